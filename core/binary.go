@@ -3,6 +3,7 @@ package core
 import (
 	"github.com/degdb/degdb/network"
 	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/query"
 	"github.com/spaolacci/murmur3"
 )
 
@@ -10,6 +11,14 @@ import (
 func (s *server) initBinary() error {
 	s.network.Handle("InsertTriples", s.handleInsertTriples)
 	s.network.Handle("QueryRequest", s.handleQueryRequest)
+	s.network.Handle("RetractTriples", s.handleRetractTriples)
+	s.network.Handle("BloomSync", s.handleBloomSync)
+	s.network.Handle("MerkleRequest", s.handleMerkleRequest)
+	s.network.HandlePeer(func(conn *network.Conn) {
+		if err := s.syncPeer(conn); err != nil {
+			s.Printf("ERR syncing with %s: %s", conn.PrettyID(), err)
+		}
+	})
 
 	return nil
 }
@@ -18,30 +27,90 @@ func (s *server) handleInsertTriples(conn *network.Conn, msg *protocol.Message)
 	triples := msg.GetInsertTriples().Triples
 	localKS := s.network.LocalPeer().Keyspace
 
-	var validTriples []*protocol.Triple
+	var validTriples, objTriples []*protocol.Triple
 	idHashes := make(map[string]uint64)
-	for _, triple := range triples {
-		hash, ok := idHashes[triple.Subj]
+	hashOf := func(id string) uint64 {
+		hash, ok := idHashes[id]
 		if !ok {
-			hash = murmur3.Sum64([]byte(triple.Subj))
-			idHashes[triple.Subj] = hash
+			hash = murmur3.Sum64([]byte(id))
+			idHashes[id] = hash
 		}
-		if !localKS.Includes(hash) {
-			s.Printf("ERR insert triple dropped due to keyspace %#v from %#v", triple, conn.Peer)
+		return hash
+	}
+	for _, triple := range triples {
+		subjLocal := localKS.Includes(hashOf(triple.Subj))
+		objLocal := localKS.Includes(hashOf(triple.Obj))
+		if !subjLocal && !objLocal {
+			s.Printf("ERR insert triple dropped due to keyspace %#v from %#v", triple, conn.Peer())
 			// TODO(d4l3k): Follow up on bad triple by reannouncing keyspace.
 			continue
 		}
-		validTriples = append(validTriples, triple)
+		if isReputationPred(triple.Pred) && !s.network.IsNode(triple.Author) {
+			s.Printf("ERR insert triple dropped since %s isn't a node %#v from %s", triple.Author, triple, conn.PrettyID())
+			continue
+		}
+		if err := s.verifyTriple(triple); err != nil {
+			s.Printf("ERR insert triple dropped due to signature %#v from %s: %s", triple, conn.PrettyID(), err)
+			s.recordVerificationFailure(peerSender(conn), triple.Pred)
+			continue
+		}
+		if subjLocal {
+			validTriples = append(validTriples, triple)
+		}
+		if objLocal {
+			objTriples = append(objTriples, triple)
+		}
+	}
+	// A triple the store skips is already held, either as it or as a newer
+	// value or tombstone, so the count is every valid triple unless the
+	// write failed.
+	count := len(validTriples)
+	_, _, storeErr := s.ts.Write(validTriples)
+	if storeErr != nil {
+		s.Printf("ERR storing triples: %s", storeErr)
+		count = 0
+	}
+	if _, _, err := s.objIndex.Write(objTriples); err != nil {
+		s.Printf("ERR inserting into object index: %s", err)
+	}
+
+	if !msg.ResponseRequired {
+		return
+	}
+	resp := &protocol.Message{
+		Message: &protocol.Message_InsertTriplesResponse{
+			InsertTriplesResponse: &protocol.InsertTriplesResponse{
+				Count: int32(count),
+			},
+		},
+	}
+	if storeErr != nil {
+		resp.Error = storeErr.Error()
+	}
+	if err := conn.RespondTo(msg, resp); err != nil {
+		s.Printf("ERR send InsertTriplesResponse %s", err)
 	}
-	s.ts.Insert(validTriples)
 }
 
 func (s *server) handleQueryRequest(conn *network.Conn, msg *protocol.Message) {
-	triples, err := s.ExecuteQuery(msg.GetQueryRequest())
+	req := msg.GetQueryRequest()
+	var triples []*protocol.Triple
+	var rows []*protocol.Row
+	var changes []*protocol.Change
+	var err error
+	if req.History {
+		changes, err = s.localHistory(req)
+	} else if req.Type == protocol.BASIC && query.HasVars(req.Steps) {
+		rows, triples, err = s.ExecuteQueryRows(req)
+	} else {
+		triples, err = s.ExecuteQuery(req)
+	}
 	resp := &protocol.Message{
 		Message: &protocol.Message_QueryResponse{
 			QueryResponse: &protocol.QueryResponse{
 				Triples: triples,
+				Rows:    rows,
+				Changes: changes,
 			},
 		},
 	}
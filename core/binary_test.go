@@ -0,0 +1,58 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/network"
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestHandleInsertTriplesVerifies(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	triples := testTriplesKeyspace(s.network.LocalKeyspace())
+	for _, triple := range triples {
+		if err := key.SignTriple(triple); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Tamper with the first triple and forge the author of the second.
+	triples[0].Obj = "tampered"
+	otherKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if triples[1].Author, err = otherKey.AuthorID(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn := network.NewPeerConn(&protocol.Peer{Id: "test"})
+	s.handleInsertTriples(conn, &protocol.Message{
+		Message: &protocol.Message_InsertTriples{
+			InsertTriples: &protocol.InsertTriples{
+				Triples: triples,
+			},
+		},
+	})
+
+	stored, err := s.ts.Query(&protocol.Triple{}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != len(triples)-2 {
+		t.Fatalf("stored %d triples %+v; expected %d", len(stored), stored, len(triples)-2)
+	}
+	for _, triple := range stored {
+		if err := crypto.VerifyTriple(triple); err != nil {
+			t.Errorf("stored triple %+v doesn't verify: %s", triple, err)
+		}
+	}
+}
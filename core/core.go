@@ -2,31 +2,61 @@
 package core
 
 import (
+	"container/list"
 	"fmt"
 	"log"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
 
 	"github.com/degdb/degdb/bitcoin"
 	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/merkle"
 	"github.com/degdb/degdb/network"
 	"github.com/degdb/degdb/triplestore"
 )
 
 var (
-	KeyFilePath      = "degdb-%d.key"
-	DatabaseFilePath = "degdb-%d.db"
+	KeyFilePath         = "degdb-%d.key"
+	DatabaseFilePath    = "degdb-%d.db"
+	ObjectIndexFilePath = "degdb-%d-obj.db"
+	PeerBookFilePath    = "degdb-%d.peers"
+	// StorageBackend is the triplestore backend used by new nodes. See
+	// triplestore.Backends.
+	StorageBackend = "sqlite"
 )
 
 type server struct {
 	diskAllocated int
 	port          int
 	network       *network.Server
-	ts            *triplestore.TripleStore
+	ts            triplestore.TripleStore
+	tree          *merkle.Tree
 	crypto        *crypto.PrivateKey
 
+	// objIndex holds the triples whose object hash is in the local keyspace.
+	objIndex triplestore.TripleStore
+	objTree  *merkle.Tree
+
+	// publicKeys is a cache of the author key directory holding up to
+	// MaxPublicKeys keys. publicKeyOrder has the authors from most to least
+	// recently used.
+	publicKeys     map[string]*publicKey
+	publicKeyOrder *list.List
+	publicKeysLock sync.Mutex
+
+	// reputation is the author behavior counted since it was last published.
+	reputation map[string]*Reputation
+	// reputationCache holds the reputations looked up by the trust strategy.
+	reputationCache map[string]*cachedReputation
+	reputationLock  sync.Mutex
+
+	// quit is closed when the server is stopped to end its loops.
+	quit     chan struct{}
+	stopOnce sync.Once
+
 	*log.Logger
 }
 
@@ -48,14 +78,32 @@ func newServer(port int, peers []string, diskAllocated int) (*server, error) {
 		Logger: log.New(os.Stdout,
 			color.CyanString(":%d ", port),
 			log.Flags()),
-		diskAllocated: diskAllocated,
-		port:          port,
+		diskAllocated:   diskAllocated,
+		port:            port,
+		publicKeys:      make(map[string]*publicKey),
+		publicKeyOrder:  list.New(),
+		reputation:      make(map[string]*Reputation),
+		reputationCache: make(map[string]*cachedReputation),
+		quit:            make(chan struct{}),
 	}
 
 	if err := s.init(); err != nil {
 		return nil, err
 	}
+	hasKeyspace, err := s.loadKeyspace()
+	if err != nil {
+		return nil, err
+	}
 	go s.connectPeers(peers)
+	go s.network.ReconnectLoop()
+	if !hasKeyspace {
+		go s.assignKeyspace()
+	}
+	go s.publishPublicKeyLoop()
+	go s.quotaLoop()
+	go s.repairLoop()
+	go s.syncLoop()
+	go s.publishReputationLoop()
 	return s, nil
 }
 
@@ -81,14 +129,32 @@ func (s *server) init() error {
 	}
 	s.crypto = privKey
 
-	s.Printf("Initializing triplestore...")
+	s.Printf("Initializing %s triplestore...", StorageBackend)
 	s.Printf("Max DB size = %d bytes.", s.diskAllocated)
 	dbFile := fmt.Sprintf(DatabaseFilePath, s.port)
-	ts, err := triplestore.NewTripleStore(dbFile, s.Logger)
+	ts, err := triplestore.Open(StorageBackend, dbFile, s.Logger)
+	if err != nil {
+		return err
+	}
+	s.Printf("Building Merkle tree...")
+	store, err := merkle.NewStore(ts, merkle.DefaultDepth, s.Logger)
 	if err != nil {
 		return err
 	}
-	s.ts = ts
+	s.ts = store
+	s.tree = store.Tree
+
+	s.Printf("Initializing object index...")
+	objIndex, err := triplestore.Open(StorageBackend, fmt.Sprintf(ObjectIndexFilePath, s.port), s.Logger)
+	if err != nil {
+		return err
+	}
+	objStore, err := merkle.NewObjectStore(objIndex, merkle.DefaultDepth, s.Logger)
+	if err != nil {
+		return err
+	}
+	s.objIndex = objStore
+	s.objTree = objStore.Tree
 
 	s.Printf("Initializing network...")
 	ns, err := network.NewServer(s.Logger, s.port)
@@ -96,6 +162,18 @@ func (s *server) init() error {
 		return err
 	}
 	s.network = ns
+	if err := ns.SetKey(s.crypto); err != nil {
+		return err
+	}
+	bookFile := fmt.Sprintf(PeerBookFilePath, s.port)
+	book, err := network.OpenPeerBook(bookFile)
+	if err != nil {
+		// The peers are only needed to rejoin the cluster, so a damaged peer
+		// book shouldn't stop the node from starting.
+		s.Printf("ERR loading peer book %s: %s, starting with an empty one", bookFile, err)
+		book = network.NewPeerBook(bookFile)
+	}
+	ns.SetPeerBook(book)
 
 	if err := s.initHTTP(); err != nil {
 		return err
@@ -107,7 +185,20 @@ func (s *server) init() error {
 	return nil
 }
 
-// Stop stops the server and closes all open sockets.
+// Stop stops the server and its loops and closes all open sockets.
 func (s *server) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.quit)
+	})
 	s.network.Stop()
 }
+
+// sleep waits for d and returns false if the server is stopped first.
+func (s *server) sleep(d time.Duration) bool {
+	select {
+	case <-s.quit:
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
 	"testing"
 	"time"
@@ -17,6 +18,8 @@ func launchSwarm(nodeCount int, t *testing.T) []*server {
 
 	var peers []string
 	for i := 0; i < nodeCount; i++ {
+		// The nodes all use port 0 so each needs its own files.
+		newTmpDir()
 		s, err := newServer(0, peers, diskAllocated)
 		if err != nil {
 			t.Error(err)
@@ -71,3 +74,39 @@ func TestCoreDiscovery(t *testing.T) {
 	nodes := launchSwarm(5, t)
 	defer killSwarm(nodes)
 }
+
+// TestDamagedPeerBook isn't parallel since it relies on the file paths set by
+// newTmpDir.
+func TestDamagedPeerBook(t *testing.T) {
+	newTmpDir()
+	path := fmt.Sprintf(PeerBookFilePath, 0)
+	if err := ioutil.WriteFile(path, []byte(`[{"addr": "a:1"}]garbage`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := newServer(0, nil, diskAllocated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Stop()
+}
+
+func TestStopEndsLoops(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	s.Stop()
+	// Stopping twice is harmless.
+	s.Stop()
+	done := make(chan bool)
+	go func() {
+		done <- s.sleep(time.Hour)
+	}()
+	select {
+	case slept := <-done:
+		if slept {
+			t.Errorf("sleep() = true after Stop")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("sleep() didn't return after Stop")
+	}
+}
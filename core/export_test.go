@@ -0,0 +1,99 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d4l3k/messagediff"
+	"github.com/spaolacci/murmur3"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/rdf"
+)
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	triples := testTriplesKeyspace(s.network.LocalKeyspace())
+	if err := s.signAndInsertTriples(protocol.CloneTriples(triples), s.crypto); err != nil {
+		t.Fatal(err)
+	}
+	hash := murmur3.Sum64([]byte(triples[0].Subj))
+	var firstSubj []*protocol.Triple
+	for _, triple := range triples {
+		if triple.Subj == triples[0].Subj {
+			firstSubj = append(firstSubj, triple)
+		}
+	}
+
+	base := fmt.Sprintf("http://localhost:%d/api/v1/export", s.network.Port)
+	testData := []struct {
+		path, accept, contentType string
+		want                      []*protocol.Triple
+	}{
+		{"", "", "application/n-triples", triples},
+		{"?format=turtle", "", "text/turtle", nil},
+		{"", "application/ld+json", "application/ld+json", nil},
+		{"?format=nt", "text/turtle", "application/n-triples", triples},
+		{fmt.Sprintf("?start=%d&end=%d", hash, hash+1), "", "application/n-triples", firstSubj},
+	}
+	for i, td := range testData {
+		req, err := http.NewRequest("GET", base+td.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(td.accept) > 0 {
+			req.Header.Set("Accept", td.accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != td.contentType {
+			t.Errorf("%d. GET %s Content-Type = %s; not %s", i, td.path, ct, td.contentType)
+		}
+		if td.want == nil {
+			continue
+		}
+		var out []*protocol.Triple
+		reader := rdf.NewReader(resp.Body)
+		for {
+			triple, err := reader.Read()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, triple)
+		}
+		protocol.SortTriples(out)
+		if diff, equal := messagediff.PrettyDiff(td.want, out); !equal {
+			t.Errorf("%d. GET %s = %+v; not %+v\n%s", i, td.path, out, td.want, diff)
+		}
+	}
+
+	resp, err := http.Get(base + "?format=rdfxml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("GET ?format=rdfxml = %d; not 400", resp.StatusCode)
+	}
+	req, _ := http.NewRequest("GET", base, strings.NewReader(""))
+	req.Header.Set("Accept", "text/html")
+	if resp, err = http.DefaultClient.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 406 {
+		t.Errorf("GET Accept: text/html = %d; not 406", resp.StatusCode)
+	}
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/query/gremlin"
+)
+
+func TestGremlin(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	triples := testTriplesKeyspace(s.network.LocalKeyspace())
+	if err := s.signAndInsertTriples(protocol.CloneTriples(triples), s.crypto); err != nil {
+		t.Fatal(err)
+	}
+	var obama string
+	for _, triple := range triples {
+		if triple.Obj == "Barack Obama" {
+			obama = triple.Subj
+		}
+	}
+
+	q := fmt.Sprintf(`g.V(%q).values("/type/object/name")`, obama)
+	trips, err := s.ExecuteQuery(&protocol.QueryRequest{
+		Type:  protocol.GREMLIN,
+		Query: q,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*protocol.Triple{{Subj: obama, Pred: "/type/object/name", Obj: "Barack Obama"}}
+	trips = stripCreated(stripSigning(trips))
+	if diff, equal := messagediff.PrettyDiff(want, trips); !equal {
+		t.Errorf("s.ExecuteQuery(%q) = %+v\n%s", q, trips, diff)
+	}
+
+	base := fmt.Sprintf("http://localhost:%d/api/v1/gremlin", s.network.Port)
+	resp, err := http.Get(base + "?q=" + url.QueryEscape(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result gremlin.Result
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	if diff, equal := messagediff.PrettyDiff([]string{"Barack Obama"}, result.Values); !equal {
+		t.Errorf("GET /api/v1/gremlin?q=%s = %+v\n%s", q, result.Values, diff)
+	}
+
+	resp, err = http.Get(base + "?q=" + url.QueryEscape("g.V().out()"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("GET unrooted traversal = %d; not 400", resp.StatusCode)
+	}
+}
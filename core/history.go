@@ -0,0 +1,72 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/spaolacci/murmur3"
+
+	"github.com/degdb/degdb/network"
+	"github.com/degdb/degdb/protocol"
+)
+
+// handleHistory returns the assertions and retractions of the triples with the
+// "subj" parameter, oldest first.
+func (s *server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	subj := r.FormValue("subj")
+	if len(subj) == 0 {
+		http.Error(w, "missing subj parameter", 400)
+		return
+	}
+	changes, err := s.History(subj)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	json.NewEncoder(w).Encode(changes)
+}
+
+// History returns the changes to the triples with the subject from the local
+// store if it owns the subject or from a peer that does.
+func (s *server) History(subj string) ([]*protocol.Change, error) {
+	req := &protocol.QueryRequest{
+		Type:    protocol.BASIC,
+		Sharded: true,
+		History: true,
+		Steps: []*protocol.ArrayOp{{
+			Triples: []*protocol.Triple{{Subj: subj}},
+		}},
+	}
+	hash := murmur3.Sum64([]byte(subj))
+	if s.network.LocalKeyspace().Includes(hash) {
+		return s.localHistory(req)
+	}
+	var err error = network.ErrNoRecipients
+	for _, conn := range s.network.Replicas(hash) {
+		var msg *protocol.Message
+		msg, err = conn.Request(&protocol.Message{
+			Message: &protocol.Message_QueryRequest{
+				QueryRequest: req,
+			}})
+		if err != nil {
+			s.Printf("ERR history from %s: %s", conn.PrettyID(), err)
+			continue
+		}
+		if len(msg.Error) > 0 {
+			err = errors.New(msg.Error)
+			continue
+		}
+		return msg.GetQueryResponse().Changes, nil
+	}
+	return nil, err
+}
+
+// localHistory returns the changes in the local store to the triples matching
+// the first triple of a history request.
+func (s *server) localHistory(req *protocol.QueryRequest) ([]*protocol.Change, error) {
+	if len(req.Steps) == 0 || len(req.Steps[0].Triples) == 0 {
+		return nil, errors.New("history request needs a triple")
+	}
+	return s.ts.History(req.Steps[0].Triples[0])
+}
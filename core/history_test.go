@@ -0,0 +1,95 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/network"
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+	s2 := testServer(t)
+	defer s2.Stop()
+	go s.network.Listen()
+	go s2.network.Listen()
+	s.network.ListenWait()
+	s2.network.ListenWait()
+
+	keyspace := s.network.LocalKeyspace()
+	s2.network.SetKeyspace(&protocol.Keyspace{Start: keyspace.End, End: keyspace.Start})
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := testTriplesKeyspace(keyspace)[0]
+	old.Created = 100
+	current := *old
+	current.Obj = "updated"
+	current.Created = 200
+	for _, triple := range []*protocol.Triple{old, &current} {
+		if err := key.SignTriple(triple); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.ts.Write([]*protocol.Triple{old})
+	s.ts.Write([]*protocol.Triple{&current})
+
+	asOf := func(unix int64) []*protocol.Triple {
+		triples, err := s.ExecuteQuery(&protocol.QueryRequest{
+			Type:  protocol.BASIC,
+			AsOf:  unix,
+			Steps: []*protocol.ArrayOp{{Triples: []*protocol.Triple{{Subj: old.Subj}}}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return triples
+	}
+	cases := []struct {
+		asOf int64
+		want []*protocol.Triple
+	}{
+		{50, nil},
+		{150, []*protocol.Triple{old}},
+		{250, []*protocol.Triple{old, &current}},
+	}
+	for i, c := range cases {
+		out := asOf(c.asOf)
+		protocol.SortTriples(out)
+		protocol.SortTriples(c.want)
+		if diff, ok := messagediff.PrettyDiff(c.want, out); !ok {
+			t.Errorf("%d. ExecuteQuery(AsOf: %d) = %#v; diff %s", i, c.asOf, out, diff)
+		}
+	}
+
+	if err := s2.network.Connect(fmt.Sprintf("localhost:%d", s.network.Port)); err != nil {
+		t.Fatal(err)
+	}
+	var conn *network.Conn
+	for i := 0; i < retryCount && conn == nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		conn = s2.network.Peers[s.network.LocalID()]
+	}
+	if conn == nil {
+		t.Fatal("peers didn't connect")
+	}
+
+	changes, err := s2.History(old.Subj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*protocol.Change{{Triple: old}, {Triple: &current}}
+	if diff, ok := messagediff.PrettyDiff(want, changes); !ok {
+		t.Errorf("History(%q) = %#v; diff %s", old.Subj, changes, diff)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/GeertJohan/go.rice"
@@ -15,6 +17,11 @@ import (
 	"github.com/degdb/degdb/network/customhttp"
 	"github.com/degdb/degdb/protocol"
 	"github.com/degdb/degdb/query"
+	"github.com/degdb/degdb/query/gremlin"
+	"github.com/degdb/degdb/query/mql"
+	"github.com/degdb/degdb/query/sparql"
+	"github.com/degdb/degdb/rdf"
+	"github.com/degdb/degdb/triplestore"
 	"github.com/spaolacci/murmur3"
 )
 
@@ -29,15 +36,29 @@ func (s *server) initHTTP() error {
 	// HTTP endpoints
 	s.network.HTTPHandleFunc("/api/v1/info", s.handleInfo)
 	s.network.HTTPHandleFunc("/api/v1/insert", s.handleInsertTriple)
+	s.network.HTTPHandleFunc("/api/v1/retract", s.handleRetract)
 	s.network.HTTPHandleFunc("/api/v1/query", s.handleQuery)
+	s.network.HTTPHandleFunc("/api/v1/history", s.handleHistory)
+	s.network.HTTPHandleFunc("/api/v1/reputation", s.handleReputation)
+	s.network.HTTPHandleFunc("/api/v1/sparql", s.handleSPARQL)
+	s.network.HTTPHandleFunc("/api/v1/gremlin", s.handleGremlin)
+	s.network.HTTPHandleFunc("/api/v1/mql", s.handleMQL)
 	s.network.HTTPHandleFunc("/api/v1/triples", s.handleTriples)
 	s.network.HTTPHandleFunc("/api/v1/peers", s.handlePeers)
 	s.network.HTTPHandleFunc("/api/v1/myip", s.handleMyIP)
+	s.network.HTTPHandleFunc("/api/v1/publickey", s.handlePublicKey)
+	s.network.HTTPHandleFunc("/api/v1/import", s.handleImport)
+	s.network.HTTPHandleFunc("/api/v1/export", s.handleExport)
+	s.network.HTTPHandleFunc("/api/v2/insert", s.handleInsertSignedTriples)
+	s.network.HTTPHandleFunc("/api/v2/retract", s.handleRetractSigned)
 
 	return nil
 }
 
-// handleInsertTriple inserts set of triples into the graph.
+// handleInsertTriple inserts set of triples into the graph. Unsigned triples
+// are signed with the server's key and triples already signed by the client
+// are kept if their signature is valid, see handleInsertSignedTriples for
+// inserting only triples signed by the client.
 func (s *server) handleInsertTriple(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		http.Error(w, "endpoint needs POST", 400)
@@ -50,48 +71,176 @@ func (s *server) handleInsertTriple(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO(d4l3k): This should ideally be refactored and force the client to presign the triple.
-	if err := s.signAndInsertTriples(triples, s.crypto); err != nil {
+	// Triples that claim an author must carry a valid signature from it and
+	// are kept as signed. The rest are signed by the server.
+	var signed, unsigned []*protocol.Triple
+	for i, triple := range triples {
+		if isReputationPred(triple.Pred) {
+			http.Error(w, fmt.Sprintf("triple %d: %s", i, ErrReputationPred), 400)
+			return
+		}
+		if len(triple.Author) == 0 && len(triple.Sig) == 0 {
+			unsigned = append(unsigned, triple)
+			continue
+		}
+		signed = append(signed, triple)
+		if err := s.verifyTriple(triple); err != nil {
+			s.Printf("ERR insert triple rejected due to signature %#v from %s: %s", triple, r.RemoteAddr, err)
+			s.recordVerificationFailure(httpSender(r), triple.Pred)
+			http.Error(w, fmt.Sprintf("triple %d: %s", i, err), 400)
+			return
+		}
+	}
+
+	if err := signTriples(unsigned, s.crypto); err != nil {
 		http.Error(w, err.Error(), 500)
+		return
+	}
+	losers := s.conflictLosers(triples)
+	if err := s.insertTriples(triples); err != nil {
+		http.Error(w, err.Error(), 500)
+		return
 	}
+	s.recordAssertions(signed)
+	s.recordConflicts(losers)
 
 	w.Write([]byte(fmt.Sprintf("Inserted %d triples.", len(triples))))
 }
 
+// handleInsertSignedTriples inserts a set of triples that have already been
+// signed by the client. The Author and Created fields are kept as is and the
+// request is rejected if any of the signatures are invalid.
+func (s *server) handleInsertSignedTriples(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "endpoint needs POST", 400)
+		return
+	}
+	var triples []*protocol.Triple
+	if err := json.NewDecoder(r.Body).Decode(&triples); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	for i, triple := range triples {
+		if isReputationPred(triple.Pred) {
+			http.Error(w, fmt.Sprintf("triple %d: %s", i, ErrReputationPred), 400)
+			return
+		}
+		if err := s.verifyTriple(triple); err != nil {
+			s.Printf("ERR insert triple rejected due to signature %#v from %s: %s", triple, r.RemoteAddr, err)
+			s.recordVerificationFailure(httpSender(r), triple.Pred)
+			http.Error(w, fmt.Sprintf("triple %d: %s", i, err), 400)
+			return
+		}
+	}
+
+	losers := s.conflictLosers(triples)
+	if err := s.insertTriples(triples); err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	s.recordAssertions(triples)
+	s.recordConflicts(losers)
+
+	w.Write([]byte(fmt.Sprintf("Inserted %d triples.", len(triples))))
+}
+
+// handleImport streams N-Triples or N-Quads from the request body into the
+// graph. The triples are signed with the server's key. The response is a stream
+// of JSON lines with the number of triples inserted and errors encountered so
+// far. The last line is done and has the error that stopped the import, if any.
+func (s *server) handleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "endpoint needs POST", 400)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	sent := false
+	send := func(progress importProgress) {
+		if !sent && len(progress.Error) > 0 {
+			w.WriteHeader(500)
+		}
+		sent = true
+		if err := enc.Encode(progress); err != nil {
+			s.Printf("ERR import writing progress to %s: %s", r.RemoteAddr, err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	start := time.Now()
+	lastLog := start
+	stats, err := s.importTriples(r.Body, func(stats importStats) {
+		if time.Since(lastLog) < ImportProgressInterval {
+			return
+		}
+		lastLog = time.Now()
+		s.Printf("Import from %s: %d triples, %d errors, %s", r.RemoteAddr, stats.Triples, stats.Errors, time.Since(start))
+		send(importProgress{importStats: stats})
+	})
+	s.Printf("Import from %s finished: %d triples, %d errors, %s", r.RemoteAddr, stats.Triples, stats.Errors, time.Since(start))
+	progress := importProgress{importStats: stats, Done: true}
+	if err != nil {
+		progress.Error = err.Error()
+	}
+	send(progress)
+}
+
 // signAndInsertTriples signs a set of triples with the server's key and then inserts them into the graph.
 func (s *server) signAndInsertTriples(triples []*protocol.Triple, key *crypto.PrivateKey) error {
-	hashes := make(map[uint64][]*protocol.Triple)
+	if err := signTriples(triples, key); err != nil {
+		return err
+	}
+	return s.insertTriples(triples)
+}
+
+// signTriples sets the creation time of the triples and signs them with the
+// key.
+func signTriples(triples []*protocol.Triple, key *crypto.PrivateKey) error {
 	unix := time.Now().Unix()
 	for _, triple := range triples {
+		triple.Created = unix
 		if err := key.SignTriple(triple); err != nil {
 			return err
 		}
-		triple.Created = unix
+	}
+	return nil
+}
+
+// insertTriples sends a set of signed triples to the peers that have them in
+// their keyspace and inserts them locally if they belong to this node. It
+// fails unless ReplicationFactor replicas acknowledge each triple.
+func (s *server) insertTriples(triples []*protocol.Triple) error {
+	hashes := make(map[uint64][]*protocol.Triple)
+	objHashes := make(map[uint64][]*protocol.Triple)
+	for _, triple := range triples {
 		hash := murmur3.Sum64([]byte(triple.Subj))
 		hashes[hash] = append(hashes[hash], triple)
+		objHash := murmur3.Sum64([]byte(triple.Obj))
+		objHashes[objHash] = append(objHashes[objHash], triple)
 	}
 
 	for hash, triples := range hashes {
-		msg := &protocol.Message{
-			Message: &protocol.Message_InsertTriples{
-				InsertTriples: &protocol.InsertTriples{
-					Triples: triples,
-				}},
-			Gossip: true,
-		}
-		currentKeyspace := s.network.LocalPeer().Keyspace.Includes(hash)
-		if err := s.network.Broadcast(&hash, msg); currentKeyspace && err == network.ErrNoRecipients {
-		} else if err != nil {
+		if err := s.replicateTriples(hash, triples); err != nil {
 			return err
 		}
-		if currentKeyspace {
-			s.ts.Insert(triples)
-		}
+	}
+	// The object index is replicated to the peers owning the object hashes so
+	// reverse lookups can be routed.
+	for hash, triples := range objHashes {
+		s.replicateObjectIndex(hash, triples)
 	}
 	return nil
 }
 
-// handleQuery executes a query against the graph.
+// handleQuery executes a query against the graph. Queries with variables
+// return the binding rows, projected to the comma separated "vars" parameter
+// if set. The "as_of" parameter queries the graph as it was at a UNIX time and
+// the "resolve" parameter ranks conflicting objects with a resolution strategy.
 func (s *server) handleQuery(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
@@ -105,20 +254,111 @@ func (s *server) handleQuery(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), 400)
 		return
 	}
-	query := &protocol.QueryRequest{
+	req := &protocol.QueryRequest{
 		Type: protocol.BASIC,
 		Steps: []*protocol.ArrayOp{{
 			Triples: triple,
 		}},
 	}
-	triples, err := s.ExecuteQuery(query)
+	if vars := r.FormValue("vars"); len(vars) > 0 {
+		req.Vars = strings.Split(vars, ",")
+	}
+	if asOf := r.FormValue("as_of"); len(asOf) > 0 {
+		if req.AsOf, err = strconv.ParseInt(asOf, 10, 64); err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+	}
+	if len(req.Vars) > 0 || query.HasVars(req.Steps) {
+		rows, _, err := s.ExecuteQueryRows(req)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		json.NewEncoder(w).Encode(rows)
+		return
+	}
+	triples, err := s.ExecuteQuery(req)
 	if err != nil {
 		http.Error(w, err.Error(), 400)
 		return
 	}
+	if name := r.FormValue("resolve"); len(name) > 0 {
+		strategy, err := query.GetStrategy(name, s.trust)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		json.NewEncoder(w).Encode(query.Resolve(triples, strategy))
+		return
+	}
 	json.NewEncoder(w).Encode(triples)
 }
 
+// handleSPARQL executes a SPARQL SELECT query and returns the SPARQL JSON
+// results. The query is read from the "query" parameter or from the body of an
+// application/sparql-query POST.
+func (s *server) handleSPARQL(w http.ResponseWriter, r *http.Request) {
+	q := r.FormValue("query")
+	if r.Method == "POST" && strings.HasPrefix(r.Header.Get("Content-Type"), "application/sparql-query") {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		q = string(body)
+	}
+	s.Printf("SPARQL query: %s", q)
+	parsed, err := sparql.Parse(q)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	results, err := parsed.Execute(s.ExecuteQuery)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	w.Header().Set("Content-Type", "application/sparql-results+json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// handleGremlin executes the Gremlin traversal in the "q" parameter and returns
+// the values it ends on and the triples used to reach them.
+func (s *server) handleGremlin(w http.ResponseWriter, r *http.Request) {
+	q := r.FormValue("q")
+	s.Printf("Gremlin query: %s", q)
+	traversal, err := gremlin.Parse(q)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	result, err := traversal.Execute(s.ExecuteQuery)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleMQL executes the MQL read query in the "query" parameter and returns
+// the filled in template as {"result": ...}.
+func (s *server) handleMQL(w http.ResponseWriter, r *http.Request) {
+	q := r.FormValue("query")
+	s.Printf("MQL query: %s", q)
+	mqlQuery, err := mql.Parse(q)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	result, err := mqlQuery.Execute(s.ExecuteQuery)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
 // handleTriples is a debug method to dump the triple DB into a JSON blob.
 func (s *server) handleTriples(w http.ResponseWriter, r *http.Request) {
 	triples, err := s.ts.Query(&protocol.Triple{}, -1)
@@ -129,11 +369,80 @@ func (s *server) handleTriples(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(triples)
 }
 
+// handleExport streams the triples stored on this node in an RDF format. The
+// format is picked by the "format" parameter or the Accept header and defaults
+// to N-Triples. The optional "start" and "end" parameters restrict the export
+// to subjects in that keyspace.
+func (s *server) handleExport(w http.ResponseWriter, r *http.Request) {
+	format, ok := rdf.ParseFormat(r.FormValue("format"))
+	if !ok && len(r.FormValue("format")) > 0 {
+		http.Error(w, "unknown format "+r.FormValue("format"), 400)
+		return
+	} else if !ok {
+		accept := r.Header.Get("Accept")
+		if format, ok = rdf.Negotiate(accept); !ok && len(accept) > 0 {
+			http.Error(w, "no supported format in Accept header", 406)
+			return
+		}
+	}
+	keyspace, err := parseKeyspace(r)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	writer := rdf.NewWriter(w, format)
+	results, errs := s.ts.EachTripleBatch(triplestore.DefaultTripleBatchSize)
+	var werr error
+	for triples := range results {
+		// Keep draining the results on error so the batch goroutine exits.
+		for _, triple := range triples {
+			if werr != nil {
+				break
+			}
+			if keyspace != nil && !keyspace.Includes(murmur3.Sum64([]byte(triple.Subj))) {
+				continue
+			}
+			werr = writer.Write(triple)
+		}
+	}
+	for err := range errs {
+		s.Printf("ERR export: %s", err)
+		return
+	}
+	if werr == nil {
+		werr = writer.Close()
+	}
+	if werr != nil {
+		s.Printf("ERR export: %s", werr)
+	}
+}
+
+// parseKeyspace reads an optional keyspace from the "start" and "end"
+// parameters.
+func parseKeyspace(r *http.Request) (*protocol.Keyspace, error) {
+	start, end := r.FormValue("start"), r.FormValue("end")
+	if len(start) == 0 && len(end) == 0 {
+		return nil, nil
+	}
+	var keyspace protocol.Keyspace
+	var err error
+	if keyspace.Start, err = strconv.ParseUint(start, 10, 64); err != nil {
+		return nil, err
+	}
+	if keyspace.End, err = strconv.ParseUint(end, 10, 64); err != nil {
+		return nil, err
+	}
+	return &keyspace, nil
+}
+
 // handlePeers is a debug method to dump the current known peers.
 func (s *server) handlePeers(w http.ResponseWriter, r *http.Request) {
-	peers := make([]*protocol.Peer, 0, len(s.network.Peers))
-	for _, peer := range s.network.Peers {
-		peers = append(peers, peer.Peer)
+	conns := s.network.Conns()
+	peers := make([]*protocol.Peer, 0, len(conns))
+	for _, conn := range conns {
+		peers = append(peers, conn.Peer())
 	}
 	json.NewEncoder(w).Encode(peers)
 }
@@ -148,6 +457,26 @@ func (s *server) handleMyIP(w http.ResponseWriter, r *http.Request) {
 	addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr)
 	if err != nil {
 		http.Error(w, err.Error(), 500)
+		return
 	}
 	w.Write([]byte(addr.IP.String()))
 }
+
+// handlePublicKey returns the signed public key triple for the author in the
+// "author" parameter.
+func (s *server) handlePublicKey(w http.ResponseWriter, r *http.Request) {
+	author := r.FormValue("author")
+	if len(author) == 0 {
+		http.Error(w, "missing author", 400)
+		return
+	}
+	triple, _, err := s.lookupPublicKey(author)
+	if err == ErrPublicKeyNotFound {
+		http.Error(w, err.Error(), 404)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	json.NewEncoder(w).Encode(triple)
+}
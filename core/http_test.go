@@ -8,17 +8,22 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/d4l3k/messagediff"
+	"github.com/degdb/degdb/crypto"
 	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/query"
 	"github.com/spaolacci/murmur3"
 )
 
 func init() {
+	// Tests rely on the default keyspace unless they assign one.
+	KeyspaceAssignDelay = time.Hour
 	newTmpDir()
 	protocol.SortTriples(testTriples)
 }
@@ -30,6 +35,9 @@ func newTmpDir() {
 	}
 	KeyFilePath = dir + "/degdb-%d.key"
 	DatabaseFilePath = dir + "/degdb-%d.db"
+	ObjectIndexFilePath = dir + "/degdb-%d-obj.db"
+	KeyspaceFilePath = dir + "/degdb-%d.keyspace"
+	PeerBookFilePath = dir + "/degdb-%d.peers"
 }
 
 func testServer(t *testing.T) *server {
@@ -207,3 +215,297 @@ func stripCreated(triples []*protocol.Triple) []*protocol.Triple {
 	}
 	return triples
 }
+
+func TestInsertForgedTriple(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+
+	time.Sleep(10 * time.Millisecond)
+	base := fmt.Sprintf("http://localhost:%d", s.network.Port)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	triples := testTriplesKeyspace(s.network.LocalKeyspace())
+	if err := key.SignTriple(triples[0]); err != nil {
+		t.Fatal(err)
+	}
+	triples[0].Obj = "tampered"
+
+	body, err := json.Marshal(triples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(base+"/api/v1/insert", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 400 {
+		out, _ := ioutil.ReadAll(resp.Body)
+		t.Errorf("http.Post(/api/v1/insert) = %d %s; not 400", resp.StatusCode, out)
+	}
+
+	stored, err := s.ts.Query(&protocol.Triple{}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 0 {
+		t.Errorf("forged insert stored triples %+v", stored)
+	}
+
+	// The failure is charged to the client, not to the author it claimed.
+	s.reputationLock.Lock()
+	defer s.reputationLock.Unlock()
+	if r, ok := s.reputation[triples[0].Author]; ok {
+		t.Errorf("claimed author reputation = %+v; expected none", r)
+	}
+	if r, ok := s.reputation["127.0.0.1"]; !ok || r.VerificationFailures != 1 {
+		t.Errorf("client reputation = %+v; expected 1 verification failure", r)
+	}
+}
+
+func TestInsertPresignedTriple(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+
+	time.Sleep(10 * time.Millisecond)
+	base := fmt.Sprintf("http://localhost:%d", s.network.Port)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	triples := testTriplesKeyspace(s.network.LocalKeyspace())[:2]
+	triples[0].Created = 100
+	if err := key.SignTriple(triples[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := json.Marshal(triples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(base+"/api/v1/insert", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 200 {
+		out, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("http.Post(/api/v1/insert) = %d %s; not 200", resp.StatusCode, out)
+	}
+
+	own, err := s.crypto.AuthorID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The signed triple keeps its author and the other is signed by the node.
+	wantAuthors := []string{triples[0].Author, own}
+	for i, triple := range triples {
+		stored, err := s.ts.Query(&protocol.Triple{Subj: triple.Subj, Pred: triple.Pred, Obj: triple.Obj}, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stored) != 1 || stored[0].Author != wantAuthors[i] {
+			t.Errorf("%d. stored %+v; expected author %s", i, stored, wantAuthors[i])
+		}
+	}
+	stored, err := s.ts.Query(&protocol.Triple{Author: triples[0].Author}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 1 || stored[0].Sig != triples[0].Sig || stored[0].Created != 100 {
+		t.Errorf("stored %+v; not the signed triple %+v", stored, triples[0])
+	}
+
+	// Only the client signed triple counts as an assertion.
+	s.reputationLock.Lock()
+	defer s.reputationLock.Unlock()
+	if r, ok := s.reputation[triples[0].Author]; !ok || r.Assertions != 1 {
+		t.Errorf("author reputation = %+v; expected 1 assertion", r)
+	}
+	if r, ok := s.reputation[own]; ok {
+		t.Errorf("node reputation = %+v; expected none", r)
+	}
+}
+
+func TestInsertSignedTriples(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+
+	time.Sleep(10 * time.Millisecond)
+	base := fmt.Sprintf("http://localhost:%d", s.network.Port)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	author, err := key.AuthorID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	triples := testTriplesKeyspace(s.network.LocalKeyspace())
+	for i, triple := range triples {
+		triple.Created = int64(1445000000 + i)
+		if err := key.SignTriple(triple); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	body, err := json.Marshal(triples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(base+"/api/v2/insert", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		t.Fatalf("http.Post(/api/v2/insert) = %d %s", resp.StatusCode, out)
+	}
+
+	stored, err := s.ts.Query(&protocol.Triple{}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	protocol.SortTriples(stored)
+	if diff, equal := messagediff.PrettyDiff(triples, stored); !equal {
+		t.Errorf("stored triples = %+v; not %+v\n%s", stored, triples, diff)
+	}
+	for _, triple := range stored {
+		if triple.Author != author {
+			t.Errorf("stored triple author = %s; not %s", triple.Author, author)
+		}
+	}
+
+	// Unsigned triples are rejected.
+	body, err = json.Marshal([]*protocol.Triple{{Subj: "foo", Pred: "bar", Obj: "baz"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = http.Post(base+"/api/v2/insert", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("http.Post(/api/v2/insert) unsigned = %d; not 400", resp.StatusCode)
+	}
+}
+
+func TestQueryResolve(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+
+	time.Sleep(10 * time.Millisecond)
+	base := fmt.Sprintf("http://localhost:%d", s.network.Port)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	triple := testTriplesKeyspace(s.network.LocalKeyspace())[0]
+	own := *triple
+	own.Created = 100
+	if err := s.crypto.SignTriple(&own); err != nil {
+		t.Fatal(err)
+	}
+	other := *triple
+	other.Obj = "conflicting"
+	other.Created = 200
+	if err := key.SignTriple(&other); err != nil {
+		t.Fatal(err)
+	}
+	s.ts.Write([]*protocol.Triple{&own, &other})
+
+	q := url.Values{}
+	q.Set("q", fmt.Sprintf(`[{"subj": %q, "pred": %q}]`, triple.Subj, triple.Pred))
+	testData := []struct {
+		strategy string
+		want     string
+	}{
+		{"latest", other.Obj},
+		{"trust", own.Obj},
+	}
+	for i, td := range testData {
+		q.Set("resolve", td.strategy)
+		resp, err := http.Get(base + "/api/v1/query?" + q.Encode())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var results []*query.Result
+		if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 2 || results[0].Obj != td.want || results[0].Confidence <= results[1].Confidence {
+			t.Errorf("%d. /api/v1/query?resolve=%s = %+v; expected %q first", i, td.strategy, results, td.want)
+		}
+	}
+
+	q.Set("resolve", "foo")
+	resp, err := http.Get(base + "/api/v1/query?" + q.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("/api/v1/query?resolve=foo = %d; not 400", resp.StatusCode)
+	}
+}
+
+func TestQueryResolveMajority(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+
+	time.Sleep(10 * time.Millisecond)
+	base := fmt.Sprintf("http://localhost:%d", s.network.Port)
+
+	triple := testTriplesKeyspace(s.network.LocalKeyspace())[0]
+	var triples []*protocol.Triple
+	// Two authors agree on the object and one disagrees.
+	for i, obj := range []string{"agreed", "agreed", "disputed"} {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertion := *triple
+		assertion.Obj = obj
+		assertion.Created = int64(100 + i)
+		if err := key.SignTriple(&assertion); err != nil {
+			t.Fatal(err)
+		}
+		triples = append(triples, &assertion)
+	}
+	if written, _, err := s.ts.Write(triples); err != nil || len(written) != len(triples) {
+		t.Fatalf("Write(%+v) = %d, %v; not %d", triples, len(written), err, len(triples))
+	}
+
+	q := url.Values{}
+	q.Set("q", fmt.Sprintf(`[{"subj": %q, "pred": %q}]`, triple.Subj, triple.Pred))
+	q.Set("resolve", "majority")
+	resp, err := http.Get(base + "/api/v1/query?" + q.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []*query.Result
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Obj != "agreed" || results[0].Confidence != 2.0/3 || results[1].Confidence != 1.0/3 {
+		t.Errorf("/api/v1/query?resolve=majority = %+v; expected agreed with 2/3 of the confidence", results)
+	}
+}
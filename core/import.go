@@ -0,0 +1,86 @@
+package core
+
+import (
+	"io"
+	"time"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/rdf"
+	"github.com/degdb/degdb/triplestore"
+)
+
+var (
+	// ImportBatchSize is the number of triples that are signed and inserted at
+	// a time when importing.
+	ImportBatchSize = triplestore.DefaultTripleBatchSize
+
+	// ImportProgressInterval is how often the progress of an import is logged
+	// and sent to the client.
+	ImportProgressInterval = 5 * time.Second
+)
+
+// importStats tracks the progress of an import.
+type importStats struct {
+	// Triples is the number of triples that were inserted.
+	Triples int `json:"triples"`
+	// Errors is the number of statements that failed to parse, were rejected or
+	// failed to insert.
+	Errors int `json:"errors"`
+}
+
+// importProgress is a line of the import response. The last line is done and
+// has the error that stopped the import, if any.
+type importProgress struct {
+	importStats
+	Done  bool   `json:"done,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// importTriples reads N-Triples or N-Quads from r, signs them with the server's
+// key in batches and routes them into the graph. progress is called after each
+// batch.
+func (s *server) importTriples(r io.Reader, progress func(importStats)) (importStats, error) {
+	var stats importStats
+	reader := rdf.NewReader(r)
+	batch := make([]*protocol.Triple, 0, ImportBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.signAndInsertTriples(batch, s.crypto); err != nil {
+			s.Printf("ERR import inserting batch of %d triples: %s", len(batch), err)
+			stats.Errors += len(batch)
+		} else {
+			stats.Triples += len(batch)
+		}
+		batch = make([]*protocol.Triple, 0, ImportBatchSize)
+		if progress != nil {
+			progress(stats)
+		}
+	}
+
+	for {
+		triple, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if perr, ok := err.(*rdf.ParseError); ok {
+			s.Printf("ERR import %s", perr)
+			stats.Errors++
+			continue
+		} else if err != nil {
+			flush()
+			return stats, err
+		}
+		if isReputationPred(triple.Pred) {
+			s.Printf("ERR import %s: %s", triple.Pred, ErrReputationPred)
+			stats.Errors++
+			continue
+		}
+		batch = append(batch, triple)
+		if len(batch) >= ImportBatchSize {
+			flush()
+		}
+	}
+	flush()
+	return stats, nil
+}
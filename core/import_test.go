@@ -0,0 +1,128 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	triples := testTriplesKeyspace(s.network.LocalKeyspace())
+	triples[0].Lang = "en"
+	var body bytes.Buffer
+	for i, triple := range triples {
+		if i == 1 {
+			body.WriteString("<broken> .\n")
+		}
+		obj := fmt.Sprintf("%q", triple.Obj)
+		if len(triple.Lang) > 0 {
+			obj += "@" + triple.Lang
+		}
+		fmt.Fprintf(&body, "<%s> <%s> %s <http://example.com/graph> .\n", triple.Subj, triple.Pred, obj)
+	}
+
+	url := fmt.Sprintf("http://localhost:%d/api/v1/import", s.network.Port)
+	resp, err := http.Post(url, "application/n-quads", &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	progress := lastImportProgress(t, resp.Body)
+	want := importProgress{importStats: importStats{Triples: len(triples), Errors: 1}, Done: true}
+	if progress != want {
+		t.Errorf("POST /api/v1/import = %+v; not %+v", progress, want)
+	}
+
+	stored, err := s.ts.Query(&protocol.Triple{}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, triple := range stored {
+		if err := crypto.VerifyTriple(triple); err != nil {
+			t.Errorf("imported triple %+v isn't signed: %s", triple, err)
+		}
+	}
+	stored = stripCreated(stripSigning(stored))
+	protocol.SortTriples(stored)
+	if diff, equal := messagediff.PrettyDiff(triples, stored); !equal {
+		t.Errorf("imported triples = %+v; not %+v\n%s", stored, triples, diff)
+	}
+}
+
+// lastImportProgress reads the lines of an import response and returns the
+// last one.
+func lastImportProgress(t *testing.T, r io.Reader) importProgress {
+	var progress importProgress
+	dec := json.NewDecoder(r)
+	for {
+		var line importProgress
+		if err := dec.Decode(&line); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if progress.Done {
+			t.Errorf("import progress %+v after it was done", line)
+		}
+		progress = line
+	}
+	return progress
+}
+
+// failingReader fails after returning its data.
+type failingReader struct {
+	io.Reader
+	err error
+}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		err = r.err
+	}
+	return n, err
+}
+
+func TestImportError(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+
+	triples := testTriplesKeyspace(s.network.LocalKeyspace())
+	var body bytes.Buffer
+	for _, triple := range triples {
+		fmt.Fprintf(&body, "<%s> <%s> %q .\n", triple.Subj, triple.Pred, triple.Obj)
+	}
+	readErr := errors.New("connection reset")
+	r := httptest.NewRequest("POST", "/api/v1/import", failingReader{strings.NewReader(body.String()), readErr})
+	w := httptest.NewRecorder()
+	s.handleImport(w, r)
+
+	if w.Code != 500 {
+		t.Errorf("POST /api/v1/import status = %d; not 500", w.Code)
+	}
+	progress := lastImportProgress(t, w.Body)
+	want := importProgress{importStats: importStats{Triples: len(triples)}, Done: true, Error: readErr.Error()}
+	if progress != want {
+		t.Errorf("POST /api/v1/import = %+v; not %+v", progress, want)
+	}
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/degdb/degdb/network"
+	"github.com/degdb/degdb/protocol"
+)
+
+var (
+	KeyspaceFilePath = "degdb-%d.keyspace"
+	// KeyspaceAssignDelay is how long a node without a saved keyspace waits for
+	// its peers to connect before picking a keyspace.
+	KeyspaceAssignDelay = 2 * time.Second
+)
+
+// loadKeyspace restores the keyspace saved by a previous run. It returns false
+// if there is none.
+func (s *server) loadKeyspace() (bool, error) {
+	buf, err := ioutil.ReadFile(fmt.Sprintf(KeyspaceFilePath, s.port))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	keyspace := &protocol.Keyspace{}
+	if err := json.Unmarshal(buf, keyspace); err != nil {
+		return false, err
+	}
+	s.network.SetKeyspace(keyspace)
+	return true, nil
+}
+
+// setKeyspace changes the local keyspace and saves it for the next run.
+// network.Server.SetKeyspace announces it to the peers.
+func (s *server) setKeyspace(keyspace *protocol.Keyspace) error {
+	s.network.SetKeyspace(keyspace)
+	buf, err := json.Marshal(keyspace)
+	if err != nil {
+		return err
+	}
+	return network.WriteFileAtomic(fmt.Sprintf(KeyspaceFilePath, s.port), buf)
+}
+
+// assignKeyspace waits for the peers to connect and picks a keyspace covering
+// the gaps in theirs.
+func (s *server) assignKeyspace() {
+	if !s.sleep(KeyspaceAssignDelay) {
+		return
+	}
+	keyspace := s.network.AssignKeyspace(ReplicationFactor)
+	s.Printf("Assigned keyspace %+v", keyspace)
+	if err := s.setKeyspace(keyspace); err != nil {
+		s.Printf("ERR saving keyspace: %s", err)
+	}
+	s.syncPeers()
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// TestKeyspacePersisted isn't parallel since restarting the server relies on the
+// file paths set by testServer.
+func TestKeyspacePersisted(t *testing.T) {
+	s := testServer(t)
+	// Later servers must not restore the saved keyspace.
+	defer newTmpDir()
+	keyspace := s.network.AssignKeyspace(ReplicationFactor)
+	if !keyspace.Maxed() {
+		t.Errorf("AssignKeyspace() without peers = %+v; not the entire keyspace", keyspace)
+	}
+	want := &protocol.Keyspace{Start: 10, End: 20}
+	if err := s.setKeyspace(want); err != nil {
+		t.Fatal(err)
+	}
+	if matches, _ := filepath.Glob(fmt.Sprintf(KeyspaceFilePath, 0) + ".tmp*"); len(matches) > 0 {
+		t.Errorf("setKeyspace() left temporary files %v", matches)
+	}
+	s.Stop()
+
+	// Restarting with the same files restores the keyspace.
+	s, err := newServer(0, nil, diskAllocated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	if out := s.network.LocalKeyspace(); !reflect.DeepEqual(out, want) {
+		t.Errorf("s.network.LocalKeyspace() after restart = %+v; not %+v", out, want)
+	}
+}
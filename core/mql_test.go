@@ -0,0 +1,82 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestMQL(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	triples := testTriplesKeyspace(s.network.LocalKeyspace())
+	if err := s.signAndInsertTriples(protocol.CloneTriples(triples), s.crypto); err != nil {
+		t.Fatal(err)
+	}
+	var obama string
+	for _, triple := range triples {
+		if triple.Obj == "Barack Obama" {
+			obama = triple.Subj
+		}
+	}
+
+	q := fmt.Sprintf(`{"id": %q, "name": null, "type": []}`, obama)
+	trips, err := s.ExecuteQuery(&protocol.QueryRequest{
+		Type:  protocol.MQL,
+		Query: q,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	protocol.SortTriples(trips)
+	var wantTriples []*protocol.Triple
+	for _, triple := range triples {
+		if triple.Subj == obama {
+			wantTriples = append(wantTriples, triple)
+		}
+	}
+	trips = stripCreated(stripSigning(trips))
+	if diff, equal := messagediff.PrettyDiff(wantTriples, trips); !equal {
+		t.Errorf("s.ExecuteQuery(%q) = %+v\n%s", q, trips, diff)
+	}
+
+	base := fmt.Sprintf("http://localhost:%d/api/v1/mql", s.network.Port)
+	resp, err := http.Get(base + "?query=" + url.QueryEscape(q))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"result": map[string]interface{}{
+			"id":   obama,
+			"name": "Barack Obama",
+			"type": []interface{}{"/people/person"},
+		},
+	}
+	if diff, equal := messagediff.PrettyDiff(want, out); !equal {
+		t.Errorf("GET /api/v1/mql?query=%s = %+v\n%s", q, out, diff)
+	}
+
+	resp, err = http.Get(base + "?query=" + url.QueryEscape(`[{"name": null}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("GET unrooted query = %d; not 400", resp.StatusCode)
+	}
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"container/list"
+	"crypto/ecdsa"
+	"errors"
+	"time"
+
+	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/protocol"
+)
+
+var (
+	// PublicKeyRepublishInterval is how often a node checks that its public
+	// key is in the author key directory and republishes it if it's missing.
+	PublicKeyRepublishInterval = 10 * time.Minute
+	// MaxPublicKeys is the most public keys cached. The least recently used
+	// are dropped first.
+	MaxPublicKeys = 10000
+)
+
+var ErrPublicKeyNotFound = errors.New("no public key found for author")
+
+// publicKey is a verified entry in the author key directory.
+type publicKey struct {
+	triple *protocol.Triple
+	key    *ecdsa.PublicKey
+	// elem is the author's element in publicKeyOrder.
+	elem *list.Element
+}
+
+// publishPublicKeyLoop periodically publishes the node's public key if it's
+// missing so other nodes can resolve its author ID.
+func (s *server) publishPublicKeyLoop() {
+	// Give the initial peer connections a chance to complete.
+	if !s.sleep(time.Second) {
+		return
+	}
+	for {
+		if err := s.publishPublicKey(); err != nil {
+			s.Printf("ERR publishing public key: %s", err)
+		}
+		if !s.sleep(PublicKeyRepublishInterval) {
+			return
+		}
+	}
+}
+
+// publishPublicKey inserts the node's public key triple into the graph unless
+// its owners already hold it. Each insert adds a row to the history, so a
+// stored key isn't published again.
+func (s *server) publishPublicKey() error {
+	triple, err := s.crypto.PublicKeyTriple()
+	if err != nil {
+		return err
+	}
+	stored, err := s.ExecuteQuery(&protocol.QueryRequest{
+		Type: protocol.BASIC,
+		Steps: []*protocol.ArrayOp{{
+			Triples: []*protocol.Triple{{
+				Subj:   triple.Subj,
+				Pred:   triple.Pred,
+				Obj:    triple.Obj,
+				Author: triple.Subj,
+			}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if len(stored) > 0 {
+		return nil
+	}
+	return s.signAndInsertTriples([]*protocol.Triple{triple}, s.crypto)
+}
+
+// verifyTriple checks the signature on a triple. If the author's public key is
+// already known it's used directly, otherwise the key is recovered from the
+// signature. Valid public key triples are added to the key cache.
+func (s *server) verifyTriple(triple *protocol.Triple) error {
+	entry, ok := s.cachedPublicKey(triple.Author)
+
+	var err error
+	if ok && triple.Pred != crypto.PublicKeyPred {
+		err = crypto.VerifyTripleWithKey(triple, entry.key)
+	} else {
+		err = crypto.VerifyTriple(triple)
+	}
+	if err != nil {
+		return err
+	}
+	if triple.Pred == crypto.PublicKeyPred {
+		s.cachePublicKey(triple)
+	}
+	return nil
+}
+
+// cachedPublicKey returns the cached public key of an author and marks it as
+// recently used.
+func (s *server) cachedPublicKey(author string) (*publicKey, bool) {
+	s.publicKeysLock.Lock()
+	defer s.publicKeysLock.Unlock()
+	entry, ok := s.publicKeys[author]
+	if ok {
+		s.publicKeyOrder.MoveToFront(entry.elem)
+	}
+	return entry, ok
+}
+
+// cachePublicKey adds a verified public key triple to the key cache, dropping
+// the least recently used keys past MaxPublicKeys.
+func (s *server) cachePublicKey(triple *protocol.Triple) {
+	key, err := crypto.ParsePublicKey(triple.Obj)
+	if err != nil {
+		return
+	}
+	s.publicKeysLock.Lock()
+	defer s.publicKeysLock.Unlock()
+	if entry, ok := s.publicKeys[triple.Subj]; ok {
+		s.publicKeyOrder.Remove(entry.elem)
+	}
+	s.publicKeys[triple.Subj] = &publicKey{
+		triple: triple,
+		key:    key,
+		elem:   s.publicKeyOrder.PushFront(triple.Subj),
+	}
+	for s.publicKeyOrder.Len() > MaxPublicKeys {
+		oldest := s.publicKeyOrder.Back()
+		s.publicKeyOrder.Remove(oldest)
+		delete(s.publicKeys, oldest.Value.(string))
+	}
+}
+
+// lookupPublicKey returns the verified public key triple for an author. The
+// local cache is checked first and then the cluster is queried.
+func (s *server) lookupPublicKey(author string) (*protocol.Triple, *ecdsa.PublicKey, error) {
+	if entry, ok := s.cachedPublicKey(author); ok {
+		return entry.triple, entry.key, nil
+	}
+
+	triples, err := s.ExecuteQuery(&protocol.QueryRequest{
+		Type: protocol.BASIC,
+		Steps: []*protocol.ArrayOp{{
+			Triples: []*protocol.Triple{{
+				Subj: author,
+				Pred: crypto.PublicKeyPred,
+			}},
+		}},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, triple := range triples {
+		if err := crypto.VerifyTriple(triple); err != nil {
+			s.Printf("ERR invalid public key triple %#v: %s", triple, err)
+			continue
+		}
+		s.cachePublicKey(triple)
+		if entry, ok := s.cachedPublicKey(author); ok {
+			return entry.triple, entry.key, nil
+		}
+	}
+	return nil, nil, ErrPublicKeyNotFound
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/spaolacci/murmur3"
+
+	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/protocol"
+)
+
+// keyInKeyspace generates keys until one has an author ID that hashes into the
+// keyspace.
+func keyInKeyspace(t *testing.T, keyspace *protocol.Keyspace) (*crypto.PrivateKey, string) {
+	for {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		author, err := key.AuthorID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keyspace.Includes(murmur3.Sum64([]byte(author))) {
+			return key, author
+		}
+	}
+}
+
+func TestPublicKeyDirectory(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	key, author := keyInKeyspace(t, s.network.LocalKeyspace())
+	triple, err := key.PublicKeyTriple()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.signAndInsertTriples([]*protocol.Triple{triple}, key); err != nil {
+		t.Fatal(err)
+	}
+
+	_, pub, err := s.lookupPublicKey(author)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &(*ecdsa.PrivateKey)(key).PublicKey
+	if pub.X.Cmp(want.X) != 0 || pub.Y.Cmp(want.Y) != 0 {
+		t.Errorf("s.lookupPublicKey(%#v) = %+v; not %+v", author, pub, want)
+	}
+
+	signed := &protocol.Triple{Subj: "foo", Pred: "bar", Obj: "baz"}
+	if err := key.SignTriple(signed); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.verifyTriple(signed); err != nil {
+		t.Errorf("s.verifyTriple(%+v) = %+v", signed, err)
+	}
+	signed.Obj = "tampered"
+	if err := s.verifyTriple(signed); err == nil {
+		t.Errorf("s.verifyTriple(%+v) = nil; expected error", signed)
+	}
+
+	base := fmt.Sprintf("http://localhost:%d/api/v1/publickey?author=", s.network.Port)
+	resp, err := http.Get(base + url.QueryEscape(author))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var respTriple protocol.Triple
+	if err := json.NewDecoder(resp.Body).Decode(&respTriple); err != nil {
+		t.Fatal(err)
+	}
+	if err := crypto.VerifyTriple(&respTriple); err != nil || respTriple.Subj != author {
+		t.Errorf("GET /api/v1/publickey = %+v, %+v", respTriple, err)
+	}
+
+	resp, err = http.Get(base + "degdb:author_missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 404 {
+		t.Errorf("GET /api/v1/publickey?author=degdb:author_missing = %d; not 404", resp.StatusCode)
+	}
+}
+
+func TestPublicKeyCacheLimit(t *testing.T) {
+	maxPublicKeys := MaxPublicKeys
+	MaxPublicKeys = 2
+	defer func() { MaxPublicKeys = maxPublicKeys }()
+
+	s := testServer(t)
+	defer s.Stop()
+
+	var authors []string
+	var triples []*protocol.Triple
+	for i := 0; i < 3; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		triple, err := key.PublicKeyTriple()
+		if err != nil {
+			t.Fatal(err)
+		}
+		authors = append(authors, triple.Subj)
+		triples = append(triples, triple)
+	}
+	s.cachePublicKey(triples[0])
+	s.cachePublicKey(triples[1])
+	// Using the first key makes the second the least recently used.
+	if _, ok := s.cachedPublicKey(authors[0]); !ok {
+		t.Fatalf("public key of %s isn't cached", authors[0])
+	}
+	s.cachePublicKey(triples[2])
+
+	for i, want := range []bool{true, false, true} {
+		if _, ok := s.cachedPublicKey(authors[i]); ok != want {
+			t.Errorf("%d. public key of %s cached = %t; not %t", i, authors[i], ok, want)
+		}
+	}
+	if n := len(s.publicKeys); n != MaxPublicKeys {
+		t.Errorf("%d public keys cached; not %d", n, MaxPublicKeys)
+	}
+}
+
+func TestPublishPublicKeyOnce(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+
+	triple, err := s.crypto.PublicKeyTriple()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := murmur3.Sum64([]byte(triple.Subj))
+	s.network.SetKeyspace(&protocol.Keyspace{Start: hash, End: hash + 1})
+
+	// A key published earlier is already stored.
+	triple.Created = 1
+	if err := s.crypto.SignTriple(triple); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.insertTriples([]*protocol.Triple{triple}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.publishPublicKey(); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := s.ts.History(&protocol.Triple{Subj: triple.Subj, Pred: crypto.PublicKeyPred})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 {
+		t.Errorf("History() = %+v; expected the key published once", changes)
+	}
+}
@@ -6,12 +6,19 @@ import (
 
 	"github.com/degdb/degdb/protocol"
 	"github.com/degdb/degdb/query"
+	"github.com/degdb/degdb/query/gremlin"
+	"github.com/degdb/degdb/query/mql"
+	"github.com/degdb/degdb/triplestore"
 )
 
 func (s *server) ExecuteQuery(q *protocol.QueryRequest) ([]*protocol.Triple, error) {
 	var triples []*protocol.Triple
 	switch q.Type {
 	case protocol.BASIC:
+		if query.HasVars(q.Steps) {
+			_, triples, err := s.ExecuteQueryRows(q)
+			return triples, err
+		}
 		for i, step := range q.Steps {
 			if i != 0 {
 				var midTriples []*protocol.Triple
@@ -29,29 +36,42 @@ func (s *server) ExecuteQuery(q *protocol.QueryRequest) ([]*protocol.Triple, err
 
 			// External request and is already sharded.
 			if q.Sharded {
-				return s.ts.QueryArrayOp(step, int(q.Limit))
+				return s.queryStore(s.store(q.ByObject), step, q)
 			}
 
 			var wg sync.WaitGroup
 			var triplesLock sync.RWMutex
 			triples = nil
 			shards := query.ShardQueryByHash(step)
+			// Queries with unknown subjects can still be routed by their objects if
+			// every object hash has an owner.
+			var byObject bool
+			if _, ok := shards[0]; ok {
+				if objShards := query.ShardQueryByObjectHash(step); objShards[0] == nil && s.covered(objShards) {
+					shards, byObject = objShards, true
+				}
+			}
 
 			// Unrooted queries
 			if arrayOp, ok := shards[0]; ok {
-				// TODO localnode
+				// The local node holds part of the keyspace as well.
+				local, err := s.queryStore(s.ts, arrayOp, q)
+				if err != nil {
+					return nil, err
+				}
+				triples = append(triples, local...)
+
 				set := s.network.MinimumCoveringPeers()
 				s.Printf("Minimum covering set %+v", set)
-				_ = arrayOp
 				wg.Add(len(set))
-				req := basicReq(arrayOp)
-				var err error
+				req := basicReq(arrayOp, q.AsOf)
 				for _, conn := range set {
 					conn := conn
 					go func() {
 						var msg *protocol.Message
 						msg, err = conn.Request(req)
 						if err != nil {
+							wg.Done()
 							return
 						}
 						triplesLock.Lock()
@@ -80,45 +100,120 @@ func (s *server) ExecuteQuery(q *protocol.QueryRequest) ([]*protocol.Triple, err
 					return nil, query.ErrUnRooted
 				}
 				if s.network.LocalPeer().Keyspace.Includes(hash) {
-					trips, err := s.ts.QueryArrayOp(arrayOp, int(q.Limit))
+					trips, err := s.queryStore(s.store(byObject), arrayOp, q)
 					if err != nil {
 						return nil, err
 					}
 					triples = append(triples, trips...)
 					continue
 				}
-			Peers:
-				for _, conn := range s.network.Peers {
-					if conn == nil || conn.Peer == nil {
-						continue
-					}
-					if conn.Peer.Keyspace.Includes(hash) {
-						req := basicReq(arrayOp)
-						// TODO(d4l3k) Parallelize
-						msg, err := conn.Request(req)
-						if err != nil {
-							return nil, err
-						}
-						triples = append(triples, msg.GetQueryResponse().Triples...)
-						break Peers
+				// The owners may not be connected directly so they're found with a
+				// lookup. Each replica is tried until one responds.
+				var msg *protocol.Message
+				var err error
+				for _, conn := range s.network.Replicas(hash) {
+					req := basicReq(arrayOp, q.AsOf)
+					req.GetQueryRequest().ByObject = byObject
+					// TODO(d4l3k) Parallelize
+					if msg, err = conn.Request(req); err == nil {
+						break
 					}
+					s.Printf("ERR querying replica %s: %s", conn.Peer().Id, err)
+				}
+				if err != nil {
+					return nil, err
+				}
+				if msg != nil {
+					triples = append(triples, msg.GetQueryResponse().Triples...)
 				}
 			}
 		}
 
-	//case protocol.GREMLIN:
-	//case protocol.MQL:
+	case protocol.GREMLIN:
+		traversal, err := gremlin.Parse(q.Query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := traversal.Execute(s.asOf(q))
+		if err != nil {
+			return nil, err
+		}
+		triples = result.Triples
+
+	case protocol.MQL:
+		mqlQuery, err := mql.Parse(q.Query)
+		if err != nil {
+			return nil, err
+		}
+		result, err := mqlQuery.Execute(s.asOf(q))
+		if err != nil {
+			return nil, err
+		}
+		triples = result.Triples
+
 	default:
 		return nil, query.ErrNotImplemented
 	}
 	return triples, nil
 }
 
-func basicReq(arrayOp *protocol.ArrayOp) *protocol.Message {
+// ExecuteQueryRows runs a BASIC query whose triples use variables such as
+// "?x". Every triple is a pattern that has to match and patterns sharing a
+// variable are joined on it, in any position. It returns the binding rows and
+// the triples that produced them.
+func (s *server) ExecuteQueryRows(q *protocol.QueryRequest) ([]*protocol.Row, []*protocol.Triple, error) {
+	if q.Type != protocol.BASIC {
+		return nil, nil, query.ErrNotImplemented
+	}
+	return query.JoinSteps(s.asOf(q), q.Steps, q.Vars, int(q.Limit))
+}
+
+// covered returns whether every shard is owned by the local node or a replica
+// it can reach.
+func (s *server) covered(shards map[uint64]*protocol.ArrayOp) bool {
+	for hash := range shards {
+		if s.network.LocalPeer().Keyspace.Includes(hash) {
+			continue
+		}
+		if len(s.network.Replicas(hash)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// queryStore runs a step of a query against a local store, as of q.AsOf if
+// it's set.
+func (s *server) queryStore(ts triplestore.TripleStore, arrayOp *protocol.ArrayOp, q *protocol.QueryRequest) ([]*protocol.Triple, error) {
+	if q.AsOf != 0 {
+		return triplestore.QueryAsOf(ts, arrayOp, q.AsOf, int(q.Limit))
+	}
+	return ts.QueryArrayOp(arrayOp, int(q.Limit))
+}
+
+// asOf returns an Executor for the sub-queries of q that keeps its as of time.
+func (s *server) asOf(q *protocol.QueryRequest) query.Executor {
+	return func(req *protocol.QueryRequest) ([]*protocol.Triple, error) {
+		req.AsOf = q.AsOf
+		return s.ExecuteQuery(req)
+	}
+}
+
+// store returns the object index for queries routed by object hash and the
+// triplestore otherwise.
+func (s *server) store(byObject bool) triplestore.TripleStore {
+	if byObject {
+		return s.objIndex
+	}
+	return s.ts
+}
+
+func basicReq(arrayOp *protocol.ArrayOp, asOf int64) *protocol.Message {
 	return &protocol.Message{Message: &protocol.Message_QueryRequest{
 		QueryRequest: &protocol.QueryRequest{
 			Type:    protocol.BASIC,
 			Steps:   []*protocol.ArrayOp{arrayOp},
 			Sharded: true,
+			AsOf:    asOf,
 		}}}
 }
@@ -1,9 +1,15 @@
 package core
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/d4l3k/messagediff"
+	"github.com/degdb/degdb/network"
 	"github.com/degdb/degdb/protocol"
 )
 
@@ -61,6 +67,68 @@ func TestQuery(t *testing.T) {
 	}
 }
 
+// TestQueryChain isn't parallel since it shrinks the routing table buckets so
+// the first node can't connect to the owner of the triples.
+func TestQueryChain(t *testing.T) {
+	bucketSize := network.BucketSize
+	network.BucketSize = 1
+	defer func() { network.BucketSize = bucketSize }()
+
+	var nodes []*server
+	for i := 0; i < 3; i++ {
+		s := testServer(t)
+		defer s.Stop()
+		go s.network.Listen()
+		s.network.ListenWait()
+		nodes = append(nodes, s)
+	}
+	a, b, c := nodes[0], nodes[1], nodes[2]
+	// b and c are in the same bucket of a's routing table and b is closer.
+	a.network.SetKeyspace(&protocol.Keyspace{Start: 0, End: 1 << 62})
+	b.network.SetKeyspace(&protocol.Keyspace{Start: 1 << 62, End: 3 << 61})
+	c.network.SetKeyspace(&protocol.Keyspace{Start: 3 << 61, End: 0})
+
+	for _, s := range []*server{c, a} {
+		if err := s.network.Connect(fmt.Sprintf("localhost:%d", b.network.Port)); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < retryCount && len(s.network.Conns()) == 0; i++ {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	for _, conn := range a.network.Conns() {
+		if conn.Peer().Id == c.network.LocalID() {
+			t.Fatalf("a is connected to c directly")
+		}
+	}
+
+	triple := &protocol.Triple{
+		Subj: subjInKeyspace(c.network.LocalKeyspace(), "/m/02mjmr"),
+		Pred: "/type/object/name",
+		Obj:  "Barack Obama",
+	}
+	if err := c.crypto.SignTriple(triple); err != nil {
+		t.Fatal(err)
+	}
+	c.ts.Write([]*protocol.Triple{triple})
+
+	q := &protocol.QueryRequest{
+		Type: protocol.BASIC,
+		Steps: []*protocol.ArrayOp{{
+			Triples: []*protocol.Triple{{Subj: triple.Subj}},
+		}},
+	}
+	trips, err := a.ExecuteQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*protocol.Triple{{Subj: triple.Subj, Pred: triple.Pred, Obj: triple.Obj}}
+	trips = stripCreated(stripSigning(trips))
+	if diff, equal := messagediff.PrettyDiff(want, trips); !equal {
+		t.Errorf("a.ExecuteQuery(%+v) = %+v\n%s", q, trips, diff)
+	}
+}
+
 // stripSigning returns a copy of the triples with the signing information stripped.
 func stripSigning(triples []*protocol.Triple) []*protocol.Triple {
 	triples = protocol.CloneTriples(triples)
@@ -70,3 +138,108 @@ func stripSigning(triples []*protocol.Triple) []*protocol.Triple {
 	}
 	return triples
 }
+
+func TestQueryRows(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	triples := testTriplesKeyspace(s.network.LocalKeyspace())
+	if err := s.signAndInsertTriples(protocol.CloneTriples(triples), s.crypto); err != nil {
+		t.Fatal(err)
+	}
+	var obama string
+	for _, triple := range triples {
+		if triple.Obj == "Barack Obama" {
+			obama = triple.Subj
+		}
+	}
+
+	q := &protocol.QueryRequest{
+		Type: protocol.BASIC,
+		Steps: []*protocol.ArrayOp{
+			{Triples: []*protocol.Triple{{Subj: "?x", Pred: "/type/object/name", Obj: "Barack Obama"}}},
+			{Triples: []*protocol.Triple{{Subj: "?x", Pred: "/type/object/type", Obj: "?type"}}},
+		},
+	}
+	want := []*protocol.Row{{Bindings: []*protocol.Binding{
+		{Var: "x", Value: obama},
+		{Var: "type", Value: "/people/person"},
+	}}}
+	rows, trips, err := s.ExecuteQueryRows(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, equal := messagediff.PrettyDiff(want, rows); !equal {
+		t.Errorf("s.ExecuteQueryRows(%+v) = %+v\n%s", q, rows, diff)
+	}
+	if len(trips) != 2 {
+		t.Errorf("s.ExecuteQueryRows(%+v) returned %d triples; not 2", q, len(trips))
+	}
+
+	filter := `[{"subj": "?x", "pred": "/type/object/name", "obj": "?name"}, {"subj": "?x", "obj": "/people/person"}]`
+	resp, err := http.Get(fmt.Sprintf("http://localhost:%d/api/v1/query?vars=name&q=%s", s.network.Port, url.QueryEscape(filter)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []*protocol.Row
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	want = []*protocol.Row{{Bindings: []*protocol.Binding{{Var: "name", Value: "Barack Obama"}}}}
+	if diff, equal := messagediff.PrettyDiff(want, out); !equal {
+		t.Errorf("GET /api/v1/query?q=%s = %+v\n%s", filter, out, diff)
+	}
+}
+
+func TestQueryByObject(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+
+	local := s.network.LocalKeyspace()
+	remote := &protocol.Keyspace{Start: local.End, End: local.Start}
+	// The subject is owned by another node so only the object index has it.
+	triple := &protocol.Triple{
+		Subj: subjInKeyspace(remote, "/m/remote"),
+		Pred: "/common/topic/links",
+		Obj:  subjInKeyspace(local, "/m/local"),
+	}
+	if err := s.crypto.SignTriple(triple); err != nil {
+		t.Fatal(err)
+	}
+	conn := network.NewPeerConn(&protocol.Peer{Id: "test"})
+	s.handleInsertTriples(conn, &protocol.Message{
+		Message: &protocol.Message_InsertTriples{
+			InsertTriples: &protocol.InsertTriples{
+				Triples: []*protocol.Triple{triple},
+			},
+		},
+	})
+
+	if stored, err := s.ts.Query(&protocol.Triple{}, -1); err != nil {
+		t.Fatal(err)
+	} else if len(stored) != 0 {
+		t.Errorf("triplestore has %+v; expected nothing", stored)
+	}
+
+	q := &protocol.QueryRequest{
+		Type: protocol.BASIC,
+		Steps: []*protocol.ArrayOp{{
+			Triples: []*protocol.Triple{{Obj: triple.Obj}},
+		}},
+	}
+	trips, err := s.ExecuteQuery(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*protocol.Triple{{Subj: triple.Subj, Pred: triple.Pred, Obj: triple.Obj}}
+	trips = stripCreated(stripSigning(trips))
+	if diff, equal := messagediff.PrettyDiff(want, trips); !equal {
+		t.Errorf("s.ExecuteQuery(%+v) = %+v\n%s", q, trips, diff)
+	}
+}
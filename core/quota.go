@@ -0,0 +1,85 @@
+package core
+
+import (
+	"time"
+
+	"github.com/spaolacci/murmur3"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/triplestore"
+)
+
+var (
+	// QuotaCheckInterval is how often the disk usage is compared to the
+	// allocation.
+	QuotaCheckInterval = time.Minute
+	// QuotaHighWater is the fraction of the allocation at which the keyspace
+	// is shrunk.
+	QuotaHighWater = 0.9
+	// QuotaLowWater is the fraction of the allocation the shrunk keyspace is
+	// expected to use.
+	QuotaLowWater = 0.75
+)
+
+// quotaLoop periodically enforces the disk allocation.
+func (s *server) quotaLoop() {
+	for s.sleep(QuotaCheckInterval) {
+		if err := s.enforceQuota(); err != nil {
+			s.Printf("ERR enforcing disk quota: %s", err)
+		}
+	}
+}
+
+// diskUsed returns the number of bytes used by the triplestore and the object
+// index.
+func (s *server) diskUsed() (uint64, error) {
+	var used uint64
+	for _, ts := range []triplestore.TripleStore{s.ts, s.objIndex} {
+		info, err := ts.Size()
+		if err != nil {
+			return 0, err
+		}
+		used += info.DiskSize
+	}
+	return used, nil
+}
+
+// enforceQuota shrinks the local keyspace when the disk usage nears the
+// allocation and evicts the triples, history and tombstones outside of it. The
+// new keyspace is announced to the peers so they route to the node
+// accordingly.
+func (s *server) enforceQuota() error {
+	if s.diskAllocated <= 0 {
+		return nil
+	}
+	used, err := s.diskUsed()
+	if err != nil {
+		return err
+	}
+	allocated := float64(s.diskAllocated)
+	if float64(used) < allocated*QuotaHighWater {
+		return nil
+	}
+
+	// Triples are spread evenly over the keyspace so it shrinks in proportion.
+	keyspace := s.network.LocalKeyspace().Shrink(allocated * QuotaLowWater / float64(used))
+	s.Printf("Disk usage %d of %d bytes. Shrinking keyspace to %+v.", used, s.diskAllocated, keyspace)
+	if err := s.setKeyspace(keyspace); err != nil {
+		return err
+	}
+
+	evicted, err := triplestore.Evict(s.ts, keyspace, func(triple *protocol.Triple) uint64 {
+		return murmur3.Sum64([]byte(triple.Subj))
+	})
+	if err != nil {
+		return err
+	}
+	evictedObj, err := triplestore.Evict(s.objIndex, keyspace, func(triple *protocol.Triple) uint64 {
+		return murmur3.Sum64([]byte(triple.Obj))
+	})
+	if err != nil {
+		return err
+	}
+	s.Printf("Evicted %d triples and %d object index triples.", evicted, evictedObj)
+	return nil
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/spaolacci/murmur3"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestEnforceQuota(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+
+	keyspace := s.network.LocalKeyspace()
+	var triples []*protocol.Triple
+	for i := 0; i < 100; i++ {
+		triples = append(triples, &protocol.Triple{
+			Subj: subjInKeyspace(keyspace, "/m/0quota"+strconv.Itoa(i)+"-"),
+			Pred: "/type/object/name",
+			Obj:  "Quota",
+		})
+	}
+	s.ts.Write(triples)
+
+	// Under the allocation nothing happens.
+	if err := s.enforceQuota(); err != nil {
+		t.Fatal(err)
+	}
+	if out := s.network.LocalKeyspace(); out.Mag() != keyspace.Mag() {
+		t.Errorf("keyspace shrunk to %+v under the allocation", out)
+	}
+
+	used, err := s.diskUsed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.diskAllocated = int(used / 2)
+	if err := s.enforceQuota(); err != nil {
+		t.Fatal(err)
+	}
+	shrunk := s.network.LocalKeyspace()
+	if shrunk.Mag() >= keyspace.Mag() {
+		t.Fatalf("keyspace %+v didn't shrink from %+v", shrunk, keyspace)
+	}
+	stored, err := s.ts.Query(&protocol.Triple{}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) >= len(triples) {
+		t.Errorf("stored %d triples; expected fewer than %d", len(stored), len(triples))
+	}
+	for _, triple := range stored {
+		if !shrunk.Includes(murmur3.Sum64([]byte(triple.Subj))) {
+			t.Errorf("triple %+v outside of the keyspace %+v wasn't evicted", triple, shrunk)
+		}
+	}
+}
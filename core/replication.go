@@ -0,0 +1,191 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spaolacci/murmur3"
+
+	"github.com/degdb/degdb/merkle"
+	"github.com/degdb/degdb/network"
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/triplestore"
+)
+
+var (
+	// ReplicationFactor is the number of replicas that must hold a triple
+	// before an insert succeeds. It should be the same on every node.
+	ReplicationFactor = 1
+	// RepairInterval is how often departed peers are checked for.
+	RepairInterval = 10 * time.Second
+)
+
+// replicateTriples sends triples with the same subject hash to every peer
+// owning the hash and waits for their acknowledgements. The triples are also
+// inserted locally if the hash is in the local keyspace. It returns an error if
+// fewer than ReplicationFactor replicas hold the triples.
+func (s *server) replicateTriples(hash uint64, triples []*protocol.Triple) error {
+	acks := 0
+	if s.network.LocalPeer().Keyspace.Includes(hash) {
+		if _, _, err := s.ts.Write(triples); err != nil {
+			s.Printf("ERR storing triples: %s", err)
+		} else {
+			acks++
+		}
+	}
+
+	replicas := s.network.Replicas(hash)
+	acked := make(chan bool, len(replicas))
+	for _, conn := range replicas {
+		conn := conn
+		go func() {
+			msg, err := conn.Request(&protocol.Message{
+				Message: &protocol.Message_InsertTriples{
+					InsertTriples: &protocol.InsertTriples{
+						Triples: triples,
+					}},
+			})
+			if err != nil {
+				s.Printf("ERR replicating to %s: %s", conn.PrettyID(), err)
+				acked <- false
+				return
+			}
+			acked <- len(msg.Error) == 0 && msg.GetInsertTriplesResponse().GetCount() == int32(len(triples))
+		}()
+	}
+	for range replicas {
+		if <-acked {
+			acks++
+		}
+	}
+
+	if acks == 0 {
+		return network.ErrNoRecipients
+	}
+	if acks < ReplicationFactor {
+		return fmt.Errorf("only %d of %d replicas acknowledged the triples", acks, ReplicationFactor)
+	}
+	return nil
+}
+
+// replicateObjectIndex sends triples with the same object hash to every peer
+// owning the hash for its object index and inserts them into the local one if
+// the hash is in the local keyspace. The index is secondary and kept in sync by
+// anti-entropy, so it doesn't wait for acknowledgements.
+func (s *server) replicateObjectIndex(hash uint64, triples []*protocol.Triple) {
+	if s.network.LocalPeer().Keyspace.Includes(hash) {
+		if _, _, err := s.objIndex.Write(triples); err != nil {
+			s.Printf("ERR inserting into object index: %s", err)
+		}
+	}
+	msg := &protocol.Message{
+		Message: &protocol.Message_InsertTriples{
+			InsertTriples: &protocol.InsertTriples{
+				Triples: triples,
+			}},
+	}
+	for _, conn := range s.network.Replicas(hash) {
+		if err := conn.Send(msg); err != nil {
+			s.Printf("ERR replicating object index to %s: %s", conn.PrettyID(), err)
+		}
+	}
+}
+
+// repairLoop watches for peers leaving and re-replicates the triples in their
+// keyspaces to the remaining owners. Departures are collected until a check
+// finds no new ones so peers leaving close together are repaired in one pass.
+func (s *server) repairLoop() {
+	known := s.peerKeyspaces()
+	var departed []*protocol.Keyspace
+	for s.sleep(RepairInterval) {
+		current := s.peerKeyspaces()
+		left := false
+		for id, keyspace := range known {
+			if _, ok := current[id]; !ok {
+				s.Printf("Peer %s left, repairing keyspace %+v", id, keyspace)
+				departed = append(departed, keyspace)
+				left = true
+			}
+		}
+		known = current
+		if left || len(departed) == 0 {
+			continue
+		}
+		if err := s.repair(departed); err != nil {
+			s.Printf("ERR repairing: %s", err)
+		}
+		departed = nil
+	}
+}
+
+// peerKeyspaces returns the keyspaces of the connected peers by ID.
+func (s *server) peerKeyspaces() map[string]*protocol.Keyspace {
+	keyspaces := make(map[string]*protocol.Keyspace)
+	for _, conn := range s.network.Conns() {
+		if conn.Peer().Keyspace != nil {
+			keyspaces[conn.Peer().Id] = conn.Peer().Keyspace
+		}
+	}
+	return keyspaces
+}
+
+// repairLeaves returns the leaves of the local Merkle tree in the keyspaces
+// that hold triples.
+func (s *server) repairLeaves(keyspaces []*protocol.Keyspace) map[uint64]bool {
+	depth := s.tree.Depth()
+	leaves := make(map[uint64]bool)
+	for i := uint64(0); i < 1<<uint(depth); i++ {
+		if s.tree.Empty(i) {
+			continue
+		}
+		for _, keyspace := range keyspaces {
+			if merkle.Intersects(keyspace, depth, i) {
+				leaves[i] = true
+				break
+			}
+		}
+	}
+	return leaves
+}
+
+// repair sends the local triples in the keyspaces to their current owners. The
+// store is only scanned if the Merkle tree has triples in the keyspaces, and
+// only the triples in those leaves are checked.
+func (s *server) repair(keyspaces []*protocol.Keyspace) error {
+	leaves := s.repairLeaves(keyspaces)
+	if len(leaves) == 0 {
+		s.Printf("Repaired 0 triples.")
+		return nil
+	}
+
+	hashes := make(map[uint64][]*protocol.Triple)
+	results, errs := s.ts.EachTripleBatch(triplestore.DefaultTripleBatchSize)
+	for triples := range results {
+		for _, triple := range triples {
+			hash := murmur3.Sum64([]byte(triple.Subj))
+			if !leaves[s.tree.Leaf(hash)] {
+				continue
+			}
+			for _, keyspace := range keyspaces {
+				if keyspace.Includes(hash) {
+					hashes[hash] = append(hashes[hash], triple)
+					break
+				}
+			}
+		}
+	}
+	for err := range errs {
+		return err
+	}
+
+	repaired := 0
+	for hash, triples := range hashes {
+		if err := s.replicateTriples(hash, triples); err != nil {
+			s.Printf("ERR repairing %d triples: %s", len(triples), err)
+			continue
+		}
+		repaired += len(triples)
+	}
+	s.Printf("Repaired %d triples.", repaired)
+	return nil
+}
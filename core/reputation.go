@@ -0,0 +1,396 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/network"
+	"github.com/degdb/degdb/protocol"
+)
+
+// ReputationInterval is how often a node publishes the author behavior it has
+// seen.
+var ReputationInterval = time.Minute
+
+// ReputationPredPrefix prefixes the predicates of the reputation triples. The
+// subject of a reputation triple is the author ID, the object is the count and
+// the author is the node that counted it. Only nodes publish them, so clients
+// can't insert them and they're only counted from proven peers.
+const ReputationPredPrefix = "degdb:reputation:"
+
+var ErrReputationPred = errors.New("reputation predicates can only be published by nodes")
+
+// The author behaviors that are counted.
+const (
+	assertion = iota
+	retraction
+	conflictLost
+	verificationFailure
+)
+
+// ReputationPreds are the predicates of the reputation counts by behavior.
+var ReputationPreds = []string{
+	ReputationPredPrefix + "assertions",
+	ReputationPredPrefix + "retractions",
+	ReputationPredPrefix + "conflicts_lost",
+	ReputationPredPrefix + "verification_failures",
+}
+
+// Reputation is the behavior of an author.
+type Reputation struct {
+	Assertions           int64 `json:"assertions"`
+	Retractions          int64 `json:"retractions"`
+	ConflictsLost        int64 `json:"conflicts_lost"`
+	VerificationFailures int64 `json:"verification_failures"`
+}
+
+// counter returns the count of a behavior.
+func (r *Reputation) counter(behavior int) *int64 {
+	switch behavior {
+	case assertion:
+		return &r.Assertions
+	case retraction:
+		return &r.Retractions
+	case conflictLost:
+		return &r.ConflictsLost
+	}
+	return &r.VerificationFailures
+}
+
+// add adds the counts of another reputation.
+func (r *Reputation) add(other *Reputation) {
+	for behavior := range ReputationPreds {
+		*r.counter(behavior) += *other.counter(behavior)
+	}
+}
+
+// Score is the fraction of an author's behavior that's assertions, with one
+// assertion and one mistake assumed. Authors without any history score 0.5.
+func (r *Reputation) Score() float64 {
+	mistakes := r.Retractions + r.ConflictsLost + r.VerificationFailures
+	return float64(r.Assertions+1) / float64(r.Assertions+mistakes+2)
+}
+
+// isReputationPred returns whether the predicate is of a reputation triple.
+func isReputationPred(pred string) bool {
+	return strings.HasPrefix(pred, ReputationPredPrefix)
+}
+
+// recordBehavior counts a behavior of an author until it's published.
+// Reputation triples aren't counted so publishing them doesn't count itself.
+func (s *server) recordBehavior(author, pred string, behavior int) {
+	if isReputationPred(pred) {
+		return
+	}
+	s.reputationLock.Lock()
+	defer s.reputationLock.Unlock()
+
+	r, ok := s.reputation[author]
+	if !ok {
+		r = &Reputation{}
+		s.reputation[author] = r
+	}
+	*r.counter(behavior)++
+}
+
+// recordAssertions counts the triples accepted from a client as assertions of
+// their authors. They're only counted by the node that accepted them, not by
+// the replicas, and triples signed by the node itself aren't counted.
+func (s *server) recordAssertions(triples []*protocol.Triple) {
+	own, err := s.crypto.AuthorID()
+	if err != nil {
+		s.Printf("ERR recording assertions: %s", err)
+		return
+	}
+	for _, triple := range triples {
+		if triple.Author == own {
+			continue
+		}
+		s.recordBehavior(triple.Author, triple.Pred, assertion)
+	}
+}
+
+// recordVerificationFailure counts a triple or tombstone that failed
+// verification against the client or peer that sent it. The author it claims
+// isn't charged since nothing proves the author sent it.
+func (s *server) recordVerificationFailure(sender, pred string) {
+	s.recordBehavior(sender, pred, verificationFailure)
+}
+
+// httpSender returns the host an HTTP request was sent from.
+func httpSender(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// peerSender returns the ID of the peer a message was received from.
+func peerSender(conn *network.Conn) string {
+	if peer := conn.Peer(); peer != nil {
+		return peer.Id
+	}
+	return conn.RemoteAddr().String()
+}
+
+// conflictLosers returns the triples that lose a conflict once the triples
+// accepted from a client are written. The newest object for a subject and
+// predicate replaces the older ones, so the value it replaces loses, as does a
+// triple older than the current value. A predicate that any author gives more
+// than one object for a subject, as several values or by changing its value,
+// is multi-valued and its objects don't conflict. It has to be called before
+// the triples are inserted.
+func (s *server) conflictLosers(triples []*protocol.Triple) []*protocol.Triple {
+	groups := make(map[string][]*protocol.Triple)
+	var order []string
+	for _, triple := range triples {
+		key := triple.Subj + "\x00" + triple.Pred
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], triple)
+	}
+	var losers []*protocol.Triple
+	for _, key := range order {
+		group := groups[key]
+		stored, err := s.ExecuteQuery(&protocol.QueryRequest{
+			Type: protocol.BASIC,
+			Steps: []*protocol.ArrayOp{{
+				Triples: []*protocol.Triple{{Subj: group[0].Subj, Pred: group[0].Pred}},
+			}},
+		})
+		if err != nil {
+			s.Printf("ERR finding conflicts: %s", err)
+			continue
+		}
+		if multiValued(append(stored, group...)) {
+			continue
+		}
+		known := make(map[string]bool)
+		var current *protocol.Triple
+		for _, triple := range stored {
+			known[tripleKey(triple)] = true
+			if current == nil || triple.Created > current.Created {
+				current = triple
+			}
+		}
+		for _, triple := range group {
+			if known[tripleKey(triple)] {
+				continue
+			}
+			known[tripleKey(triple)] = true
+			var loser *protocol.Triple
+			switch {
+			case current == nil:
+				current = triple
+			case triple.Created > current.Created:
+				loser, current = current, triple
+			case triple.Created < current.Created:
+				loser = triple
+			}
+			if loser != nil && loser.Obj != current.Obj {
+				losers = append(losers, loser)
+			}
+		}
+	}
+	return losers
+}
+
+// multiValued returns whether an author gives more than one object for the
+// subject and predicate of the triples.
+func multiValued(triples []*protocol.Triple) bool {
+	objs := make(map[string]string)
+	for _, triple := range triples {
+		if obj, ok := objs[triple.Author]; ok && obj != triple.Obj {
+			return true
+		}
+		objs[triple.Author] = triple.Obj
+	}
+	return false
+}
+
+// recordConflicts counts the authors of the losing triples as having lost a
+// conflict. Like assertions, conflicts are only counted by the node that
+// accepted the triples from the client.
+func (s *server) recordConflicts(losers []*protocol.Triple) {
+	for _, loser := range losers {
+		s.recordBehavior(loser.Author, loser.Pred, conflictLost)
+	}
+}
+
+// tripleKey is the unique key of a triple in the triplestore.
+func tripleKey(triple *protocol.Triple) string {
+	return triple.Subj + "\x00" + triple.Pred + "\x00" + triple.Obj + "\x00" + triple.Author
+}
+
+// publishReputationLoop periodically publishes the counted author behavior and
+// drops the expired cached reputations.
+func (s *server) publishReputationLoop() {
+	for s.sleep(ReputationInterval) {
+		if err := s.publishReputation(); err != nil {
+			s.Printf("ERR publishing reputation: %s", err)
+		}
+		s.expireReputationCache()
+	}
+}
+
+// publishReputation adds the behavior counted since the last publish to the
+// node's reputation triples. The new counts are inserted and the old ones are
+// retracted.
+func (s *server) publishReputation() error {
+	s.reputationLock.Lock()
+	pending := s.reputation
+	s.reputation = make(map[string]*Reputation)
+	s.reputationLock.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	err := s.publishCounts(pending)
+	if err != nil {
+		s.reputationLock.Lock()
+		for author, r := range pending {
+			if current, ok := s.reputation[author]; ok {
+				r.add(current)
+			}
+			s.reputation[author] = r
+		}
+		s.reputationLock.Unlock()
+	}
+	return err
+}
+
+// publishCounts adds the pending counts to the node's published ones. A
+// retraction may not have reached every replica, so the largest published
+// count is the current one and every published count is retracted.
+func (s *server) publishCounts(pending map[string]*Reputation) error {
+	own, err := s.crypto.AuthorID()
+	if err != nil {
+		return err
+	}
+	unix := time.Now().Unix()
+	var triples []*protocol.Triple
+	var tombstones []*protocol.Tombstone
+	for author, delta := range pending {
+		published, err := s.reputationTriples(author)
+		if err != nil {
+			return err
+		}
+		for behavior, pred := range ReputationPreds {
+			d := *delta.counter(behavior)
+			if d == 0 {
+				continue
+			}
+			var count int64
+			for _, triple := range published {
+				if triple.Author != own || triple.Pred != pred {
+					continue
+				}
+				if n, err := strconv.ParseInt(triple.Obj, 10, 64); err == nil && n > count {
+					count = n
+				}
+				tombstone := crypto.NewTombstone(triple)
+				tombstone.Created = unix
+				if err := s.crypto.SignTombstone(tombstone); err != nil {
+					return err
+				}
+				tombstones = append(tombstones, tombstone)
+			}
+			triples = append(triples, &protocol.Triple{
+				Subj: author,
+				Pred: pred,
+				Obj:  strconv.FormatInt(count+d, 10),
+			})
+		}
+	}
+	if err := s.signAndInsertTriples(triples, s.crypto); err != nil {
+		return err
+	}
+	_, err = s.retractTriples(tombstones)
+	return err
+}
+
+// reputationTriples returns the reputation triples about an author from every
+// node. Triples whose author isn't the local node or a proven peer are
+// ignored.
+func (s *server) reputationTriples(author string) ([]*protocol.Triple, error) {
+	triples, err := s.ExecuteQuery(&protocol.QueryRequest{
+		Type: protocol.BASIC,
+		Steps: []*protocol.ArrayOp{{
+			Triples: []*protocol.Triple{{Subj: author}},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var results []*protocol.Triple
+	for _, triple := range triples {
+		if isReputationPred(triple.Pred) && s.network.IsNode(triple.Author) {
+			results = append(results, triple)
+		}
+	}
+	return results, nil
+}
+
+// reputationOf sums the published counts of every node about an author with
+// the behavior this node hasn't published yet.
+func (s *server) reputationOf(author string) (*Reputation, error) {
+	triples, err := s.reputationTriples(author)
+	if err != nil {
+		return nil, err
+	}
+	// Counts only grow so the largest is the current one for each node.
+	type count struct{ node, pred string }
+	latest := make(map[count]int64)
+	for _, triple := range triples {
+		n, err := strconv.ParseInt(triple.Obj, 10, 64)
+		if err != nil {
+			continue
+		}
+		key := count{triple.Author, triple.Pred}
+		if n > latest[key] {
+			latest[key] = n
+		}
+	}
+	r := &Reputation{}
+	for key, n := range latest {
+		for behavior, pred := range ReputationPreds {
+			if pred == key.pred {
+				*r.counter(behavior) += n
+			}
+		}
+	}
+
+	s.reputationLock.Lock()
+	if pending, ok := s.reputation[author]; ok {
+		r.add(pending)
+	}
+	s.reputationLock.Unlock()
+	return r, nil
+}
+
+// handleReputation returns the behavior counts and score of the "author"
+// parameter.
+func (s *server) handleReputation(w http.ResponseWriter, r *http.Request) {
+	author := r.FormValue("author")
+	if len(author) == 0 {
+		http.Error(w, "missing author parameter", 400)
+		return
+	}
+	reputation, err := s.reputationOf(author)
+	if err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	json.NewEncoder(w).Encode(struct {
+		*Reputation
+		Score float64 `json:"score"`
+	}{reputation, reputation.Score()})
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestReputationScore(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		reputation Reputation
+		want       float64
+	}{
+		{Reputation{}, 0.5},
+		{Reputation{Assertions: 7}, 8.0 / 9},
+		{Reputation{Assertions: 1, Retractions: 1, ConflictsLost: 1, VerificationFailures: 1}, 2.0 / 6},
+	}
+	for i, td := range testData {
+		if out := td.reputation.Score(); out != td.want {
+			t.Errorf("%d. %+v.Score() = %f; not %f", i, td.reputation, out, td.want)
+		}
+	}
+}
+
+func TestPublishReputation(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+	s.network.SetKeyspace(&protocol.Keyspace{Start: 1, End: 0})
+
+	for i := 0; i < 3; i++ {
+		s.recordBehavior("author", "/type/object/name", assertion)
+	}
+	if err := s.publishReputation(); err != nil {
+		t.Fatal(err)
+	}
+	s.recordBehavior("author", "/type/object/name", assertion)
+	s.recordBehavior("author", "/type/object/name", verificationFailure)
+	// The author loses once when its value is replaced by a newer one.
+	accept(s, []*protocol.Triple{{Subj: "a", Pred: "b", Obj: "d", Author: "author", Created: 1}})
+	for i := 0; i < 2; i++ {
+		accept(s, []*protocol.Triple{{Subj: "a", Pred: "b", Obj: "c", Author: "other", Created: 2}})
+	}
+	if err := s.publishReputation(); err != nil {
+		t.Fatal(err)
+	}
+	// Reputation triples aren't counted themselves.
+	s.recordBehavior("author", ReputationPreds[assertion], assertion)
+
+	out, err := s.reputationOf("author")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Reputation{Assertions: 4, ConflictsLost: 1, VerificationFailures: 1}
+	if diff, ok := messagediff.PrettyDiff(want, out); !ok {
+		t.Errorf("reputationOf() = %+v; diff %s", out, diff)
+	}
+
+	// The old counts are retracted.
+	triples, err := s.ts.Query(&protocol.Triple{Subj: "author", Pred: ReputationPreds[assertion]}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triples) != 1 || triples[0].Obj != "4" {
+		t.Errorf("reputation triples = %+v; expected a single count of 4", triples)
+	}
+}
+
+// accept writes triples like they were accepted from a client, counting the
+// conflicts they cause.
+func accept(s *server, triples []*protocol.Triple) {
+	losers := s.conflictLosers(triples)
+	s.ts.Write(triples)
+	s.recordConflicts(losers)
+}
+
+func TestRecordConflicts(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+	s.network.SetKeyspace(&protocol.Keyspace{Start: 1, End: 0})
+
+	accept(s, []*protocol.Triple{
+		{Subj: "a", Pred: "b", Obj: "c", Author: "current", Created: 10},
+		// Asserting the current value again doesn't lose.
+		{Subj: "a", Pred: "b", Obj: "c", Author: "agreed", Created: 5},
+	})
+	accept(s, []*protocol.Triple{
+		// Older than the current value so it loses as soon as it's written.
+		{Subj: "a", Pred: "b", Obj: "e", Author: "late", Created: 1},
+		// Created at the same time as the current value so neither loses.
+		{Subj: "a", Pred: "b", Obj: "f", Author: "tied", Created: 10},
+	})
+	// An author gives several objects so the predicate is multi-valued and
+	// an older object doesn't lose.
+	accept(s, []*protocol.Triple{
+		{Subj: "x", Pred: "/people/person/children", Obj: "y", Author: "parent", Created: 10},
+		{Subj: "x", Pred: "/people/person/children", Obj: "z", Author: "parent", Created: 10},
+	})
+	accept(s, []*protocol.Triple{
+		{Subj: "x", Pred: "/people/person/children", Obj: "w", Author: "other", Created: 1},
+	})
+
+	s.reputationLock.Lock()
+	defer s.reputationLock.Unlock()
+	want := map[string]*Reputation{
+		"late": {ConflictsLost: 1},
+	}
+	if diff, ok := messagediff.PrettyDiff(want, s.reputation); !ok {
+		t.Errorf("reputation = %+v; diff %s", s.reputation, diff)
+	}
+}
+
+// TestRecordConflictsReplicated checks that only the node accepting the
+// triples from the client counts the conflicts, not its replicas.
+func TestRecordConflictsReplicated(t *testing.T) {
+	replicationFactor := ReplicationFactor
+	ReplicationFactor = 2
+	defer func() { ReplicationFactor = replicationFactor }()
+
+	var nodes []*server
+	for i := 0; i < 2; i++ {
+		s := testServer(t)
+		defer s.Stop()
+		go s.network.Listen()
+		s.network.ListenWait()
+		s.network.SetKeyspace(&protocol.Keyspace{Start: 1, End: 0})
+		nodes = append(nodes, s)
+	}
+	a, b := nodes[0], nodes[1]
+	if err := b.network.Connect(fmt.Sprintf("localhost:%d", a.network.Port)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < retryCount && len(a.network.Conns()) == 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	var triples []*protocol.Triple
+	for i, obj := range []string{"Barack Obama", "Obama"} {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		triple := &protocol.Triple{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: obj, Created: int64(i + 1)}
+		if err := key.SignTriple(triple); err != nil {
+			t.Fatal(err)
+		}
+		triples = append(triples, triple)
+	}
+	for _, triple := range triples {
+		body, err := json.Marshal([]*protocol.Triple{triple})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		a.handleInsertSignedTriples(w, httptest.NewRequest("POST", "/api/v2/insert", bytes.NewReader(body)))
+		if w.Code != 200 {
+			t.Fatalf("insert = %d %s", w.Code, w.Body)
+		}
+	}
+
+	stored, err := b.ts.Query(&protocol.Triple{Subj: "/m/02mjmr"}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("replica holds %+v; expected both triples", stored)
+	}
+	counts := func(s *server) map[string]*Reputation {
+		s.reputationLock.Lock()
+		defer s.reputationLock.Unlock()
+		conflicts := make(map[string]*Reputation)
+		for author, r := range s.reputation {
+			if r.ConflictsLost > 0 {
+				conflicts[author] = &Reputation{ConflictsLost: r.ConflictsLost}
+			}
+		}
+		return conflicts
+	}
+	want := map[string]*Reputation{triples[0].Author: {ConflictsLost: 1}}
+	if diff, ok := messagediff.PrettyDiff(want, counts(a)); !ok {
+		t.Errorf("accepting node conflicts = %+v; diff %s", counts(a), diff)
+	}
+	if out := counts(b); len(out) != 0 {
+		t.Errorf("replica conflicts = %+v; expected none", out)
+	}
+}
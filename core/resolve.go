@@ -0,0 +1,68 @@
+package core
+
+import "time"
+
+// TrustedAuthors are the author IDs the "trust" resolution strategy trusts
+// fully, along with the node's own key.
+var TrustedAuthors []string
+
+// ReputationCacheTTL is how long the "trust" resolution strategy reuses the
+// reputation it looked up for an author.
+var ReputationCacheTTL = time.Minute
+
+// cachedReputation is a reputation score looked up by the trust strategy.
+type cachedReputation struct {
+	score   float64
+	expires time.Time
+}
+
+// trust returns how much the node trusts an author when resolving conflicting
+// values. Authors that aren't trusted fully are trusted by their reputation
+// score.
+func (s *server) trust(author string) float64 {
+	if own, err := s.crypto.AuthorID(); err == nil && author == own {
+		return 1
+	}
+	for _, trusted := range TrustedAuthors {
+		if author == trusted {
+			return 1
+		}
+	}
+	return s.reputationScore(author)
+}
+
+// reputationScore returns the reputation score of an author. Scores are cached
+// for ReputationCacheTTL so resolving queries doesn't query the cluster for
+// every author each time.
+func (s *server) reputationScore(author string) float64 {
+	now := time.Now()
+	s.reputationLock.Lock()
+	cached, ok := s.reputationCache[author]
+	s.reputationLock.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.score
+	}
+
+	reputation, err := s.reputationOf(author)
+	if err != nil {
+		s.Printf("ERR looking up reputation of %s: %s", author, err)
+		return (&Reputation{}).Score()
+	}
+	score := reputation.Score()
+	s.reputationLock.Lock()
+	s.reputationCache[author] = &cachedReputation{score: score, expires: now.Add(ReputationCacheTTL)}
+	s.reputationLock.Unlock()
+	return score
+}
+
+// expireReputationCache drops the cached reputations that have expired.
+func (s *server) expireReputationCache() {
+	now := time.Now()
+	s.reputationLock.Lock()
+	defer s.reputationLock.Unlock()
+	for author, cached := range s.reputationCache {
+		if !now.Before(cached.expires) {
+			delete(s.reputationCache, author)
+		}
+	}
+}
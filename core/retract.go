@@ -0,0 +1,294 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spaolacci/murmur3"
+
+	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/network"
+	"github.com/degdb/degdb/protocol"
+)
+
+var ErrNotDelegated = errors.New("tombstone signer isn't the author or a delegate of the author")
+
+// handleRetract retracts a set of triples. The tombstones are signed with the
+// server's key so it must be the author of the triples or a delegate of it.
+// Triples without an author are assumed to be the server's.
+func (s *server) handleRetract(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "endpoint needs POST", 400)
+		return
+	}
+	var triples []*protocol.Triple
+	if err := json.NewDecoder(r.Body).Decode(&triples); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	unix := time.Now().Unix()
+	var tombstones []*protocol.Tombstone
+	for _, triple := range triples {
+		tombstone := crypto.NewTombstone(triple)
+		tombstone.Created = unix
+		if err := s.crypto.SignTombstone(tombstone); err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+		tombstones = append(tombstones, tombstone)
+	}
+	s.serveRetract(w, r, tombstones)
+}
+
+// handleRetractSigned retracts triples with tombstones that have already been
+// signed by the client.
+func (s *server) handleRetractSigned(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "endpoint needs POST", 400)
+		return
+	}
+	var tombstones []*protocol.Tombstone
+	if err := json.NewDecoder(r.Body).Decode(&tombstones); err != nil {
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	s.serveRetract(w, r, tombstones)
+}
+
+func (s *server) serveRetract(w http.ResponseWriter, r *http.Request, tombstones []*protocol.Tombstone) {
+	for i, tombstone := range tombstones {
+		if err := s.verifyTombstone(tombstone); err != nil {
+			s.Printf("ERR tombstone rejected %#v from %s: %s", tombstone, r.RemoteAddr, err)
+			s.recordVerificationFailure(httpSender(r), tombstone.Pred)
+			http.Error(w, fmt.Sprintf("tombstone %d: %s", i, err), 400)
+			return
+		}
+	}
+	count, err := s.retractTriples(tombstones)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.Write([]byte(fmt.Sprintf("Retracted %d triples.", count)))
+}
+
+// verifyTombstone checks the signature on a tombstone and that the signer is
+// the author of the retracted triple or a delegate of it.
+func (s *server) verifyTombstone(tombstone *protocol.Tombstone) error {
+	if err := crypto.VerifyTombstone(tombstone); err != nil {
+		return err
+	}
+	if tombstone.Signer == tombstone.Author {
+		return nil
+	}
+	return s.verifyDelegate(tombstone.Author, tombstone.Signer)
+}
+
+// verifyDelegate checks that the graph has a DelegatePred triple from the
+// author to the delegate signed by the author.
+func (s *server) verifyDelegate(author, delegate string) error {
+	triples, err := s.ExecuteQuery(&protocol.QueryRequest{
+		Type: protocol.BASIC,
+		Steps: []*protocol.ArrayOp{{
+			Triples: []*protocol.Triple{{
+				Subj: author,
+				Pred: crypto.DelegatePred,
+				Obj:  delegate,
+			}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	for _, triple := range triples {
+		if triple.Author != author {
+			continue
+		}
+		if err := s.verifyTriple(triple); err != nil {
+			s.Printf("ERR invalid delegate triple %#v: %s", triple, err)
+			continue
+		}
+		return nil
+	}
+	return ErrNotDelegated
+}
+
+// retractTriples sends verified tombstones to the replicas of the retracted
+// triples by subject and gossips them to the peers that have the objects in
+// their keyspace. They're applied locally as well. It returns the number of
+// triples retracted, counting a triple held by several replicas once.
+func (s *server) retractTriples(tombstones []*protocol.Tombstone) (int, error) {
+	hashes := make(map[uint64][]*protocol.Tombstone)
+	objHashes := make(map[uint64][]*protocol.Tombstone)
+	for _, tombstone := range tombstones {
+		s.recordBehavior(tombstone.Author, tombstone.Pred, retraction)
+		hash := murmur3.Sum64([]byte(tombstone.Subj))
+		hashes[hash] = append(hashes[hash], tombstone)
+		objHash := murmur3.Sum64([]byte(tombstone.Obj))
+		objHashes[objHash] = append(objHashes[objHash], tombstone)
+	}
+	count := 0
+	for hash, tombstones := range hashes {
+		count += s.replicateTombstones(hash, tombstones)
+	}
+	// Like the object index it updates, this is best effort.
+	localKS := s.network.LocalKeyspace()
+	for hash, tombstones := range objHashes {
+		msg := &protocol.Message{
+			Message: &protocol.Message_RetractTriples{
+				RetractTriples: &protocol.RetractTriples{
+					Tombstones: tombstones,
+				}},
+			Gossip: true,
+		}
+		if err := s.network.Broadcast(&hash, msg); err != nil && err != network.ErrNoRecipients {
+			return count, err
+		}
+		if localKS.Includes(hash) {
+			if _, err := s.objIndex.Erase(tombstones); err != nil {
+				s.Printf("ERR retracting from object index: %s", err)
+			}
+		}
+	}
+	return count, nil
+}
+
+// replicateTombstones sends tombstones with the same subject hash to every
+// peer owning the hash and waits for the number of triples they retracted.
+// The tombstones are also applied locally if the hash is in the local
+// keyspace. It returns the most triples retracted by any replica.
+func (s *server) replicateTombstones(hash uint64, tombstones []*protocol.Tombstone) int {
+	most := 0
+	if s.network.LocalKeyspace().Includes(hash) {
+		removed, err := s.ts.Erase(tombstones)
+		if err != nil {
+			s.Printf("ERR retracting triples: %s", err)
+		}
+		most = len(removed)
+	}
+
+	replicas := s.network.Replicas(hash)
+	counts := make(chan int, len(replicas))
+	for _, conn := range replicas {
+		conn := conn
+		go func() {
+			msg, err := conn.Request(&protocol.Message{
+				Message: &protocol.Message_RetractTriples{
+					RetractTriples: &protocol.RetractTriples{
+						Tombstones: tombstones,
+					}},
+			})
+			if err == nil && len(msg.Error) > 0 {
+				err = errors.New(msg.Error)
+			}
+			if err != nil {
+				s.Printf("ERR retracting from %s: %s", conn.PrettyID(), err)
+				counts <- 0
+				return
+			}
+			counts <- int(msg.GetRetractResponse().GetCount())
+		}()
+	}
+	for range replicas {
+		if count := <-counts; count > most {
+			most = count
+		}
+	}
+	return most
+}
+
+// applyTombstones retracts the triples in the local keyspace from the
+// triplestore and the object index. It returns the number of triples
+// retracted from the triplestore.
+func (s *server) applyTombstones(tombstones []*protocol.Tombstone) (int, error) {
+	localKS := s.network.LocalKeyspace()
+	var subjLocal, objLocal []*protocol.Tombstone
+	for _, tombstone := range tombstones {
+		if localKS.Includes(murmur3.Sum64([]byte(tombstone.Subj))) {
+			subjLocal = append(subjLocal, tombstone)
+		}
+		if localKS.Includes(murmur3.Sum64([]byte(tombstone.Obj))) {
+			objLocal = append(objLocal, tombstone)
+		}
+	}
+	count := 0
+	if len(subjLocal) > 0 {
+		removed, err := s.ts.Erase(subjLocal)
+		if err != nil {
+			return 0, err
+		}
+		count = len(removed)
+		s.Printf("Retracted %d triples.", count)
+	}
+	if len(objLocal) > 0 {
+		if _, err := s.objIndex.Erase(objLocal); err != nil {
+			s.Printf("ERR retracting from object index: %s", err)
+		}
+	}
+	return count, nil
+}
+
+// handleRetractTriples applies the valid tombstones from a peer, responds with
+// the number of triples retracted if asked to and forwards them to the peers
+// it hasn't been sent to.
+func (s *server) handleRetractTriples(conn *network.Conn, msg *protocol.Message) {
+	var valid []*protocol.Tombstone
+	for _, tombstone := range msg.GetRetractTriples().Tombstones {
+		if err := s.verifyTombstone(tombstone); err != nil {
+			s.Printf("ERR tombstone dropped %#v from %s: %s", tombstone, conn.PrettyID(), err)
+			s.recordVerificationFailure(peerSender(conn), tombstone.Pred)
+			continue
+		}
+		valid = append(valid, tombstone)
+	}
+	count := 0
+	var applyErr error
+	if len(valid) > 0 {
+		if count, applyErr = s.applyTombstones(valid); applyErr != nil {
+			s.Printf("ERR retracting triples: %s", applyErr)
+		}
+	}
+	if msg.ResponseRequired {
+		resp := &protocol.Message{
+			Message: &protocol.Message_RetractResponse{
+				RetractResponse: &protocol.RetractResponse{
+					Count: int32(count),
+				},
+			},
+		}
+		if applyErr != nil {
+			resp.Error = applyErr.Error()
+		}
+		if err := conn.RespondTo(msg, resp); err != nil {
+			s.Printf("ERR send RetractResponse %s", err)
+		}
+	}
+
+	if len(valid) == 0 || !msg.Gossip {
+		return
+	}
+	forward := &protocol.Message{
+		Message: &protocol.Message_RetractTriples{
+			RetractTriples: &protocol.RetractTriples{
+				Tombstones: valid,
+			}},
+		Gossip: true,
+		SentTo: msg.SentTo,
+	}
+	sent := make(map[uint64]bool)
+	for _, tombstone := range valid {
+		for _, id := range []string{tombstone.Subj, tombstone.Obj} {
+			hash := murmur3.Sum64([]byte(id))
+			if sent[hash] {
+				continue
+			}
+			sent[hash] = true
+			if err := s.network.Broadcast(&hash, forward); err != nil && err != network.ErrNoRecipients {
+				s.Printf("ERR forwarding tombstones: %s", err)
+			}
+		}
+	}
+}
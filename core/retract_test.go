@@ -0,0 +1,127 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/network"
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestRetractTriples(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+	s.network.SetKeyspace(&protocol.Keyspace{Start: 1, End: 0})
+
+	author, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	delegate, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	triples := protocol.CloneTriples(testTriples)
+	for _, triple := range triples {
+		if err := author.SignTriple(triple); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.ts.Write(triples)
+
+	retract := func(key *crypto.PrivateKey, triple *protocol.Triple) error {
+		tombstone := crypto.NewTombstone(triple)
+		if err := key.SignTombstone(tombstone); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.verifyTombstone(tombstone); err != nil {
+			return err
+		}
+		_, err := s.retractTriples([]*protocol.Tombstone{tombstone})
+		return err
+	}
+
+	if err := retract(delegate, triples[0]); err != ErrNotDelegated {
+		t.Errorf("retract by an undelegated key = %v; not %v", err, ErrNotDelegated)
+	}
+	if err := retract(author, triples[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	delegateID, err := delegate.AuthorID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	delegation, err := author.DelegateTriple(delegateID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := author.SignTriple(delegation); err != nil {
+		t.Fatal(err)
+	}
+	s.ts.Write([]*protocol.Triple{delegation})
+	if err := retract(delegate, triples[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Retracted triples are hidden and can't be inserted again.
+	s.handleInsertTriples(network.NewPeerConn(&protocol.Peer{Id: "test"}), &protocol.Message{
+		Message: &protocol.Message_InsertTriples{
+			InsertTriples: &protocol.InsertTriples{
+				Triples: triples,
+			},
+		},
+	})
+	out, err := s.ts.Query(&protocol.Triple{Author: triples[0].Author}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(protocol.CloneTriples(triples[2:]), delegation)
+	protocol.SortTriples(want)
+	protocol.SortTriples(out)
+	if diff, ok := messagediff.PrettyDiff(want, out); !ok {
+		t.Errorf("Query() after retracting = %#v; diff %s", out, diff)
+	}
+}
+
+func TestHandleRetract(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+	s.network.SetKeyspace(&protocol.Keyspace{Start: 1, End: 0})
+
+	time.Sleep(10 * time.Millisecond)
+	base := fmt.Sprintf("http://localhost:%d", s.network.Port)
+
+	triples := protocol.CloneTriples(testTriples)
+	if err := signTriples(triples, s.crypto); err != nil {
+		t.Fatal(err)
+	}
+	s.ts.Write(triples[:1])
+
+	// Only the stored triple is counted, not every tombstone.
+	body, err := json.Marshal(triples[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(base+"/api/v1/retract", "application/json", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := ioutil.ReadAll(resp.Body)
+	if want := "Retracted 1 triples."; string(out) != want {
+		t.Errorf("http.Post(/api/v1/retract) = %q; not %q", out, want)
+	}
+}
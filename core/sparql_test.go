@@ -0,0 +1,85 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestSPARQL(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	go s.network.Listen()
+	defer s.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	triples := testTriplesKeyspace(s.network.LocalKeyspace())
+	if err := s.signAndInsertTriples(protocol.CloneTriples(triples), s.crypto); err != nil {
+		t.Fatal(err)
+	}
+	var obama string
+	for _, triple := range triples {
+		if triple.Obj == "Barack Obama" {
+			obama = triple.Subj
+		}
+	}
+
+	query := `SELECT ?x ?type WHERE {
+		?x </type/object/name> ?name ;
+		   </type/object/type> ?type .
+		FILTER(?name = "Barack Obama")
+	}`
+	want := map[string]interface{}{
+		"head": map[string]interface{}{"vars": []interface{}{"x", "type"}},
+		"results": map[string]interface{}{"bindings": []interface{}{
+			map[string]interface{}{
+				"x":    map[string]interface{}{"type": "uri", "value": obama},
+				"type": map[string]interface{}{"type": "uri", "value": "/people/person"},
+			},
+		}},
+	}
+
+	base := fmt.Sprintf("http://localhost:%d/api/v1/sparql", s.network.Port)
+	get, err := http.NewRequest("GET", base+"?query="+url.QueryEscape(query), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post, err := http.NewRequest("POST", base, strings.NewReader(query))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post.Header.Set("Content-Type", "application/sparql-query")
+	for _, req := range []*http.Request{get, post} {
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != 200 {
+			t.Fatalf("%s /api/v1/sparql = %d", req.Method, resp.StatusCode)
+		}
+		var out map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatal(err)
+		}
+		if diff, equal := messagediff.PrettyDiff(want, out); !equal {
+			t.Errorf("%s /api/v1/sparql = %+v; not %+v\n%s", req.Method, out, want, diff)
+		}
+	}
+
+	resp, err := http.Get(base + "?query=" + url.QueryEscape("SELECT * WHERE { ?s ?p }"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("GET invalid query = %d; not 400", resp.StatusCode)
+	}
+}
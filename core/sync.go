@@ -0,0 +1,260 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tylertreat/BoomFilters"
+
+	"github.com/degdb/degdb/merkle"
+	"github.com/degdb/degdb/network"
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/triplestore"
+)
+
+var (
+	// SyncInterval is how often the local shard is compared with the peers
+	// sharing it.
+	SyncInterval = time.Minute
+	// MaxSyncRanges is the most differing ranges synced separately. Past
+	// that the whole shard is synced at once.
+	MaxSyncRanges = 8
+)
+
+// syncLoop periodically syncs the local shard with the peers.
+func (s *server) syncLoop() {
+	for s.sleep(SyncInterval) {
+		s.syncPeers()
+	}
+}
+
+// syncPeers syncs the local shard with every peer.
+func (s *server) syncPeers() {
+	for _, conn := range s.network.Conns() {
+		if err := s.syncPeer(conn); err != nil {
+			s.Printf("ERR syncing with %s: %s", conn.PrettyID(), err)
+		}
+	}
+}
+
+// syncPeer compares the Merkle trees of the local shard and a peer sharing part
+// of it and syncs the ranges that differ. The object index is sharded by object
+// hash over the same keyspace, so it's synced with the same peers.
+func (s *server) syncPeer(conn *network.Conn) error {
+	keyspace := s.network.LocalKeyspace()
+	peer := conn.Peer()
+	if keyspace == nil || peer == nil || keyspace.Intersection(peer.Keyspace) == nil {
+		return nil
+	}
+	for _, byObject := range []bool{false, true} {
+		if err := s.syncIndex(conn, keyspace, byObject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncIndex syncs the ranges of the triples, or of the object index if
+// byObject, that differ from a peer.
+func (s *server) syncIndex(conn *network.Conn, keyspace *protocol.Keyspace, byObject bool) error {
+	ranges, err := s.diffPeer(conn, byObject, keyspace, conn.Peer().Keyspace)
+	if err != nil {
+		s.Printf("ERR comparing Merkle trees with %s: %s", conn.PrettyID(), err)
+		ranges = []*protocol.Keyspace{keyspace}
+	} else if len(ranges) > MaxSyncRanges {
+		ranges = []*protocol.Keyspace{keyspace}
+	}
+	for _, r := range ranges {
+		if err := s.bloomSync(conn, r, byObject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexTree returns the Merkle tree of the triples or of the object index.
+func (s *server) indexTree(byObject bool) *merkle.Tree {
+	if byObject {
+		return s.objTree
+	}
+	return s.tree
+}
+
+// diffPeer walks down the Merkle trees of the local node and a peer and
+// returns the ranges of the local keyspace where they differ. Nodes that aren't
+// in both keyspaces can't be compared and are split until they're leaves.
+func (s *server) diffPeer(conn *network.Conn, byObject bool, local, peer *protocol.Keyspace) ([]*protocol.Keyspace, error) {
+	tree := s.indexTree(byObject)
+	depth := tree.Depth()
+	var leaves []uint64
+	nodes := []uint64{0}
+	for level := 0; len(nodes) > 0; level++ {
+		var compare []uint64
+		differ := make(map[uint64]bool)
+		for _, i := range nodes {
+			if !merkle.Intersects(local, level, i) || !merkle.Intersects(peer, level, i) {
+				continue
+			}
+			if merkle.Covers(local, level, i) && merkle.Covers(peer, level, i) {
+				compare = append(compare, i)
+			} else {
+				differ[i] = true
+			}
+		}
+		if len(compare) > 0 {
+			msg, err := conn.Request(&protocol.Message{
+				Message: &protocol.Message_MerkleRequest{
+					MerkleRequest: &protocol.MerkleRequest{
+						Depth:    uint32(depth),
+						Level:    uint32(level),
+						Nodes:    compare,
+						ByObject: byObject,
+					}},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if len(msg.Error) > 0 {
+				return nil, errors.New(msg.Error)
+			}
+			hashes := msg.GetMerkleResponse().GetHashes()
+			if len(hashes) != len(compare) {
+				return nil, fmt.Errorf("got %d Merkle hashes; expected %d", len(hashes), len(compare))
+			}
+			for j, i := range compare {
+				if !bytes.Equal(hashes[j], tree.Hash(level, i)) {
+					differ[i] = true
+				}
+			}
+		}
+
+		// The nodes stay in order so the leaves are too.
+		var next []uint64
+		for _, i := range nodes {
+			if !differ[i] {
+				continue
+			}
+			if level == depth {
+				leaves = append(leaves, i)
+			} else {
+				next = append(next, 2*i, 2*i+1)
+			}
+		}
+		nodes = next
+	}
+
+	// Adjacent leaves are merged into a single range.
+	var ranges []*protocol.Keyspace
+	for _, i := range leaves {
+		r := merkle.Keyspace(depth, i)
+		if n := len(ranges); n > 0 && ranges[n-1].End == r.Start {
+			ranges[n-1].End = r.End
+		} else {
+			ranges = append(ranges, r)
+		}
+	}
+	for i, r := range ranges {
+		ranges[i] = r.Intersection(local)
+	}
+	return ranges, nil
+}
+
+// handleMerkleRequest responds with the hashes of the requested nodes of the
+// local Merkle tree.
+func (s *server) handleMerkleRequest(conn *network.Conn, msg *protocol.Message) {
+	req := msg.GetMerkleRequest()
+	tree := s.indexTree(req.ByObject)
+	resp := &protocol.Message{
+		Message: &protocol.Message_MerkleResponse{
+			MerkleResponse: &protocol.MerkleResponse{},
+		},
+	}
+	if int(req.Depth) != tree.Depth() {
+		resp.Error = fmt.Sprintf("Merkle tree depth %d; not %d", tree.Depth(), req.Depth)
+	} else {
+		for _, i := range req.Nodes {
+			resp.GetMerkleResponse().Hashes = append(resp.GetMerkleResponse().Hashes, tree.Hash(int(req.Level), i))
+		}
+	}
+	if err := conn.RespondTo(msg, resp); err != nil {
+		s.Printf("ERR send MerkleResponse %s", err)
+	}
+}
+
+// bloomSync sends a bloom filter of the local triples in the keyspace, or of
+// the object index if byObject, to a peer. The peer replies with the triples
+// that are missing from the filter.
+func (s *server) bloomSync(conn *network.Conn, keyspace *protocol.Keyspace, byObject bool) error {
+	var filter *boom.ScalableBloomFilter
+	var err error
+	if byObject {
+		filter, err = triplestore.ObjectBloom(s.objIndex, keyspace)
+	} else {
+		filter, err = s.ts.Bloom(keyspace)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := filter.GobEncode()
+	if err != nil {
+		return err
+	}
+	return conn.Send(&protocol.Message{
+		Message: &protocol.Message_BloomSync{
+			BloomSync: &protocol.BloomSync{
+				Keyspace: keyspace,
+				Filter:   data,
+				ByObject: byObject,
+			}},
+	})
+}
+
+// handleBloomSync streams the local triples in the requested keyspace that are
+// missing from the peer's bloom filter back to the peer.
+func (s *server) handleBloomSync(conn *network.Conn, msg *protocol.Message) {
+	req := msg.GetBloomSync()
+	if req.Keyspace == nil {
+		s.Printf("ERR bloom sync from %s without a keyspace", conn.PrettyID())
+		return
+	}
+	filter := &boom.ScalableBloomFilter{}
+	if err := filter.GobDecode(req.Filter); err != nil {
+		s.Printf("ERR decoding bloom filter from %s: %s", conn.PrettyID(), err)
+		return
+	}
+
+	sent := 0
+	var sendErr error
+	var results <-chan []*protocol.Triple
+	var errs <-chan error
+	if req.ByObject {
+		results, errs = triplestore.ObjectTriplesMissingBloom(s.objIndex, filter, req.Keyspace)
+	} else {
+		results, errs = triplestore.TriplesMissingBloom(s.ts, filter, req.Keyspace)
+	}
+	for triples := range results {
+		// Keep draining the results so the stream finishes.
+		if sendErr != nil {
+			continue
+		}
+		sendErr = conn.Send(&protocol.Message{
+			Message: &protocol.Message_InsertTriples{
+				InsertTriples: &protocol.InsertTriples{
+					Triples: triples,
+				}},
+		})
+		if sendErr != nil {
+			s.Printf("ERR sending missing triples to %s: %s", conn.PrettyID(), sendErr)
+			continue
+		}
+		sent += len(triples)
+	}
+	for err := range errs {
+		s.Printf("ERR finding missing triples: %s", err)
+	}
+	if sent > 0 {
+		s.Printf("Synced %d triples to %s.", sent, conn.PrettyID())
+	}
+}
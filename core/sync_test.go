@@ -0,0 +1,190 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/d4l3k/messagediff"
+	"github.com/spaolacci/murmur3"
+
+	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/merkle"
+	"github.com/degdb/degdb/network"
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestBloomSyncOnConnect(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+	s2 := testServer(t)
+	defer s2.Stop()
+	go s.network.Listen()
+	go s2.network.Listen()
+	s.network.ListenWait()
+	s2.network.ListenWait()
+
+	keyspace := s.network.LocalKeyspace()
+	s2.network.SetKeyspace(keyspace)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	triples := testTriplesKeyspace(keyspace)
+	for _, triple := range triples {
+		if err := key.SignTriple(triple); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.ts.Write(triples)
+	s2.ts.Write(triples[:1])
+
+	if err := s2.network.Connect(fmt.Sprintf("localhost:%d", s.network.Port)); err != nil {
+		t.Fatal(err)
+	}
+
+	var synced []*protocol.Triple
+	for i := 0; i < retryCount; i++ {
+		time.Sleep(100 * time.Millisecond)
+		if synced, err = s2.ts.Query(&protocol.Triple{}, -1); err != nil {
+			t.Fatal(err)
+		}
+		if len(synced) == len(triples) {
+			break
+		}
+	}
+	synced = stripCreated(stripSigning(synced))
+	protocol.SortTriples(synced)
+	want := stripCreated(stripSigning(protocol.CloneTriples(triples)))
+	if diff, equal := messagediff.PrettyDiff(want, synced); !equal {
+		t.Errorf("synced triples = %+v; not %+v\n%s", synced, want, diff)
+	}
+}
+
+func TestObjectIndexSyncOnConnect(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+	s2 := testServer(t)
+	defer s2.Stop()
+	go s.network.Listen()
+	go s2.network.Listen()
+	s.network.ListenWait()
+	s2.network.ListenWait()
+
+	keyspace := s.network.LocalKeyspace()
+	s2.network.SetKeyspace(keyspace)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var triples []*protocol.Triple
+	for _, triple := range testTriples {
+		triple := *triple
+		triple.Obj = subjInKeyspace(keyspace, triple.Obj)
+		if err := key.SignTriple(&triple); err != nil {
+			t.Fatal(err)
+		}
+		triples = append(triples, &triple)
+	}
+	s.objIndex.Write(triples)
+
+	if err := s2.network.Connect(fmt.Sprintf("localhost:%d", s.network.Port)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < retryCount && !bytes.Equal(s.objTree.Hash(0, 0), s2.objTree.Hash(0, 0)); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	synced, err := s2.objIndex.Query(&protocol.Triple{}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	synced = stripCreated(stripSigning(synced))
+	protocol.SortTriples(synced)
+	want := stripCreated(stripSigning(protocol.CloneTriples(triples)))
+	protocol.SortTriples(want)
+	if diff, equal := messagediff.PrettyDiff(want, synced); !equal {
+		t.Errorf("synced object index = %+v; not %+v\n%s", synced, want, diff)
+	}
+}
+
+func TestDiffPeer(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t)
+	defer s.Stop()
+	s2 := testServer(t)
+	defer s2.Stop()
+	go s.network.Listen()
+	go s2.network.Listen()
+	s.network.ListenWait()
+	s2.network.ListenWait()
+
+	keyspace := s.network.LocalKeyspace()
+	s2.network.SetKeyspace(keyspace)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	triples := testTriplesKeyspace(keyspace)
+	for _, triple := range triples {
+		if err := key.SignTriple(triple); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.ts.Write(triples)
+	s2.ts.Write(triples[1:])
+
+	if err := s2.network.Connect(fmt.Sprintf("localhost:%d", s.network.Port)); err != nil {
+		t.Fatal(err)
+	}
+	var conn *network.Conn
+	for i := 0; i < retryCount && conn == nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		conn = s2.network.Peers[s.network.LocalID()]
+	}
+	if conn == nil {
+		t.Fatal("peers didn't connect")
+	}
+
+	// Wait for the sync on connect to finish.
+	for i := 0; i < retryCount && !bytes.Equal(s.tree.Hash(0, 0), s2.tree.Hash(0, 0)); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	ranges, err := s2.diffPeer(conn, false, keyspace, keyspace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The leaves at the edges of the keyspace are only partly covered so
+	// they're always synced.
+	for _, r := range ranges {
+		if !r.Includes(keyspace.Start) && !r.Includes(keyspace.End-1) {
+			t.Errorf("diffPeer() = %+v after syncing; expected only the edges of %+v", ranges, keyspace)
+		}
+	}
+
+	extra := &protocol.Triple{Subj: triples[0].Subj, Pred: "/type/object/name", Obj: "unsynced"}
+	s2.ts.Write([]*protocol.Triple{extra})
+	ranges, err = s2.diffPeer(conn, false, keyspace, keyspace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := murmur3.Sum64([]byte(extra.Subj))
+	found := false
+	for _, r := range ranges {
+		if r.Includes(hash) && r.Mag() <= 1<<uint(64-merkle.DefaultDepth) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("diffPeer() = %+v; expected the leaf of %d", ranges, hash)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
+	"encoding/base64"
 	"io"
 	"io/ioutil"
 	"strconv"
@@ -59,30 +60,48 @@ func (key *PrivateKey) Write(path string) error {
 	return nil
 }
 
+// SignTriple sets the author of the triple and signs it. Any existing
+// signature is replaced. The signature is the base64 encoded concatenation of
+// r and s padded to the curve size.
 func (key *PrivateKey) SignTriple(t *protocol.Triple) error {
 	var err error
 	t.Author, err = key.AuthorID()
 	if err != nil {
 		return err
 	}
-	fingerprint, err := FingerprintTriple(t)
+	fingerprint, err := fingerprintUnsigned(t)
 	if err != nil {
 		return err
 	}
+	t.Sig, err = key.sign(fingerprint)
+	return err
+}
 
+// sign returns the base64 encoded concatenation of r and s padded to the curve
+// size for the signature of the fingerprint.
+func (key *PrivateKey) sign(fingerprint []byte) (string, error) {
 	r, s, err := ecdsa.Sign(rand.Reader, (*ecdsa.PrivateKey)(key), fingerprint)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	t.Sig = string(r.Bytes()) + string(s.Bytes())
-	return nil
+	size := curveByteSize()
+	sig := make([]byte, 2*size)
+	rBytes := r.Bytes()
+	sBytes := s.Bytes()
+	copy(sig[size-len(rBytes):size], rBytes)
+	copy(sig[2*size-len(sBytes):], sBytes)
+	return base64.StdEncoding.EncodeToString(sig), nil
 }
 
 // AuthorID generates a unique ID based on the murmur hash of the public key.
 func (key *PrivateKey) AuthorID() (string, error) {
+	return authorID(&(*ecdsa.PrivateKey)(key).PublicKey)
+}
+
+func authorID(pub *ecdsa.PublicKey) (string, error) {
 	hasher := murmur3.New64()
-	buf, err := x509.MarshalPKIXPublicKey(&(*ecdsa.PrivateKey)(key).PublicKey)
+	buf, err := x509.MarshalPKIXPublicKey(pub)
 	if err != nil {
 		return "", err
 	}
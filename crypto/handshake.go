@@ -0,0 +1,66 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha1"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// SignHandshake signs the handshake with the key. The sender's author and key
+// pin must be the key's.
+func (key *PrivateKey) SignHandshake(h *protocol.Handshake) error {
+	author, err := key.AuthorID()
+	if err != nil {
+		return err
+	}
+	pin, err := key.KeyPin()
+	if err != nil {
+		return err
+	}
+	if h.Sender == nil || h.Sender.Author != author || h.Sender.KeyPin != pin {
+		return ErrAuthorMismatch
+	}
+	fingerprint, err := fingerprintHandshake(h)
+	if err != nil {
+		return err
+	}
+	h.Sig, err = key.sign(fingerprint)
+	return err
+}
+
+// VerifyHandshake checks that h.Sig is a valid signature of the handshake made
+// by the key with the sender's author and key pin.
+func VerifyHandshake(h *protocol.Handshake) error {
+	if h.Sender == nil || len(h.Sender.Author) == 0 || len(h.Sender.KeyPin) == 0 || len(h.Sig) == 0 {
+		return ErrMissingSignature
+	}
+	fingerprint, err := fingerprintHandshake(h)
+	if err != nil {
+		return err
+	}
+	return verifySignatureBy(fingerprint, h.Sig, func(pub *ecdsa.PublicKey) (bool, error) {
+		author, err := authorID(pub)
+		if err != nil {
+			return false, err
+		}
+		pin, err := keyPin(pub)
+		if err != nil {
+			return false, err
+		}
+		return author == h.Sender.Author && pin == h.Sender.KeyPin, nil
+	})
+}
+
+// fingerprintHandshake returns the SHA-1 hash of the handshake without the
+// signature.
+func fingerprintHandshake(h *protocol.Handshake) ([]byte, error) {
+	unsigned := *h
+	unsigned.Sig = ""
+	data, err := unsigned.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(data)
+	return sum[:], nil
+}
@@ -0,0 +1,70 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestVerifyHandshake(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	author, err := key.AuthorID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pin, err := key.KeyPin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherAuthor, err := otherKey.AuthorID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPin, err := otherKey.KeyPin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		tamper func(h *protocol.Handshake)
+		want   error
+	}{
+		{func(h *protocol.Handshake) {}, nil},
+		{func(h *protocol.Handshake) { h.To = "c:1" }, ErrAuthorMismatch},
+		{func(h *protocol.Handshake) { h.Created++ }, ErrAuthorMismatch},
+		{func(h *protocol.Handshake) { h.Sender.Id = "c:1" }, ErrAuthorMismatch},
+		{func(h *protocol.Handshake) { h.Sender.Author = otherAuthor }, ErrAuthorMismatch},
+		{func(h *protocol.Handshake) { h.Sender.KeyPin = otherPin }, ErrAuthorMismatch},
+		{func(h *protocol.Handshake) { h.Sig = "" }, ErrMissingSignature},
+		{func(h *protocol.Handshake) { h.Sender.KeyPin = "" }, ErrMissingSignature},
+	}
+	for i, td := range testData {
+		h := &protocol.Handshake{
+			Sender:  &protocol.Peer{Id: "a:1", Author: author, KeyPin: pin},
+			To:      "b:1",
+			Created: 10,
+		}
+		if err := key.SignHandshake(h); err != nil {
+			t.Fatal(err)
+		}
+		td.tamper(h)
+		if err := VerifyHandshake(h); err != td.want {
+			t.Errorf("%d. VerifyHandshake(%+v) = %+v; not %+v", i, h, err, td.want)
+		}
+	}
+
+	// Keys can only sign handshakes from their author.
+	h := &protocol.Handshake{Sender: &protocol.Peer{Id: "a:1", Author: author, KeyPin: pin}}
+	if err := otherKey.SignHandshake(h); err != ErrAuthorMismatch {
+		t.Errorf("SignHandshake() by another key = %+v; not %+v", err, ErrAuthorMismatch)
+	}
+}
@@ -0,0 +1,71 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// PublicKeyPred is the predicate used to publish an author's public key. The
+// subject is the author ID and the object is the base64 encoded PKIX public
+// key.
+const PublicKeyPred = "degdb:public_key"
+
+var ErrNotECDSAKey = errors.New("public key is not an ECDSA key")
+
+// PublicKeyTriple returns an unsigned triple that publishes the public key of
+// key under its author ID.
+func (key *PrivateKey) PublicKeyTriple() (*protocol.Triple, error) {
+	author, err := key.AuthorID()
+	if err != nil {
+		return nil, err
+	}
+	buf, err := x509.MarshalPKIXPublicKey(&(*ecdsa.PrivateKey)(key).PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &protocol.Triple{
+		Subj: author,
+		Pred: PublicKeyPred,
+		Obj:  base64.StdEncoding.EncodeToString(buf),
+	}, nil
+}
+
+// ParsePublicKey parses a base64 encoded PKIX public key as found in the
+// object of a PublicKeyPred triple.
+func ParsePublicKey(encoded string) (*ecdsa.PublicKey, error) {
+	buf, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(buf)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, ErrNotECDSAKey
+	}
+	return pub, nil
+}
+
+// PublicKeyAuthorID returns the author ID for a public key.
+func PublicKeyAuthorID(pub *ecdsa.PublicKey) (string, error) {
+	return authorID(pub)
+}
+
+// verifyPublicKeyTriple checks that a PublicKeyPred triple is self-describing:
+// it must be about its own author and be signed by the key it contains.
+func verifyPublicKeyTriple(t *protocol.Triple) error {
+	if t.Subj != t.Author {
+		return ErrAuthorMismatch
+	}
+	pub, err := ParsePublicKey(t.Obj)
+	if err != nil {
+		return err
+	}
+	return VerifyTripleWithKey(t, pub)
+}
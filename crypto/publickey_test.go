@@ -0,0 +1,85 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"testing"
+)
+
+func TestPublicKeyTriple(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	triple, err := key.PublicKeyTriple()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := key.SignTriple(triple); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyTriple(triple); err != nil {
+		t.Errorf("VerifyTriple(%+v) = %+v", triple, err)
+	}
+
+	pub, err := ParsePublicKey(triple.Obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &(*ecdsa.PrivateKey)(key).PublicKey
+	if pub.X.Cmp(want.X) != 0 || pub.Y.Cmp(want.Y) != 0 {
+		t.Errorf("ParsePublicKey(%#v) = %+v; not %+v", triple.Obj, pub, want)
+	}
+	author, err := PublicKeyAuthorID(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if author != triple.Subj {
+		t.Errorf("PublicKeyAuthorID(%+v) = %#v; not %#v", pub, author, triple.Subj)
+	}
+
+	if err := VerifyTripleWithKey(triple, &(*ecdsa.PrivateKey)(otherKey).PublicKey); err != ErrAuthorMismatch {
+		t.Errorf("VerifyTripleWithKey(%+v, otherKey) = %+v; not %+v", triple, err, ErrAuthorMismatch)
+	}
+
+	// A key can't be published for a different author.
+	otherTriple, err := otherKey.PublicKeyTriple()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherTriple.Subj = triple.Subj
+	if err := key.SignTriple(otherTriple); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyTriple(otherTriple); err == nil {
+		t.Errorf("VerifyTriple(%+v) = nil; expected error", otherTriple)
+	}
+
+	// An author can't publish someone else's key.
+	otherTriple, err = otherKey.PublicKeyTriple()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := key.SignTriple(otherTriple); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyTriple(otherTriple); err != ErrAuthorMismatch {
+		t.Errorf("VerifyTriple(%+v) = %+v; not %+v", otherTriple, err, ErrAuthorMismatch)
+	}
+}
+
+func TestParsePublicKeyInvalid(t *testing.T) {
+	t.Parallel()
+
+	for i, in := range []string{"", "not base64!", "Zm9v"} {
+		if _, err := ParsePublicKey(in); err == nil {
+			t.Errorf("%d. ParsePublicKey(%#v) = nil; expected error", i, in)
+		}
+	}
+}
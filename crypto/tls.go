@@ -0,0 +1,75 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"time"
+)
+
+// Certificate returns a self-signed TLS certificate for the key. Nodes don't
+// use a CA, the peer's key is checked against its key pin instead.
+func (key *PrivateKey) Certificate() (tls.Certificate, error) {
+	author, err := key.AuthorID()
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: author},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(10, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	priv := (*ecdsa.PrivateKey)(key)
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  priv,
+	}, nil
+}
+
+// CertificateKey returns the author ID and the key pin of the key a
+// certificate is for.
+func CertificateKey(cert *x509.Certificate) (author, pin string, err error) {
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != ellipticCurve {
+		return "", "", ErrNotECDSAKey
+	}
+	if author, err = authorID(pub); err != nil {
+		return "", "", err
+	}
+	if pin, err = keyPin(pub); err != nil {
+		return "", "", err
+	}
+	return author, pin, nil
+}
+
+// KeyPin returns the hex SHA-256 hash of the DER encoded public key. Unlike the
+// 64 bit author ID, it's long enough to pin peer connections to.
+func (key *PrivateKey) KeyPin() (string, error) {
+	return keyPin(&(*ecdsa.PrivateKey)(key).PublicKey)
+}
+
+func keyPin(pub *ecdsa.PublicKey) (string, error) {
+	buf, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(buf)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,45 @@
+package crypto
+
+import (
+	"crypto/x509"
+	"testing"
+)
+
+func TestCertificate(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := key.Certificate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsed.CheckSignature(parsed.SignatureAlgorithm, parsed.RawTBSCertificate, parsed.Signature); err != nil {
+		t.Errorf("certificate isn't self-signed: %s", err)
+	}
+
+	author, pin, err := CertificateKey(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantAuthor, err := key.AuthorID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPin, err := key.KeyPin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if author != wantAuthor || pin != wantPin {
+		t.Errorf("CertificateKey() = %q, %q; not %q, %q", author, pin, wantAuthor, wantPin)
+	}
+	if len(pin) != 64 {
+		t.Errorf("KeyPin() = %q; not a hex SHA-256 hash", pin)
+	}
+}
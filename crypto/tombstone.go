@@ -0,0 +1,82 @@
+package crypto
+
+import (
+	"crypto/sha1"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// DelegatePred is the predicate an author uses to let another key retract its
+// triples. The subject is the author ID and the object is the author ID of the
+// delegated key. It must be signed by the subject.
+const DelegatePred = "degdb:delegate"
+
+// NewTombstone returns an unsigned tombstone retracting the triple.
+func NewTombstone(t *protocol.Triple) *protocol.Tombstone {
+	return &protocol.Tombstone{
+		Subj:   t.Subj,
+		Pred:   t.Pred,
+		Obj:    t.Obj,
+		Author: t.Author,
+	}
+}
+
+// SignTombstone sets the signer of the tombstone and signs it. If the
+// tombstone has no author, the key is the author.
+func (key *PrivateKey) SignTombstone(t *protocol.Tombstone) error {
+	var err error
+	t.Signer, err = key.AuthorID()
+	if err != nil {
+		return err
+	}
+	if len(t.Author) == 0 {
+		t.Author = t.Signer
+	}
+	fingerprint, err := fingerprintTombstone(t)
+	if err != nil {
+		return err
+	}
+	t.Sig, err = key.sign(fingerprint)
+	return err
+}
+
+// VerifyTombstone checks that t.Sig is a valid signature of the tombstone made
+// by the key belonging to t.Signer. Whether the signer may retract the author's
+// triples is up to the caller, see DelegatePred.
+func VerifyTombstone(t *protocol.Tombstone) error {
+	if len(t.Author) == 0 || len(t.Signer) == 0 || len(t.Sig) == 0 {
+		return ErrMissingSignature
+	}
+	fingerprint, err := fingerprintTombstone(t)
+	if err != nil {
+		return err
+	}
+	return verifySignature(fingerprint, t.Sig, t.Signer)
+}
+
+// DelegateTriple returns an unsigned triple delegating the retraction of the
+// key's triples to the delegate author ID.
+func (key *PrivateKey) DelegateTriple(delegate string) (*protocol.Triple, error) {
+	author, err := key.AuthorID()
+	if err != nil {
+		return nil, err
+	}
+	return &protocol.Triple{
+		Subj: author,
+		Pred: DelegatePred,
+		Obj:  delegate,
+	}, nil
+}
+
+// fingerprintTombstone returns the SHA-1 hash of the tombstone without the
+// signature.
+func fingerprintTombstone(t *protocol.Tombstone) ([]byte, error) {
+	unsigned := *t
+	unsigned.Sig = ""
+	data, err := unsigned.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum(data)
+	return sum[:], nil
+}
@@ -0,0 +1,60 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestVerifyTombstone(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherAuthor, err := otherKey.AuthorID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		tamper func(t *protocol.Tombstone)
+		want   error
+	}{
+		{func(t *protocol.Tombstone) {}, nil},
+		{func(t *protocol.Tombstone) { t.Obj = "d" }, ErrAuthorMismatch},
+		{func(t *protocol.Tombstone) { t.Author = otherAuthor }, ErrAuthorMismatch},
+		{func(t *protocol.Tombstone) { t.Signer = otherAuthor }, ErrAuthorMismatch},
+		{func(t *protocol.Tombstone) { t.Sig = "" }, ErrMissingSignature},
+	}
+	for i, td := range testData {
+		tombstone := NewTombstone(&protocol.Triple{Subj: "a", Pred: "b", Obj: "c"})
+		if err := key.SignTombstone(tombstone); err != nil {
+			t.Fatal(err)
+		}
+		if tombstone.Author != tombstone.Signer {
+			t.Errorf("%d. SignTombstone() author = %q; not the signer %q", i, tombstone.Author, tombstone.Signer)
+		}
+		td.tamper(tombstone)
+		if err := VerifyTombstone(tombstone); err != td.want {
+			t.Errorf("%d. VerifyTombstone(%+v) = %+v; not %+v", i, tombstone, err, td.want)
+		}
+	}
+
+	// A triple signature doesn't verify as a tombstone of the triple.
+	triple := &protocol.Triple{Subj: "a", Pred: "b", Obj: "c"}
+	if err := key.SignTriple(triple); err != nil {
+		t.Fatal(err)
+	}
+	tombstone := NewTombstone(triple)
+	tombstone.Signer = triple.Author
+	tombstone.Sig = triple.Sig
+	if err := VerifyTombstone(tombstone); err == nil {
+		t.Errorf("VerifyTombstone(%+v) with the triple signature = nil", tombstone)
+	}
+}
@@ -0,0 +1,205 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+var (
+	ErrMissingSignature = errors.New("triple is missing an author or signature")
+	ErrInvalidSignature = errors.New("triple signature is malformed")
+	ErrAuthorMismatch   = errors.New("triple signature does not match the author")
+)
+
+// VerifyTriple checks that t.Sig is a valid signature of the triple made by
+// the key belonging to t.Author. Since author IDs are hashes of the public
+// key, the candidate public keys are recovered from the signature and checked
+// against the author ID.
+func VerifyTriple(t *protocol.Triple) error {
+	if len(t.Author) == 0 || len(t.Sig) == 0 {
+		return ErrMissingSignature
+	}
+	if t.Pred == PublicKeyPred {
+		return verifyPublicKeyTriple(t)
+	}
+	fingerprint, err := fingerprintUnsigned(t)
+	if err != nil {
+		return err
+	}
+	return verifySignature(fingerprint, t.Sig, t.Author)
+}
+
+// verifySignature checks that sig is a valid signature of the fingerprint made
+// by the key belonging to author.
+func verifySignature(fingerprint []byte, sig, author string) error {
+	return verifySignatureBy(fingerprint, sig, func(pub *ecdsa.PublicKey) (bool, error) {
+		id, err := authorID(pub)
+		return id == author, err
+	})
+}
+
+// verifySignatureBy checks that sig is a valid signature of the fingerprint
+// made by a key that match accepts.
+func verifySignatureBy(fingerprint []byte, sig string, match func(*ecdsa.PublicKey) (bool, error)) error {
+	sigs := decodeSignature(sig)
+	if len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+	for _, sig := range sigs {
+		for _, pub := range recoverPublicKeys(fingerprint, sig[0], sig[1]) {
+			ok, err := match(pub)
+			if err != nil {
+				return err
+			}
+			if ok && ecdsa.Verify(pub, fingerprint, sig[0], sig[1]) {
+				return nil
+			}
+		}
+	}
+	return ErrAuthorMismatch
+}
+
+// VerifyTripleWithKey checks that t.Sig is a valid signature made by pub and
+// that pub belongs to t.Author. This is faster than VerifyTriple when the key
+// is already known.
+func VerifyTripleWithKey(t *protocol.Triple, pub *ecdsa.PublicKey) error {
+	if len(t.Author) == 0 || len(t.Sig) == 0 {
+		return ErrMissingSignature
+	}
+	author, err := authorID(pub)
+	if err != nil {
+		return err
+	}
+	if author != t.Author {
+		return ErrAuthorMismatch
+	}
+	fingerprint, err := fingerprintUnsigned(t)
+	if err != nil {
+		return err
+	}
+	sigs := decodeSignature(t.Sig)
+	if len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+	for _, sig := range sigs {
+		if ecdsa.Verify(pub, fingerprint, sig[0], sig[1]) {
+			return nil
+		}
+	}
+	return ErrAuthorMismatch
+}
+
+// fingerprintUnsigned returns the fingerprint of the triple with the signature
+// removed. This is the value that is signed by SignTriple.
+func fingerprintUnsigned(t *protocol.Triple) ([]byte, error) {
+	unsigned := *t
+	unsigned.Sig = ""
+	return FingerprintTriple(&unsigned)
+}
+
+// decodeSignature returns the possible (r, s) pairs encoded in sig. Signatures
+// are base64 encoded, but older signatures were the raw concatenation of r and
+// s without padding so every valid split point of those is returned.
+func decodeSignature(sig string) [][2]*big.Int {
+	if buf, err := base64.StdEncoding.DecodeString(sig); err == nil && len(buf) == 2*curveByteSize() {
+		return splitSignature(buf)
+	}
+	return splitSignature([]byte(sig))
+}
+
+// splitSignature returns the (r, s) pairs for each possible split of sig.
+func splitSignature(sig []byte) [][2]*big.Int {
+	size := curveByteSize()
+	if len(sig) > 2*size {
+		return nil
+	}
+	var sigs [][2]*big.Int
+	for i := len(sig) - size; i <= size; i++ {
+		if i <= 0 || i >= len(sig) {
+			continue
+		}
+		if len(sig) == 2*size && i != size {
+			continue
+		}
+		r := new(big.Int).SetBytes(sig[:i])
+		s := new(big.Int).SetBytes(sig[i:])
+		sigs = append(sigs, [2]*big.Int{r, s})
+	}
+	return sigs
+}
+
+// recoverPublicKeys returns the public keys that could have produced the
+// signature (r, s) of hash.
+func recoverPublicKeys(hash []byte, r, s *big.Int) []*ecdsa.PublicKey {
+	params := ellipticCurve.Params()
+	n := params.N
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(n) >= 0 || s.Cmp(n) >= 0 {
+		return nil
+	}
+	e := hashToInt(hash)
+	rInv := new(big.Int).ModInverse(r, n)
+	if rInv == nil {
+		return nil
+	}
+
+	// y² = x³ - 3x + b
+	x := new(big.Int).Set(r)
+	if x.Cmp(params.P) >= 0 {
+		return nil
+	}
+	y2 := new(big.Int).Mul(x, x)
+	y2.Mul(y2, x)
+	threeX := new(big.Int).Lsh(x, 1)
+	threeX.Add(threeX, x)
+	y2.Sub(y2, threeX)
+	y2.Add(y2, params.B)
+	y2.Mod(y2, params.P)
+	y := new(big.Int).ModSqrt(y2, params.P)
+	if y == nil {
+		return nil
+	}
+
+	// Q = r⁻¹(sR - eG)
+	eGx, eGy := ellipticCurve.ScalarBaseMult(new(big.Int).Mod(e, n).Bytes())
+	eGy.Sub(params.P, eGy)
+	v := rInv.Bytes()
+
+	var keys []*ecdsa.PublicKey
+	for _, ry := range []*big.Int{y, new(big.Int).Sub(params.P, y)} {
+		sRx, sRy := ellipticCurve.ScalarMult(x, ry, s.Bytes())
+		if sRx.Cmp(eGx) == 0 && sRy.Cmp(eGy) != 0 {
+			// sR = eG so the sum is the point at infinity.
+			continue
+		}
+		qx, qy := ellipticCurve.Add(sRx, sRy, eGx, eGy)
+		qx, qy = ellipticCurve.ScalarMult(qx, qy, v)
+		if qx.Sign() == 0 && qy.Sign() == 0 {
+			continue
+		}
+		keys = append(keys, &ecdsa.PublicKey{Curve: ellipticCurve, X: qx, Y: qy})
+	}
+	return keys
+}
+
+// hashToInt converts a hash to an integer the same way crypto/ecdsa does.
+func hashToInt(hash []byte) *big.Int {
+	orderBits := ellipticCurve.Params().N.BitLen()
+	orderBytes := (orderBits + 7) / 8
+	if len(hash) > orderBytes {
+		hash = hash[:orderBytes]
+	}
+	ret := new(big.Int).SetBytes(hash)
+	if excess := len(hash)*8 - orderBits; excess > 0 {
+		ret.Rsh(ret, uint(excess))
+	}
+	return ret
+}
+
+// curveByteSize returns the number of bytes needed to store a scalar.
+func curveByteSize() int {
+	return (ellipticCurve.Params().N.BitLen() + 7) / 8
+}
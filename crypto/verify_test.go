@@ -0,0 +1,158 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"testing"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestVerifyTriple(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherAuthor, err := otherKey.AuthorID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func() *protocol.Triple {
+		triple := &protocol.Triple{
+			Subj:    "/m/02mjmr",
+			Pred:    "/type/object/name",
+			Obj:     "Barack Obama",
+			Lang:    "en",
+			Created: 1445000000,
+		}
+		if err := key.SignTriple(triple); err != nil {
+			t.Fatal(err)
+		}
+		return triple
+	}
+
+	testData := []struct {
+		tamper func(*protocol.Triple)
+		want   error
+	}{
+		{
+			func(*protocol.Triple) {},
+			nil,
+		},
+		{
+			func(t *protocol.Triple) { t.Obj = "Hume" },
+			ErrAuthorMismatch,
+		},
+		{
+			func(t *protocol.Triple) { t.Created++ },
+			ErrAuthorMismatch,
+		},
+		{
+			func(t *protocol.Triple) { t.Author = otherAuthor },
+			ErrAuthorMismatch,
+		},
+		{
+			func(t *protocol.Triple) { t.Sig = "" },
+			ErrMissingSignature,
+		},
+		{
+			func(t *protocol.Triple) { t.Author = "" },
+			ErrMissingSignature,
+		},
+		{
+			func(t *protocol.Triple) { t.Sig += "extra bytes that don't fit" },
+			ErrInvalidSignature,
+		},
+		{
+			func(t *protocol.Triple) {
+				flipped := "A"
+				if t.Sig[10:11] == flipped {
+					flipped = "B"
+				}
+				t.Sig = t.Sig[:10] + flipped + t.Sig[11:]
+			},
+			ErrAuthorMismatch,
+		},
+	}
+	for i, td := range testData {
+		triple := sign()
+		td.tamper(triple)
+		if err := VerifyTriple(triple); err != td.want {
+			t.Errorf("%d. VerifyTriple(%+v) = %+v; not %+v", i, triple, err, td.want)
+		}
+	}
+}
+
+func TestVerifyTripleResign(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	triple := &protocol.Triple{Subj: "a", Pred: "b", Obj: "c"}
+	for i := 0; i < 2; i++ {
+		if err := key.SignTriple(triple); err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyTriple(triple); err != nil {
+			t.Errorf("%d. VerifyTriple(%+v) = %+v", i, triple, err)
+		}
+	}
+}
+
+// TestVerifyTripleUnpadded checks that signatures made before r and s were
+// padded to the curve size still verify.
+func TestVerifyTripleUnpadded(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	triple := &protocol.Triple{Subj: "a", Pred: "b", Obj: "c"}
+	if triple.Author, err = key.AuthorID(); err != nil {
+		t.Fatal(err)
+	}
+	fingerprint, err := FingerprintTriple(triple)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		r, s, err := ecdsa.Sign(rand.Reader, (*ecdsa.PrivateKey)(key), fingerprint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		triple.Sig = string(r.Bytes()) + string(s.Bytes())
+		if err := VerifyTriple(triple); err != nil {
+			t.Errorf("%d. VerifyTriple(%+v) = %+v", i, triple, err)
+		}
+	}
+}
+
+func BenchmarkVerifyTriple(b *testing.B) {
+	triple := &protocol.Triple{
+		Subj: "/m/02mjmr",
+		Pred: "/type/object/name",
+		Obj:  "Barack Obama",
+		Lang: "en",
+	}
+	key, err := GenerateKey()
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := key.SignTriple(triple); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		VerifyTriple(triple)
+	}
+}
@@ -1,13 +1,23 @@
 package main
 
 import (
+	"compress/bzip2"
+	"compress/gzip"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/degdb/degdb/core"
+	"github.com/degdb/degdb/network"
+	"github.com/degdb/degdb/rdf"
+	"github.com/degdb/degdb/triplestore"
 	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 )
@@ -17,17 +27,47 @@ var (
 	initialPeers = flag.String("peers", "", "CSV list of peers to connect to.")
 	diskAllowed  = flag.String("disk", "1G", "Amount of disk space to allocate.")
 	nodes        = flag.Int("nodes", 1, "Number of nodes to launch in this binary. Development use only. Disables external connections.")
+	importPath   = flag.String("import", "", "N-Triples or N-Quads file to import through the node listening on -port. Doesn't launch a node.")
+	replication  = flag.Int("replication", 1, "Number of replicas that must hold a triple before an insert succeeds. Must be the same on every node.")
+	trusted      = flag.String("trusted", "", "CSV list of author IDs the trust resolution strategy trusts fully.")
+	advertise    = flag.String("advertise", "", "Host to advertise to peers. Overrides -address.")
+	address      = flag.String("address", "public,interface", "Comma separated strategies tried in order to find the host to advertise: "+strings.Join(network.AddressStrategyNames, ", ")+". peers asks the -peers for the address they see.")
+	storage      = flag.String("storage", "sqlite", "Triplestore backend to use: "+strings.Join(triplestore.Backends, ", ")+".")
+	baseIRI      = flag.String("base", rdf.BaseIRI, "Base IRI that relative ids are exported under and stripped from on import.")
 )
 
 func main() {
 	flag.Parse()
 	color.NoColor = false
 
+	if len(*importPath) > 0 {
+		if err := importFile(*importPath, *bindPort); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	var peers []string
 	if len(*initialPeers) > 0 && *nodes == 1 {
 		peers = strings.Split(*initialPeers, ",")
 	}
 
+	if len(*advertise) > 0 {
+		network.AddressStrategies = []network.AddressStrategy{network.Advertise(*advertise)}
+	} else {
+		strategies, err := network.ParseAddressStrategies(*address, peers)
+		if err != nil {
+			log.Fatal(err)
+		}
+		network.AddressStrategies = strategies
+	}
+	core.StorageBackend = *storage
+	core.ReplicationFactor = *replication
+	rdf.BaseIRI = *baseIRI
+	if len(*trusted) > 0 {
+		core.TrustedAuthors = strings.Split(*trusted, ",")
+	}
+
 	diskFloat, _, err := humanize.ParseSI(*diskAllowed)
 	if err != nil {
 		log.Fatal(err)
@@ -47,3 +87,69 @@ func main() {
 	}
 	wg.Wait()
 }
+
+// importFile streams an N-Triples or N-Quads file to the import endpoint of the
+// node listening on port. Files ending in .gz or .bz2 are decompressed first.
+func importFile(path string, port int) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	progress := &progressReader{Reader: f, total: uint64(info.Size()), last: time.Now()}
+	var r io.Reader = progress
+	switch {
+	case strings.HasSuffix(path, ".gz"):
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	case strings.HasSuffix(path, ".bz2"):
+		r = bzip2.NewReader(r)
+	}
+
+	url := fmt.Sprintf("http://localhost:%d/api/v1/import", port)
+	log.Printf("Importing %s (%s) to %s", path, humanize.Bytes(progress.total), url)
+	resp, err := http.Post(url, "application/n-triples", r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("import failed: %s", strings.TrimSpace(string(body)))
+	}
+	log.Printf("Import finished: %s", strings.TrimSpace(string(body)))
+	return nil
+}
+
+// progressReader logs how much of the underlying reader has been read.
+type progressReader struct {
+	io.Reader
+	read, total uint64
+	last        time.Time
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.Reader.Read(b)
+	p.read += uint64(n)
+	if time.Since(p.last) > 5*time.Second || err == io.EOF {
+		p.last = time.Now()
+		percent := 100.0
+		if p.total > 0 {
+			percent = float64(p.read) / float64(p.total) * 100
+		}
+		log.Printf("Sent %s of %s (%.1f%%)", humanize.Bytes(p.read), humanize.Bytes(p.total), percent)
+	}
+	return n, err
+}
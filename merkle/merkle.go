@@ -0,0 +1,177 @@
+// Package merkle provides Merkle trees summarizing the triples a node holds so
+// replicas can find the parts of the keyspace where they differ without
+// comparing every triple.
+package merkle
+
+import (
+	"crypto/sha1"
+	"math"
+	"sync"
+
+	"github.com/spaolacci/murmur3"
+
+	"github.com/degdb/degdb/crypto"
+	"github.com/degdb/degdb/protocol"
+)
+
+// DefaultDepth is the depth of the trees nodes use. Both sides of a
+// comparison need the same depth. It splits the keyspace into 4096 buckets.
+const DefaultDepth = 12
+
+// Tree is a Merkle tree over the keyspace. The leaves are buckets of the
+// murmur3 subject hash, or the object hash for the object index, and hold the XOR of the fingerprints of their triples
+// so triples can be added and removed in any order. Inner nodes are the SHA-1
+// of their children and are only recomputed when read.
+type Tree struct {
+	depth int
+	key   func(*protocol.Triple) string
+
+	mu sync.Mutex
+	// nodes[level][index] is the hash of a node. Level 0 is the root and
+	// level depth holds the leaves.
+	nodes [][][]byte
+	dirty [][]bool
+}
+
+// New returns an empty tree with the specified depth over the subject hashes.
+func New(depth int) *Tree {
+	return newTree(depth, func(triple *protocol.Triple) string { return triple.Subj })
+}
+
+// NewObject returns an empty tree with the specified depth over the object
+// hashes.
+func NewObject(depth int) *Tree {
+	return newTree(depth, func(triple *protocol.Triple) string { return triple.Obj })
+}
+
+func newTree(depth int, key func(*protocol.Triple) string) *Tree {
+	t := &Tree{depth: depth, key: key}
+	for level := 0; level <= depth; level++ {
+		t.nodes = append(t.nodes, make([][]byte, 1<<uint(level)))
+		t.dirty = append(t.dirty, make([]bool, 1<<uint(level)))
+	}
+	for i := range t.nodes[depth] {
+		t.nodes[depth][i] = make([]byte, sha1.Size)
+	}
+	for level := 0; level < depth; level++ {
+		for i := range t.dirty[level] {
+			t.dirty[level][i] = true
+		}
+	}
+	return t
+}
+
+// Depth returns the level of the leaves.
+func (t *Tree) Depth() int {
+	return t.depth
+}
+
+// Add adds a triple to the leaf of its hash.
+func (t *Tree) Add(triple *protocol.Triple) error {
+	return t.toggle(triple)
+}
+
+// Remove removes a triple previously added from the leaf of its hash.
+func (t *Tree) Remove(triple *protocol.Triple) error {
+	return t.toggle(triple)
+}
+
+func (t *Tree) toggle(triple *protocol.Triple) error {
+	fingerprint, err := crypto.FingerprintTriple(triple)
+	if err != nil {
+		return err
+	}
+	index := t.Leaf(murmur3.Sum64([]byte(t.key(triple))))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	leaf := t.nodes[t.depth][index]
+	for i, b := range fingerprint {
+		leaf[i] ^= b
+	}
+	for level := t.depth - 1; level >= 0; level-- {
+		index /= 2
+		t.dirty[level][index] = true
+	}
+	return nil
+}
+
+// Leaf returns the index of the leaf the hash belongs to.
+func (t *Tree) Leaf(hash uint64) uint64 {
+	if t.depth == 0 {
+		return 0
+	}
+	return hash >> uint(64-t.depth)
+}
+
+// Empty returns whether the leaf holds no triples.
+func (t *Tree) Empty(index uint64) bool {
+	for _, b := range t.Hash(t.depth, index) {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Hash returns the hash of a node. It returns nil if there is no such node.
+func (t *Tree) Hash(level int, index uint64) []byte {
+	if level < 0 || level > t.depth || index >= uint64(len(t.nodes[level])) {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	hash := t.hash(level, index)
+	return append([]byte(nil), hash...)
+}
+
+func (t *Tree) hash(level int, index uint64) []byte {
+	if level == t.depth || !t.dirty[level][index] {
+		return t.nodes[level][index]
+	}
+	h := sha1.New()
+	h.Write(t.hash(level+1, 2*index))
+	h.Write(t.hash(level+1, 2*index+1))
+	t.nodes[level][index] = h.Sum(nil)
+	t.dirty[level][index] = false
+	return t.nodes[level][index]
+}
+
+// Range returns the first and last hash covered by a node.
+func Range(level int, index uint64) (first, last uint64) {
+	if level == 0 {
+		return 0, math.MaxUint64
+	}
+	first = index << uint(64-level)
+	return first, first | math.MaxUint64>>uint(level)
+}
+
+// Keyspace returns the keyspace covered by a node. The root covers all but the
+// last hash since a keyspace can't cover the entire ring.
+func Keyspace(level int, index uint64) *protocol.Keyspace {
+	first, last := Range(level, index)
+	if level == 0 {
+		return &protocol.Keyspace{Start: first, End: last}
+	}
+	return &protocol.Keyspace{Start: first, End: last + 1}
+}
+
+// Covers returns whether every hash of the node is in the keyspace.
+func Covers(k *protocol.Keyspace, level int, index uint64) bool {
+	first, last := Range(level, index)
+	if !k.Includes(first) || !k.Includes(last) {
+		return false
+	}
+	// The end of the keyspace mustn't fall inside the node.
+	offset := k.End - first
+	return offset == 0 || offset > last-first
+}
+
+// Intersects returns whether any hash of the node is in the keyspace.
+func Intersects(k *protocol.Keyspace, level int, index uint64) bool {
+	if k == nil {
+		return false
+	}
+	first, last := Range(level, index)
+	return k.Includes(first) || k.Includes(last) || k.Start-first <= last-first
+}
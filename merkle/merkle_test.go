@@ -0,0 +1,142 @@
+package merkle
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/spaolacci/murmur3"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+var testTriples = []*protocol.Triple{
+	{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Barack Obama"},
+	{Subj: "/m/02mjmr", Pred: "/type/object/type", Obj: "/people/person"},
+	{Subj: "/m/0hume", Pred: "/type/object/name", Obj: "Hume"},
+	{Subj: "/m/0hume", Pred: "/type/object/type", Obj: "/people/person"},
+}
+
+func TestTree(t *testing.T) {
+	t.Parallel()
+
+	a := New(4)
+	b := New(4)
+	empty := a.Hash(0, 0)
+	for _, triple := range testTriples {
+		if err := a.Add(triple); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := len(testTriples) - 1; i >= 0; i-- {
+		if err := b.Add(testTriples[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(a.Hash(0, 0), b.Hash(0, 0)) {
+		t.Errorf("root hash depends on the insertion order: %x != %x", a.Hash(0, 0), b.Hash(0, 0))
+	}
+	if bytes.Equal(a.Hash(0, 0), empty) {
+		t.Errorf("root hash %x didn't change", empty)
+	}
+
+	b.Remove(testTriples[0])
+	if bytes.Equal(a.Hash(0, 0), b.Hash(0, 0)) {
+		t.Errorf("root hash didn't change after Remove")
+	}
+	// Only the nodes above the triple's leaf differ.
+	leaf := b.Leaf(murmur3.Sum64([]byte(testTriples[0].Subj)))
+	for level := 4; level >= 0; level-- {
+		for i := uint64(0); i < 1<<uint(level); i++ {
+			differ := !bytes.Equal(a.Hash(level, i), b.Hash(level, i))
+			if differ != (i == leaf) {
+				t.Errorf("Hash(%d, %d) differ = %t", level, i, differ)
+			}
+		}
+		leaf /= 2
+	}
+
+	b.Add(testTriples[0])
+	if !bytes.Equal(a.Hash(0, 0), b.Hash(0, 0)) {
+		t.Errorf("root hash %x != %x after adding the triple back", b.Hash(0, 0), a.Hash(0, 0))
+	}
+	if hash := a.Hash(5, 0); hash != nil {
+		t.Errorf("Hash(5, 0) = %x; not nil", hash)
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	t.Parallel()
+
+	tree := New(4)
+	triple := testTriples[0]
+	leaf := tree.Leaf(murmur3.Sum64([]byte(triple.Subj)))
+	if !tree.Empty(leaf) {
+		t.Errorf("Empty(%d) = false on a new tree", leaf)
+	}
+	if err := tree.Add(triple); err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(0); i < 1<<4; i++ {
+		if empty := tree.Empty(i); empty != (i != leaf) {
+			t.Errorf("Empty(%d) = %t after adding to leaf %d", i, empty, leaf)
+		}
+	}
+	if err := tree.Remove(triple); err != nil {
+		t.Fatal(err)
+	}
+	if !tree.Empty(leaf) {
+		t.Errorf("Empty(%d) = false after removing its triple", leaf)
+	}
+}
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		level       int
+		index       uint64
+		first, last uint64
+	}{
+		{0, 0, 0, math.MaxUint64},
+		{1, 0, 0, math.MaxUint64 / 2},
+		{1, 1, math.MaxUint64/2 + 1, math.MaxUint64},
+		{2, 1, 1 << 62, 1<<63 - 1},
+	}
+	for i, td := range testData {
+		first, last := Range(td.level, td.index)
+		if first != td.first || last != td.last {
+			t.Errorf("%d. Range(%d, %d) = %d, %d; not %d, %d", i, td.level, td.index, first, last, td.first, td.last)
+		}
+	}
+}
+
+func TestCovers(t *testing.T) {
+	t.Parallel()
+
+	half := uint64(1 << 63)
+	testData := []struct {
+		k                  *protocol.Keyspace
+		level              int
+		index              uint64
+		covers, intersects bool
+	}{
+		{&protocol.Keyspace{Start: 0, End: half}, 1, 0, true, true},
+		{&protocol.Keyspace{Start: 0, End: half}, 1, 1, false, false},
+		{&protocol.Keyspace{Start: 0, End: half - 1}, 1, 0, false, true},
+		{&protocol.Keyspace{Start: half, End: 0}, 1, 1, true, true},
+		{&protocol.Keyspace{Start: half + 10, End: 10}, 1, 1, false, true},
+		{&protocol.Keyspace{Start: half + 10, End: 10}, 2, 0, false, true},
+		{&protocol.Keyspace{Start: 10, End: 20}, 0, 0, false, true},
+		{&protocol.Keyspace{Start: 1, End: 0}, 1, 1, true, true},
+		{nil, 1, 1, false, false},
+	}
+	for i, td := range testData {
+		if out := Covers(td.k, td.level, td.index); out != td.covers {
+			t.Errorf("%d. Covers(%+v, %d, %d) = %t; not %t", i, td.k, td.level, td.index, out, td.covers)
+		}
+		if out := Intersects(td.k, td.level, td.index); out != td.intersects {
+			t.Errorf("%d. Intersects(%+v, %d, %d) = %t; not %t", i, td.k, td.level, td.index, out, td.intersects)
+		}
+	}
+}
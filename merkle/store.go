@@ -0,0 +1,94 @@
+package merkle
+
+import (
+	"log"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/triplestore"
+)
+
+// Store is a TripleStore that keeps a Merkle tree of its triples up to date.
+// The tree is only updated with the triples the TripleStore reports writing or
+// removing so it can't drift from what's stored.
+type Store struct {
+	triplestore.TripleStore
+	Tree *Tree
+
+	logger *log.Logger
+}
+
+// NewStore wraps a TripleStore and builds a tree with the specified depth from
+// the triples it already has.
+func NewStore(ts triplestore.TripleStore, depth int, logger *log.Logger) (*Store, error) {
+	return newStore(ts, New(depth), logger)
+}
+
+// NewObjectStore wraps an object index like NewStore with a tree over the
+// object hashes.
+func NewObjectStore(ts triplestore.TripleStore, depth int, logger *log.Logger) (*Store, error) {
+	return newStore(ts, NewObject(depth), logger)
+}
+
+func newStore(ts triplestore.TripleStore, tree *Tree, logger *log.Logger) (*Store, error) {
+	s := &Store{TripleStore: ts, Tree: tree, logger: logger}
+	results, errs := ts.EachTripleBatch(triplestore.DefaultTripleBatchSize)
+	var addErr error
+	for triples := range results {
+		for _, triple := range triples {
+			if err := s.Tree.Add(triple); err != nil && addErr == nil {
+				addErr = err
+			}
+		}
+	}
+	for err := range errs {
+		return nil, err
+	}
+	if addErr != nil {
+		return nil, addErr
+	}
+	return s, nil
+}
+
+// Write saves a bunch of triples, adds the ones written to the tree in place
+// of the ones they replace and returns them.
+func (s *Store) Write(triples []*protocol.Triple) (written, replaced []*protocol.Triple, err error) {
+	written, replaced, err = s.TripleStore.Write(triples)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.update(replaced, s.Tree.Remove)
+	s.update(written, s.Tree.Add)
+	return written, replaced, nil
+}
+
+// Remove removes the triples with the same subject, predicate, object and
+// author from the store and the tree and returns the stored triples removed.
+func (s *Store) Remove(triples []*protocol.Triple) ([]*protocol.Triple, error) {
+	removed, err := s.TripleStore.Remove(triples)
+	if err != nil {
+		return nil, err
+	}
+	s.update(removed, s.Tree.Remove)
+	return removed, nil
+}
+
+// Erase saves the tombstones, deletes the triples they retract from the store
+// and the tree and returns the stored triples deleted.
+func (s *Store) Erase(tombstones []*protocol.Tombstone) ([]*protocol.Triple, error) {
+	removed, err := s.TripleStore.Erase(tombstones)
+	if err != nil {
+		return nil, err
+	}
+	s.update(removed, s.Tree.Remove)
+	return removed, nil
+}
+
+// update applies a tree operation to the triples. A triple that can't be
+// fingerprinted leaves the tree as it was.
+func (s *Store) update(triples []*protocol.Triple, op func(*protocol.Triple) error) {
+	for _, triple := range triples {
+		if err := op(triple); err != nil {
+			s.logger.Printf("ERR updating Merkle tree with %#v: %s", triple, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package merkle
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/triplestore"
+)
+
+var testLogger = log.New(os.Stdout, "", log.Flags())
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	ts := triplestore.NewMemoryStore()
+	ts.Write(testTriples[:1])
+	s, err := NewStore(ts, 4, testLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := New(4)
+	for _, triple := range testTriples {
+		want.Add(triple)
+	}
+	if written, _, err := s.Write(testTriples); err != nil || len(written) != len(testTriples)-1 {
+		t.Errorf("Write(testTriples) = %d, %v; not %d", len(written), err, len(testTriples)-1)
+	}
+	// Inserting duplicates doesn't change the tree.
+	s.Write(testTriples)
+	if !bytes.Equal(s.Tree.Hash(0, 0), want.Hash(0, 0)) {
+		t.Errorf("Tree.Hash(0, 0) = %x; not %x", s.Tree.Hash(0, 0), want.Hash(0, 0))
+	}
+
+	if removed, err := s.Remove(testTriples[1:]); err != nil || len(removed) != len(testTriples)-1 {
+		t.Errorf("Remove(testTriples[1:]) = %d, %v; not %d", len(removed), err, len(testTriples)-1)
+	}
+	s.Remove(testTriples[1:])
+	want = New(4)
+	want.Add(testTriples[0])
+	if !bytes.Equal(s.Tree.Hash(0, 0), want.Hash(0, 0)) {
+		t.Errorf("Tree.Hash(0, 0) = %x; not %x", s.Tree.Hash(0, 0), want.Hash(0, 0))
+	}
+}
+
+func TestStoreReassert(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewStore(triplestore.NewMemoryStore(), 4, testLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	triple := *testTriples[0]
+	triple.Created = 1
+	s.Write([]*protocol.Triple{&triple})
+	s.Erase([]*protocol.Tombstone{{Subj: triple.Subj, Pred: triple.Pred, Obj: triple.Obj, Author: triple.Author, Created: 2}})
+
+	reassert := triple
+	reassert.Created = 3
+	if written, _, err := s.Write([]*protocol.Triple{&triple, &reassert}); err != nil || len(written) != 1 {
+		t.Errorf("Write() after Retract = %d, %v; not 1", len(written), err)
+	}
+	want := New(4)
+	want.Add(&reassert)
+	if !bytes.Equal(s.Tree.Hash(0, 0), want.Hash(0, 0)) {
+		t.Errorf("Tree.Hash(0, 0) = %x; not %x", s.Tree.Hash(0, 0), want.Hash(0, 0))
+	}
+}
+
+// partialStore only writes the first triple of each batch, like a store whose
+// writes partly fail.
+type partialStore struct {
+	*triplestore.MemoryStore
+}
+
+func (s partialStore) Write(triples []*protocol.Triple) (written, replaced []*protocol.Triple, err error) {
+	return s.MemoryStore.Write(triples[:1])
+}
+
+func TestStorePartialWrite(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewStore(partialStore{triplestore.NewMemoryStore()}, 4, testLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written, _, err := s.Write(testTriples); err != nil || len(written) != 1 {
+		t.Errorf("Write(testTriples) = %d, %v; not 1", len(written), err)
+	}
+	// Only the stored triple is in the tree.
+	want := New(4)
+	want.Add(testTriples[0])
+	if !bytes.Equal(s.Tree.Hash(0, 0), want.Hash(0, 0)) {
+		t.Errorf("Tree.Hash(0, 0) = %x; not %x", s.Tree.Hash(0, 0), want.Hash(0, 0))
+	}
+}
@@ -0,0 +1,66 @@
+package network
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/degdb/degdb/network/ip"
+)
+
+// An AddressStrategy finds the host the node advertises to its peers.
+type AddressStrategy func() (string, error)
+
+// AddressStrategyNames are the names accepted by ParseAddressStrategies.
+var AddressStrategyNames = []string{"peers", "public", "interface"}
+
+// AddressStrategies are tried in order until one finds the host to advertise.
+// If they all fail the node advertises localhost.
+var AddressStrategies = []AddressStrategy{ip.IP, ip.Interface}
+
+// Advertise returns a strategy that always advertises host.
+func Advertise(host string) AddressStrategy {
+	return func() (string, error) {
+		return host, nil
+	}
+}
+
+// PeerAddress returns a strategy that advertises the address a quorum of the
+// peers see connections from. The peers are in the format "host:port".
+func PeerAddress(peers []string) AddressStrategy {
+	return func() (string, error) {
+		return ip.Peers(peers)
+	}
+}
+
+// ParseAddressStrategies returns the strategies for a comma separated list of
+// AddressStrategyNames. The "peers" strategy asks the specified peers.
+func ParseAddressStrategies(names string, peers []string) ([]AddressStrategy, error) {
+	var strategies []AddressStrategy
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "peers":
+			strategies = append(strategies, PeerAddress(peers))
+		case "public":
+			strategies = append(strategies, ip.IP)
+		case "interface":
+			strategies = append(strategies, ip.Interface)
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown address strategy %q, expected one of %v", name, AddressStrategyNames)
+		}
+	}
+	return strategies, nil
+}
+
+// findHost returns the host from the first address strategy that succeeds.
+func findHost(strategies []AddressStrategy) (string, []error) {
+	var errs []error
+	for _, strategy := range strategies {
+		host, err := strategy()
+		if err == nil && len(host) > 0 {
+			return host, errs
+		}
+		errs = append(errs, err)
+	}
+	return "localhost", errs
+}
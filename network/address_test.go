@@ -0,0 +1,49 @@
+package network
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFindHost(t *testing.T) {
+	t.Parallel()
+
+	failing := func() (string, error) { return "", errors.New("offline") }
+	testData := []struct {
+		strategies []AddressStrategy
+		want       string
+		errs       int
+	}{
+		{nil, "localhost", 0},
+		{[]AddressStrategy{Advertise("a.example")}, "a.example", 0},
+		{[]AddressStrategy{failing, Advertise("b.example"), Advertise("c.example")}, "b.example", 1},
+		{[]AddressStrategy{failing, PeerAddress(nil)}, "localhost", 2},
+	}
+	for i, td := range testData {
+		host, errs := findHost(td.strategies)
+		if host != td.want || len(errs) != td.errs {
+			t.Errorf("%d. findHost() = %q, %v; not %q with %d errors", i, host, errs, td.want, td.errs)
+		}
+	}
+}
+
+func TestParseAddressStrategies(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		names string
+		want  int
+		err   bool
+	}{
+		{"", 0, false},
+		{"public", 1, false},
+		{"peers, interface,public", 3, false},
+		{"public,foo", 0, true},
+	}
+	for i, td := range testData {
+		strategies, err := ParseAddressStrategies(td.names, nil)
+		if (err != nil) != td.err || len(strategies) != td.want {
+			t.Errorf("%d. ParseAddressStrategies(%q) = %d strategies, %v; not %d", i, td.names, len(strategies), err, td.want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"math/rand"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
@@ -23,16 +24,35 @@ func (s *Server) NewConn(c net.Conn) *Conn {
 	}
 }
 
+// NewPeerConn returns a Conn from the peer that isn't backed by a network
+// connection, so messages from the peer can be passed to handlers directly.
+func NewPeerConn(peer *protocol.Peer) *Conn {
+	return &Conn{peer: peer}
+}
+
 // Conn is a net.Conn with extensions.
 type Conn struct {
-	Peer   *protocol.Peer
-	Closed bool
+	// peer is the peer information from the handshake. It's replaced by
+	// handshake updates, so it's read with Peer.
+	peer     *protocol.Peer
+	peerLock sync.RWMutex
+	Closed   bool
+	// Author and KeyPin are the author ID and the key pin of the key the peer
+	// authenticated with over TLS.
+	Author string
+	KeyPin string
+
+	// relay is the connection messages to relayTo are relayed through if the
+	// peer can't be connected to directly.
+	relay   *Conn
+	relayTo string
 
 	// Notify channel for heartbeats
 	peerRequest        chan bool
 	peerRequestRetries int
 	server             *Server
 	expectedMessages   map[uint64]chan *protocol.Message
+	expectedLock       sync.Mutex
 
 	net.Conn
 }
@@ -43,6 +63,16 @@ func (c *Conn) Send(m *protocol.Message) error {
 	if err != nil {
 		return err
 	}
+	if c.relay != nil {
+		return c.relay.Send(&protocol.Message{
+			Message: &protocol.Message_Relay{
+				Relay: &protocol.Relay{
+					To:      c.relayTo,
+					Message: msg,
+				},
+			},
+		})
+	}
 	packet := make([]byte, len(msg)+4)
 	binary.BigEndian.PutUint32(packet, uint32(len(msg)))
 	copy(packet[4:], msg)
@@ -55,6 +85,16 @@ func (c *Conn) Send(m *protocol.Message) error {
 func (c *Conn) Request(m *protocol.Message) (*protocol.Message, error) {
 	m.Id = uint64(rand.Int63())
 	m.ResponseRequired = true
+	// Register before sending so a quick response isn't dropped.
+	resp := make(chan *protocol.Message, 1)
+	c.expectedLock.Lock()
+	c.expectedMessages[m.Id] = resp
+	c.expectedLock.Unlock()
+	defer func() {
+		c.expectedLock.Lock()
+		delete(c.expectedMessages, m.Id)
+		c.expectedLock.Unlock()
+	}()
 	if err := c.Send(m); err != nil {
 		return nil, err
 	}
@@ -64,8 +104,6 @@ func (c *Conn) Request(m *protocol.Message) (*protocol.Message, error) {
 		time.Sleep(10 * time.Second)
 		timeout <- true
 	}()
-	resp := make(chan *protocol.Message, 1)
-	c.expectedMessages[m.Id] = resp
 
 	var msg *protocol.Message
 	var err error
@@ -74,10 +112,17 @@ func (c *Conn) Request(m *protocol.Message) (*protocol.Message, error) {
 	case <-timeout:
 		err = Timeout
 	}
-	delete(c.expectedMessages, m.Id)
 	return msg, err
 }
 
+// expected returns the channel waiting for a response to the message id.
+func (c *Conn) expected(id uint64) (chan *protocol.Message, bool) {
+	c.expectedLock.Lock()
+	defer c.expectedLock.Unlock()
+	ch, ok := c.expectedMessages[id]
+	return ch, ok
+}
+
 // RespondTo sends `resp` as a response to the request `to`.
 func (c *Conn) RespondTo(to *protocol.Message, resp *protocol.Message) error {
 	resp.ResponseTo = to.Id
@@ -90,17 +135,35 @@ func (c *Conn) Close() error {
 		return nil
 	}
 	c.Closed = true
+	if c.relay != nil {
+		c.server.removeRelayed(c)
+	}
 	if c.Conn != nil {
 		return c.Conn.Close()
 	}
 	return nil
 }
 
+// Peer returns the peer information from the handshake or nil if the peer
+// hasn't sent a valid one.
+func (c *Conn) Peer() *protocol.Peer {
+	c.peerLock.RLock()
+	defer c.peerLock.RUnlock()
+	return c.peer
+}
+
+// setPeer replaces the peer information of the connection.
+func (c *Conn) setPeer(peer *protocol.Peer) {
+	c.peerLock.Lock()
+	c.peer = peer
+	c.peerLock.Unlock()
+}
+
 // PrettyID returns a terminal colored format of the connection ID.
 func (c *Conn) PrettyID() string {
 	var remote string
-	if c.Peer != nil {
-		remote = c.Peer.Id
+	if peer := c.Peer(); peer != nil {
+		remote = peer.Id
 	} else {
 		remote = c.RemoteAddr().String()
 	}
@@ -72,10 +72,10 @@ type httpConn struct {
 }
 
 func (c *httpConn) Read(b []byte) (int, error) {
-	i := len(c.initial)
-	if i > 0 {
-		copy(b, c.initial)
-		c.initial = nil
+	i := copy(b, c.initial)
+	c.initial = c.initial[i:]
+	if i == len(b) {
+		return i, nil
 	}
 	n, err := c.Conn.Read(b[i:])
 	return n + i, err
@@ -1,14 +1,26 @@
-// Package ip returns the current IP address by contacting a number of public servers.
+// Package ip returns the current IP address by contacting a number of public
+// servers, asking peers or from the network interfaces.
 package ip
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"strings"
+	"time"
 )
 
+var (
+	ErrNoAddress = errors.New("no usable network interface address")
+	ErrNoServers = errors.New("no servers to ask for the ip address")
+)
+
+// client gives up on servers that don't respond so offline nodes can fall back
+// to other ways of finding their address.
+var client = &http.Client{Timeout: 5 * time.Second}
+
 var ipServers = []string{
 	"https://ipinfo.io/ip",
 	"https://icanhazip.com/",
@@ -18,17 +30,36 @@ var ipServers = []string{
 
 // IP returns the current IP address from a quorum of external servers.
 func IP() (string, error) {
-	ips := make(chan string, 1)
-	errs := make(chan error, 1)
-	for _, server := range ipServers {
+	return quorum(ipServers)
+}
+
+// Peers returns the current IP address from a quorum of the /api/v1/myip
+// endpoints of degdb peers. The peers are in the format "host:port".
+func Peers(peers []string) (string, error) {
+	var servers []string
+	for _, peer := range peers {
+		servers = append(servers, "http://"+peer+"/api/v1/myip")
+	}
+	return quorum(servers)
+}
+
+// quorum returns the IP address most of the servers respond with.
+func quorum(servers []string) (string, error) {
+	if len(servers) == 0 {
+		return "", ErrNoServers
+	}
+	ips := make(chan string, len(servers))
+	errs := make(chan error, len(servers))
+	for _, server := range servers {
 		server := server
 		go func() {
-			resp, err := http.Get(server)
+			resp, err := client.Get(server)
 			if err != nil {
 				errs <- err
 				return
 			}
 			body, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
 			ipAddr := strings.TrimSpace(string(body))
 			ip := net.ParseIP(ipAddr)
 			if ip == nil {
@@ -41,7 +72,7 @@ func IP() (string, error) {
 	var errors []error
 	var resps []string
 	respMap := make(map[string]int)
-	for (len(resps) < 2 || len(respMap) > 1) && len(resps)+len(errors) < len(ipServers) {
+	for (len(resps) < 2 || len(respMap) > 1) && len(resps)+len(errors) < len(servers) {
 		select {
 		case resp := <-ips:
 			resps = append(resps, resp)
@@ -64,3 +95,38 @@ func IP() (string, error) {
 	}
 	return best, nil
 }
+
+// Interface returns the address of the first network interface that is up and
+// not a loopback, preferring IPv4.
+func Interface() (string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", err
+	}
+	var v6 string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || !ipNet.IP.IsGlobalUnicast() {
+				continue
+			}
+			if ipNet.IP.To4() != nil {
+				return ipNet.IP.String(), nil
+			}
+			if len(v6) == 0 {
+				v6 = ipNet.IP.String()
+			}
+		}
+	}
+	if len(v6) > 0 {
+		return v6, nil
+	}
+	return "", ErrNoAddress
+}
@@ -59,3 +59,25 @@ func TestIP(t *testing.T) {
 		}
 	}
 }
+
+func TestPeers(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/myip", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("10.0.0.1"))
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	ip, err := Peers([]string{s.Listener.Addr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "10.0.0.1"; ip != want {
+		t.Errorf("Peers() = %q; not %q", ip, want)
+	}
+	if _, err := Peers(nil); err != ErrNoServers {
+		t.Errorf("Peers(nil) = %v; not %v", err, ErrNoServers)
+	}
+}
@@ -2,15 +2,18 @@
 package network
 
 import (
+	"crypto/tls"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"math"
 	"net"
 	"net/http"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,7 +21,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/spaolacci/murmur3"
 
-	"github.com/degdb/degdb/network/ip"
+	"github.com/degdb/degdb/crypto"
 	"github.com/degdb/degdb/protocol"
 )
 
@@ -36,14 +39,15 @@ func init() {
 	stunWG.Add(1)
 }
 
+// getHost returns the host the node advertises, found with the
+// AddressStrategies the first time it's called.
 func getHost() string {
 	stunOnce.Do(func() {
-		// TODO(d4l3k): Fetch IP by talking to other nodes.
-		ip, err := ip.IP()
-		if err != nil {
-			log.Fatal(err)
+		host, errs := findHost(AddressStrategies)
+		for _, err := range errs {
+			log.Printf("ERR finding address: %s", err)
 		}
-		stunHost = ip
+		stunHost = host
 		stunWG.Done()
 	})
 	stunWG.Wait()
@@ -62,15 +66,49 @@ type Server struct {
 	mux           *http.ServeMux
 	httpEndpoints []string
 
-	Peers     map[string]*Conn
+	Peers map[string]*Conn
+	// unproven are the connections whose handshake was accepted but whose id
+	// isn't proven yet, by id. They aren't routed to or advertised.
+	unproven map[string]*Conn
+	// nodes are the author IDs of the peers whose ids were proven.
+	nodes     map[string]bool
 	peersLock sync.RWMutex
+	// routes are the peers messages are routed through.
+	routes *routingTable
+
+	// keyspace overrides the default keyspace if set.
+	keyspace     *protocol.Keyspace
+	keyspaceLock sync.RWMutex
+
+	// author and keyPin are the author ID and the key pin of the key peers
+	// authenticate this node with.
+	author    string
+	keyPin    string
+	key       *crypto.PrivateKey
+	tlsConfig *tls.Config
 
 	netListener net.Listener
 	// listeningWG waits for the server to start listening and accepting connections.
 	listeningWG sync.WaitGroup
 
-	handlers map[string]protocolHandler
-	listener *httpListener
+	// peerBook records the known peers for reconnecting.
+	peerBook *PeerBook
+
+	// quit is closed when the server is stopped to end its loops.
+	quit     chan struct{}
+	stopOnce sync.Once
+
+	// relay is the id of the peer relaying for the node if it can't be
+	// connected to directly.
+	relay string
+	// relayed are the connections to peers that are relayed through another
+	// peer, by id.
+	relayed   map[string]*Conn
+	relayLock sync.Mutex
+
+	handlers     map[string]protocolHandler
+	peerHandlers []func(conn *Conn)
+	listener     *httpListener
 	*log.Logger
 }
 
@@ -83,8 +121,16 @@ func NewServer(logger *log.Logger, port int) (*Server, error) {
 		Logger:   logger,
 		Port:     port,
 		Peers:    make(map[string]*Conn),
+		unproven: make(map[string]*Conn),
+		nodes:    make(map[string]bool),
+		peerBook: NewPeerBook(""),
+		relayed:  make(map[string]*Conn),
 		handlers: make(map[string]protocolHandler),
+		quit:     make(chan struct{}),
 	}
+	s.routes = newRoutingTable(func() uint64 {
+		return s.LocalKeyspace().Start
+	})
 
 	s.listeningWG.Add(1)
 
@@ -100,6 +146,7 @@ func NewServer(logger *log.Logger, port int) (*Server, error) {
 	s.Handle("Handshake", s.handleHandshake)
 	s.Handle("PeerRequest", s.handlePeerRequest)
 	s.Handle("PeerNotify", s.handlePeerNotify)
+	s.Handle("Relay", s.handleRelay)
 
 	return s, nil
 }
@@ -110,13 +157,18 @@ type Closable interface {
 
 // Stop closes all connections and cleans up.
 func (s *Server) Stop() {
+	s.stopOnce.Do(func() {
+		if s.quit != nil {
+			close(s.quit)
+		}
+	})
 	toClose := []Closable{s.netListener, s.listener}
 
-	s.peersLock.Lock()
-	defer s.peersLock.Unlock()
+	s.peersLock.RLock()
 	for _, peer := range s.Peers {
 		toClose = append(toClose, peer)
 	}
+	s.peersLock.RUnlock()
 
 	for _, close := range toClose {
 		if close == nil {
@@ -135,14 +187,28 @@ func (s *Server) ListenWait() {
 
 // Connect to another server. `addr` should be in the format "google.com:80".
 func (s *Server) Connect(addr string) error {
+	return s.ConnectKey(addr, "")
+}
+
+// ConnectKey connects to another server and, if the server has a key and pin
+// is set, checks that the peer authenticates with the key with that key pin.
+func (s *Server) ConnectKey(addr, pin string) error {
 	netConn, err := net.Dial("tcp", addr)
 	if err != nil {
+		s.peerBook.Failed(addr)
 		return err
 	}
 	tcpConn := netConn.(*net.TCPConn)
 	tcpConn.SetKeepAlive(true)
 
 	conn := s.NewConn(tcpConn)
+	if s.tlsConfig != nil {
+		if conn, err = s.dialTLS(tcpConn, pin); err != nil {
+			tcpConn.Close()
+			s.peerBook.Failed(addr)
+			return err
+		}
+	}
 
 	if err := s.sendHandshake(conn, protocol.HANDSHAKE_INITIAL); err != nil {
 		return err
@@ -182,7 +248,14 @@ func (s *Server) Handle(typ string, f protocolHandler) {
 	s.handlers[typ] = f
 }
 
+// HandlePeer registers a handler that is called for every new peer after the
+// handshake.
+func (s *Server) HandlePeer(f func(conn *Conn)) {
+	s.peerHandlers = append(s.peerHandlers, f)
+}
+
 // Broadcast sends a message to all peers with that have the hash in their keyspace.
+// The peers are found with Replicas, or with a nil hash, are the routing table.
 // If there is no peer that can receive the message, ErrNoRecipients is returned.
 func (s *Server) Broadcast(hash *uint64, msg *protocol.Message) error {
 	alreadySentTo := make(map[uint64]bool)
@@ -193,9 +266,13 @@ func (s *Server) Broadcast(hash *uint64, msg *protocol.Message) error {
 	}
 	sentTo := []uint64{murmur3.Sum64([]byte(s.LocalID()))}
 	var toPeers []*Conn
-	for _, peer := range s.Peers {
-		peerHash := murmur3.Sum64([]byte(peer.Peer.Id))
-		if (hash == nil || peer.Peer.GetKeyspace().Includes(*hash)) && !alreadySentTo[peerHash] {
+	candidates := s.routes.conns()
+	if hash != nil {
+		candidates = s.Replicas(*hash)
+	}
+	for _, peer := range candidates {
+		peerHash := murmur3.Sum64([]byte(peer.Peer().Id))
+		if (hash == nil || peer.Peer().GetKeyspace().Includes(*hash)) && !alreadySentTo[peerHash] {
 			sentTo = append(sentTo, peerHash)
 			toPeers = append(toPeers, peer)
 		}
@@ -207,7 +284,7 @@ func (s *Server) Broadcast(hash *uint64, msg *protocol.Message) error {
 		return ErrNoRecipients
 	}
 	for _, peer := range toPeers {
-		s.Printf("Broadcasting to %s", peer.Peer.Id)
+		s.Printf("Broadcasting to %s", peer.Peer().Id)
 		if err := peer.Send(msg); err != nil {
 			return err
 		}
@@ -215,11 +292,37 @@ func (s *Server) Broadcast(hash *uint64, msg *protocol.Message) error {
 	return nil
 }
 
+// Conns returns a snapshot of the connected peers that finished their
+// handshake, ordered by ID.
+func (s *Server) Conns() []*Conn {
+	s.peersLock.RLock()
+	var conns []*Conn
+	for _, conn := range s.Peers {
+		if conn != nil && conn.Peer() != nil {
+			conns = append(conns, conn)
+		}
+	}
+	s.peersLock.RUnlock()
+	sort.Sort(connsByID(conns))
+	return conns
+}
+
+// IsNode returns whether author is the author ID of the local node or of a
+// peer whose id was proven by its handshake.
+func (s *Server) IsNode(author string) bool {
+	if len(author) == 0 {
+		return false
+	}
+	s.peersLock.RLock()
+	defer s.peersLock.RUnlock()
+	return author == s.author || s.nodes[author]
+}
+
 func (s *Server) handleConnection(conn *Conn) error {
 	var err error
 	for {
 		header := make([]byte, 4)
-		_, err = conn.Read(header)
+		_, err = io.ReadFull(conn, header)
 		if err != nil {
 			break
 		}
@@ -228,13 +331,25 @@ func (s *Server) handleConnection(conn *Conn) error {
 			s.handleHTTPConnection(header, conn)
 			return nil
 		}
+		if s.tlsConfig != nil && len(conn.Author) == 0 {
+			if header[0] != tlsHandshakeRecord {
+				err = ErrUnauthenticated
+				break
+			}
+			var authed *Conn
+			if authed, err = s.acceptTLS(conn, header); err != nil {
+				break
+			}
+			conn = authed
+			continue
+		}
 		length := binary.BigEndian.Uint32(header)
 		if length > 10000000 {
 			err = fmt.Errorf("Packet larger than 10MB! len = %s", humanize.SI(float64(length), "B"))
 			break
 		}
 		buf := make([]byte, length)
-		_, err = conn.Read(buf)
+		_, err = io.ReadFull(conn, buf)
 		if err != nil {
 			break
 		}
@@ -243,44 +358,87 @@ func (s *Server) handleConnection(conn *Conn) error {
 		if err = req.Unmarshal(buf); err != nil {
 			break
 		}
-		s.Printf("Message: <- %s, %+v", conn.PrettyID(), req.GetMessage())
-		if req.ResponseTo != 0 {
-			if c, ok := conn.expectedMessages[req.ResponseTo]; ok {
-				c <- req
-				continue
-			} else {
-				err = fmt.Errorf("response sent to invalid request %d", req.ResponseTo)
-				break
-			}
-		}
-		rawType := reflect.TypeOf(req.GetMessage()).Elem().Name()
-		typ := strings.TrimPrefix(rawType, "Message_")
-		handler, ok := s.handlers[typ]
-		if !ok {
-			err = fmt.Errorf("no handler for message type %s", typ)
+		if err = s.dispatch(conn, req); err != nil {
 			break
 		}
-		go handler(conn, req)
 	}
 	s.Printf("Connection closed. %s", err)
 	conn.Close()
-	if conn.Peer != nil {
-		delete(s.Peers, conn.Peer.Id)
+	if conn.Peer() != nil {
+		s.removePeer(conn)
 	}
 	return err
 }
 
+// dispatch passes a message to the request waiting for it or to the handler
+// for its type.
+func (s *Server) dispatch(conn *Conn, req *protocol.Message) error {
+	s.Printf("Message: <- %s, %+v", conn.PrettyID(), req.GetMessage())
+	if req.ResponseTo != 0 {
+		c, ok := conn.expected(req.ResponseTo)
+		if !ok {
+			return fmt.Errorf("response sent to invalid request %d", req.ResponseTo)
+		}
+		c <- req
+		return nil
+	}
+	if req.GetMessage() == nil {
+		return fmt.Errorf("message without a type from %s", conn.PrettyID())
+	}
+	rawType := reflect.TypeOf(req.GetMessage()).Elem().Name()
+	typ := strings.TrimPrefix(rawType, "Message_")
+	handler, ok := s.handlers[typ]
+	if !ok {
+		return fmt.Errorf("no handler for message type %s", typ)
+	}
+	go handler(conn, req)
+	return nil
+}
+
+// removePeer removes a connection from the peers unless it was replaced.
+func (s *Server) removePeer(conn *Conn) {
+	// Connections are only added once their handshake is accepted.
+	if conn.Peer() == nil {
+		return
+	}
+	s.peersLock.Lock()
+	defer s.peersLock.Unlock()
+	if s.Peers[conn.Peer().Id] == conn {
+		delete(s.Peers, conn.Peer().Id)
+	}
+	if s.unproven[conn.Peer().Id] == conn {
+		delete(s.unproven, conn.Peer().Id)
+	}
+	s.routes.remove(conn)
+	s.relayLock.Lock()
+	if s.relay == conn.Peer().Id {
+		s.relay = ""
+	}
+	s.relayLock.Unlock()
+}
+
 // LocalPeer returns a peer object of the current server.
 func (s *Server) LocalPeer() *protocol.Peer {
 	return &protocol.Peer{
 		Id:       s.LocalID(),
 		Serving:  s.Serving,
 		Keyspace: s.LocalKeyspace(),
+		Author:   s.author,
+		KeyPin:   s.keyPin,
+		Relay:    s.relayID(),
 	}
 }
 
-// LocalKeyspace returns the keyspace that the local node represents.
+// LocalKeyspace returns the keyspace that the local node represents. Unless
+// set with SetKeyspace, it's a quarter of the keyspace on either side of the
+// hash of the node ID.
 func (s *Server) LocalKeyspace() *protocol.Keyspace {
+	s.keyspaceLock.RLock()
+	keyspace := s.keyspace
+	s.keyspaceLock.RUnlock()
+	if keyspace != nil {
+		return keyspace.Clone()
+	}
 	center := murmur3.Sum64([]byte(s.LocalID()))
 	return &protocol.Keyspace{
 		Start: center - math.MaxUint64/4,
@@ -288,12 +446,76 @@ func (s *Server) LocalKeyspace() *protocol.Keyspace {
 	}
 }
 
+// AssignKeyspace picks a keyspace for the local node from the keyspaces the
+// peers advertise. It takes the largest part of the keyspace fewer than
+// replicas peers cover, or if there are none, splits the largest peer keyspace
+// in half. Without peers the node covers the entire keyspace.
+func (s *Server) AssignKeyspace(replicas int) *protocol.Keyspace {
+	var keyspaces []*protocol.Keyspace
+	s.peersLock.RLock()
+	for _, conn := range s.Peers {
+		if routable(conn) {
+			keyspaces = append(keyspaces, conn.Peer().Keyspace)
+		}
+	}
+	s.peersLock.RUnlock()
+
+	if len(keyspaces) == 0 {
+		center := murmur3.Sum64([]byte(s.LocalID()))
+		return &protocol.Keyspace{Start: center, End: center - 1}
+	}
+	var best *protocol.Keyspace
+	for _, gap := range protocol.Gaps(keyspaces, replicas) {
+		if gap.Mag() > best.Mag() {
+			best = gap
+		}
+	}
+	if best != nil {
+		return best
+	}
+	for _, keyspace := range keyspaces {
+		if keyspace.Mag() > best.Mag() {
+			best = keyspace
+		}
+	}
+	mag := best.Mag()
+	return &protocol.Keyspace{Start: best.Start + mag/2, End: best.End}
+}
+
+// SetKeyspace changes the keyspace the local node represents and announces it
+// to all peers with a HANDSHAKE_UPDATE.
+func (s *Server) SetKeyspace(keyspace *protocol.Keyspace) {
+	s.keyspaceLock.Lock()
+	s.keyspace = keyspace.Clone()
+	s.keyspaceLock.Unlock()
+	s.routes.rebuild()
+	s.announce()
+}
+
+// announce sends the local peer information to all peers with a
+// HANDSHAKE_UPDATE.
+func (s *Server) announce() {
+	s.peersLock.RLock()
+	var peers []*Conn
+	for _, conn := range s.Peers {
+		if conn != nil {
+			peers = append(peers, conn)
+		}
+	}
+	s.peersLock.RUnlock()
+	for _, conn := range peers {
+		if err := s.sendHandshake(conn, protocol.HANDSHAKE_UPDATE); err != nil {
+			s.Printf("ERR sendHandshake %s", err)
+		}
+	}
+}
+
 // LocalID returns the local machines ID.
 func (s *Server) LocalID() string {
 	return net.JoinHostPort(s.IP, strconv.Itoa(s.Port))
 }
 
-// MinimumCoveringPeers returns a set of peers that minimizes overlap. This is similar to the Set Covering Problem and is NP-hard.
+// MinimumCoveringPeers returns a set of peers from the routing table that minimizes overlap. This is similar to the Set Covering Problem and is NP-hard.
 // This is a greedy algorithm. While the keyspace is not entirely covered, scan through all peers and pick the peer that will add the most to the set while still having the start in the selected set.
 // TODO(wiz): Make this more optimal.
 // TODO(wiz): achieve n-redundancy
@@ -301,16 +523,16 @@ func (s *Server) MinimumCoveringPeers() []*Conn {
 	usedPeers := make(map[string]bool)
 	var peers []*Conn
 	var keyspace *protocol.Keyspace
-	for i := 0; i < len(s.Peers) && !keyspace.Maxed(); i++ {
+	conns := s.routes.conns()
+	for i := 0; i < len(conns) && !keyspace.Maxed(); i++ {
 		var bestPeer *Conn
 		var increase uint64
-		// By definition, ranging through peer map will go in random order.
 	Peers:
-		for id, conn := range s.Peers {
-			if conn == nil || conn.Peer == nil || usedPeers[id] {
+		for _, conn := range conns {
+			if usedPeers[conn.Peer().Id] {
 				continue
 			}
-			peer := conn.Peer
+			peer := conn.Peer()
 			if keyspace == nil {
 				peers = append(peers, conn)
 				keyspace = peer.Keyspace
@@ -324,8 +546,8 @@ func (s *Server) MinimumCoveringPeers() []*Conn {
 		}
 		if bestPeer != nil {
 			peers = append(peers, bestPeer)
-			keyspace = keyspace.Union(bestPeer.Peer.Keyspace)
-			usedPeers[bestPeer.Peer.Id] = true
+			keyspace = keyspace.Union(bestPeer.Peer().Keyspace)
+			usedPeers[bestPeer.Peer().Id] = true
 			// break?
 		}
 	}
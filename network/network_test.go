@@ -5,6 +5,7 @@ import (
 	"log"
 	"math"
 	"os"
+	"reflect"
 	"sort"
 	"strconv"
 	"sync"
@@ -127,14 +128,14 @@ func TestMinimumCoveringPeers(t *testing.T) {
 		},
 	}
 	for i, td := range testData {
-		s := &Server{Peers: make(map[string]*Conn)}
+		s := &Server{routes: newRoutingTable(func() uint64 { return 0 })}
 		for j, keyspace := range td.keyspaces {
 			id := strconv.Itoa(j)
-			s.Peers[id] = &Conn{
-				Peer: &protocol.Peer{
+			s.routes.add(&Conn{
+				peer: &protocol.Peer{
 					Keyspace: keyspace,
 					Id:       id,
-				}}
+				}})
 		}
 		min := s.MinimumCoveringPeers()
 		if len(min) > len(td.keyspaces) {
@@ -149,9 +150,9 @@ func TestMinimumCoveringPeers(t *testing.T) {
 		for i := 0; i < len(min); i++ {
 			for _, peer := range min {
 				if i == 0 {
-					keyspaces = append(keyspaces, *peer.Peer.Keyspace)
+					keyspaces = append(keyspaces, *peer.Peer().Keyspace)
 				}
-				union = union.Union(peer.Peer.Keyspace)
+				union = union.Union(peer.Peer().Keyspace)
 			}
 		}
 		if union.Maxed() != td.cover {
@@ -166,7 +167,7 @@ func (s sortConnByKeyspace) Len() int {
 	return len(s)
 }
 func (s sortConnByKeyspace) Less(i, j int) bool {
-	return s[i].Peer.Keyspace.Start < s[j].Peer.Keyspace.Start
+	return s[i].Peer().Keyspace.Start < s[j].Peer().Keyspace.Start
 }
 func (s sortConnByKeyspace) Swap(i, j int) {
 	s[i], s[j] = s[j], s[i]
@@ -211,6 +212,80 @@ func TestLocalPeer(t *testing.T) {
 	}
 }
 
+func TestSetKeyspace(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{
+		IP:     "127.0.0.1",
+		Port:   7946,
+		Peers:  make(map[string]*Conn),
+		Logger: log.New(os.Stdout, "", log.Flags()),
+	}
+	s.routes = newRoutingTable(func() uint64 { return s.LocalKeyspace().Start })
+	want := &protocol.Keyspace{Start: 10, End: 20}
+	s.SetKeyspace(want)
+	if out := s.LocalPeer().Keyspace; !reflect.DeepEqual(out, want) {
+		t.Errorf("s.LocalPeer().Keyspace = %+v; not %+v", out, want)
+	}
+
+	// A handshake update changes the keyspace of an established peer.
+	conn := &Conn{peer: &protocol.Peer{Id: "peer", Keyspace: &protocol.Keyspace{Start: 1, End: 2}}}
+	s.Peers["peer"] = conn
+	s.handleHandshake(conn, &protocol.Message{
+		Message: &protocol.Message_Handshake{
+			Handshake: &protocol.Handshake{
+				Type:   protocol.HANDSHAKE_UPDATE,
+				Sender: &protocol.Peer{Id: "peer", Keyspace: want},
+			},
+		},
+	})
+	if s.Peers["peer"] != conn {
+		t.Errorf("s.Peers[%q] = %+v; not %+v", "peer", s.Peers["peer"], conn)
+	}
+	if out := conn.Peer().Keyspace; !reflect.DeepEqual(out, want) {
+		t.Errorf("conn.Peer().Keyspace = %+v; not %+v", out, want)
+	}
+}
+
+func TestAssignKeyspace(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		keyspaces []*protocol.Keyspace
+		want      *protocol.Keyspace
+	}{
+		{
+			nil,
+			&protocol.Keyspace{Start: 0x5677ecc49097f350 + math.MaxUint64/4, End: 0x5677ecc49097f350 + math.MaxUint64/4 - 1},
+		},
+		{
+			[]*protocol.Keyspace{
+				{Start: 0, End: 100},
+				{Start: 200, End: 0},
+			},
+			&protocol.Keyspace{Start: 100, End: 200},
+		},
+		{
+			[]*protocol.Keyspace{
+				{Start: 0, End: 100},
+				{Start: 100, End: 0},
+			},
+			&protocol.Keyspace{Start: 100 + (math.MaxUint64-99)/2, End: 0},
+		},
+	}
+	for i, td := range testData {
+		s := &Server{IP: "127.0.0.1", Port: 7946, Peers: make(map[string]*Conn)}
+		for j, keyspace := range td.keyspaces {
+			id := strconv.Itoa(j)
+			s.Peers[id] = &Conn{peer: &protocol.Peer{Id: id, Keyspace: keyspace}}
+		}
+		out := s.AssignKeyspace(1)
+		if diff, ok := messagediff.PrettyDiff(td.want, out); !ok {
+			t.Errorf("%d. AssignKeyspace() with %+v = %+v; diff %s", i, td.keyspaces, out, diff)
+		}
+	}
+}
+
 func TestGetHost(t *testing.T) {
 	resetStun()
 
@@ -234,3 +309,39 @@ func resetStun() {
 	stunWG = testWG
 	stunHost = ""
 }
+
+func TestConns(t *testing.T) {
+	t.Parallel()
+
+	s := &Server{Peers: map[string]*Conn{
+		"b":       {peer: &protocol.Peer{Id: "b"}},
+		"a":       {peer: &protocol.Peer{Id: "a"}},
+		"pending": nil,
+		"c":       {},
+	}}
+	conns := s.Conns()
+	var ids []string
+	for _, conn := range conns {
+		ids = append(ids, conn.Peer().Id)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("s.Conns() = %v; not %v", ids, want)
+	}
+
+	// Conns can be called while peers connect.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			id := strconv.Itoa(i)
+			s.peersLock.Lock()
+			s.Peers[id] = &Conn{peer: &protocol.Peer{Id: id}}
+			s.peersLock.Unlock()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		s.Conns()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,285 @@
+package network
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ReconnectInterval is how often the peer book is checked for peers to
+	// reconnect to.
+	ReconnectInterval = time.Second
+	// ReconnectMinBackoff is the wait before retrying a peer after its first
+	// failed connection. It doubles with every failure after that.
+	ReconnectMinBackoff = time.Second
+	// ReconnectMaxBackoff caps the wait between retries.
+	ReconnectMaxBackoff = 10 * time.Minute
+	// PeerBookExpiry is how long a peer that keeps failing is kept after it was
+	// last seen.
+	PeerBookExpiry = 7 * 24 * time.Hour
+)
+
+// PeerBookEntry is a peer the node has heard of.
+type PeerBookEntry struct {
+	Addr string `json:"addr"`
+	// KeyPin is the key pin of the key the peer was last seen with at Addr.
+	KeyPin      string    `json:"key_pin,omitempty"`
+	LastSeen    time.Time `json:"last_seen"`
+	Failures    int       `json:"failures"`
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// PeerBook is the set of known peers, saved to disk so the node can rejoin the
+// cluster after a restart.
+type PeerBook struct {
+	path  string
+	mu    sync.Mutex
+	peers map[string]*PeerBookEntry
+	dirty bool
+}
+
+// NewPeerBook returns an empty peer book that is saved to path. A peer book
+// without a path is only kept in memory.
+func NewPeerBook(path string) *PeerBook {
+	return &PeerBook{path: path, peers: make(map[string]*PeerBookEntry)}
+}
+
+// OpenPeerBook loads the peer book saved at path. A missing file is an empty
+// peer book.
+func OpenPeerBook(path string) (*PeerBook, error) {
+	b := NewPeerBook(path)
+	if len(path) == 0 {
+		return b, nil
+	}
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return b, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var entries []*PeerBookEntry
+	if err := json.Unmarshal(buf, &entries); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		b.peers[entry.Addr] = entry
+	}
+	return b, nil
+}
+
+// Add records a peer address.
+func (b *PeerBook) Add(addr string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entry(addr, "")
+}
+
+// Seen records a successful connection to a peer and resets its failures. The
+// key pin is updated if set.
+func (b *PeerBook) Seen(addr, pin string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry := b.entry(addr, pin)
+	entry.LastSeen = time.Now()
+	entry.Failures = 0
+	entry.NextAttempt = time.Time{}
+}
+
+// Failed records a failed connection to a peer and backs off retrying it.
+// Peers that haven't been seen within PeerBookExpiry are forgotten.
+func (b *PeerBook) Failed(addr string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry := b.entry(addr, "")
+	entry.Failures++
+	now := time.Now()
+	if now.Sub(entry.LastSeen) > PeerBookExpiry {
+		delete(b.peers, addr)
+		return
+	}
+	entry.NextAttempt = now.Add(backoff(entry.Failures))
+}
+
+// Attempted backs off a peer while a connection to it is being made.
+func (b *PeerBook) Attempted(addr string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry := b.entry(addr, "")
+	entry.NextAttempt = time.Now().Add(backoff(entry.Failures + 1))
+}
+
+// KeyPin returns the key pin the peer at addr was last seen with, or "" if
+// there is none.
+func (b *PeerBook) KeyPin(addr string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if entry, ok := b.peers[addr]; ok {
+		return entry.KeyPin
+	}
+	return ""
+}
+
+// entry returns the entry for an address, creating it if needed. The lock must
+// be held.
+func (b *PeerBook) entry(addr, pin string) *PeerBookEntry {
+	b.dirty = true
+	entry, ok := b.peers[addr]
+	if !ok {
+		entry = &PeerBookEntry{Addr: addr, LastSeen: time.Now()}
+		b.peers[addr] = entry
+	}
+	if len(pin) > 0 {
+		entry.KeyPin = pin
+	}
+	return entry
+}
+
+// backoff returns how long to wait before retrying a peer after a number of
+// failures.
+func backoff(failures int) time.Duration {
+	wait := ReconnectMinBackoff
+	for i := 1; i < failures && wait < ReconnectMaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > ReconnectMaxBackoff {
+		wait = ReconnectMaxBackoff
+	}
+	return wait
+}
+
+// Due returns the peers that can be retried at the time, ordered by address.
+func (b *PeerBook) Due(now time.Time) []PeerBookEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var due []PeerBookEntry
+	for _, entry := range b.peers {
+		if !entry.NextAttempt.After(now) {
+			due = append(due, *entry)
+		}
+	}
+	sort.Sort(peerBookSlice(due))
+	return due
+}
+
+// Entries returns all the known peers ordered by address.
+func (b *PeerBook) Entries() []PeerBookEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var entries []PeerBookEntry
+	for _, entry := range b.peers {
+		entries = append(entries, *entry)
+	}
+	sort.Sort(peerBookSlice(entries))
+	return entries
+}
+
+// Save writes the peer book to disk if it changed.
+func (b *PeerBook) Save() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.dirty || len(b.path) == 0 {
+		return nil
+	}
+	var entries []*PeerBookEntry
+	for _, entry := range b.peers {
+		entries = append(entries, entry)
+	}
+	buf, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	if err := WriteFileAtomic(b.path, buf); err != nil {
+		return err
+	}
+	b.dirty = false
+	return nil
+}
+
+// WriteFileAtomic writes the file to a temporary file next to it and renames
+// it into place so readers never see a partial write.
+func WriteFileAtomic(path string, buf []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+type peerBookSlice []PeerBookEntry
+
+func (s peerBookSlice) Len() int           { return len(s) }
+func (s peerBookSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s peerBookSlice) Less(i, j int) bool { return s[i].Addr < s[j].Addr }
+
+// SetPeerBook replaces the peer book the server records peers in.
+func (s *Server) SetPeerBook(book *PeerBook) {
+	s.peerBook = book
+}
+
+// ReconnectLoop connects to the peers in the peer book that aren't connected
+// and periodically saves it until the server is stopped.
+func (s *Server) ReconnectLoop() {
+	for {
+		s.reconnect()
+		select {
+		case <-s.quit:
+			return
+		case <-time.After(ReconnectInterval):
+		}
+	}
+}
+
+// reconnect dials the peers in the peer book that are due for a retry and
+// aren't connected.
+func (s *Server) reconnect() {
+	for _, entry := range s.peerBook.Due(time.Now()) {
+		if entry.Addr == s.LocalID() {
+			continue
+		}
+		s.peersLock.RLock()
+		_, ok := s.Peers[entry.Addr]
+		s.peersLock.RUnlock()
+		if ok {
+			continue
+		}
+		s.peerBook.Attempted(entry.Addr)
+		s.Printf("Reconnecting to peer %s", entry.Addr)
+		if err := s.ConnectKey(entry.Addr, entry.KeyPin); err != nil {
+			s.Printf("ERR reconnecting to peer %s: %s", entry.Addr, err)
+		}
+	}
+	if err := s.peerBook.Save(); err != nil {
+		s.Printf("ERR saving peer book: %s", err)
+	}
+}
@@ -0,0 +1,154 @@
+package network
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, ReconnectMinBackoff},
+		{1, ReconnectMinBackoff},
+		{2, 2 * ReconnectMinBackoff},
+		{4, 8 * ReconnectMinBackoff},
+		{100, ReconnectMaxBackoff},
+	}
+	for i, td := range testData {
+		if out := backoff(td.failures); out != td.want {
+			t.Errorf("%d. backoff(%d) = %s; not %s", i, td.failures, out, td.want)
+		}
+	}
+}
+
+func TestPeerBook(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "degdb-peerbook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "peers")
+
+	book, err := OpenPeerBook(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	book.Add("a:1")
+	book.Seen("b:1", "pin_b")
+	book.Failed("b:1")
+	book.Failed("b:1")
+
+	now := time.Now()
+	due := book.Due(now)
+	if len(due) != 1 || due[0].Addr != "a:1" {
+		t.Errorf("Due() = %+v; expected only a:1", due)
+	}
+	if due := book.Due(now.Add(backoff(2))); len(due) != 2 {
+		t.Errorf("Due() after the backoff = %+v; expected both peers", due)
+	}
+	book.Seen("b:1", "")
+	if due := book.Due(now); len(due) != 2 || due[1].Failures != 0 || due[1].KeyPin != "pin_b" {
+		t.Errorf("Due() after seeing b:1 = %+v; expected both peers without failures", due)
+	}
+
+	if err := book.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if matches, _ := filepath.Glob(path + ".tmp*"); len(matches) > 0 {
+		t.Errorf("Save() left temporary files %v", matches)
+	}
+	loaded, err := OpenPeerBook(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := book.Entries()
+	out := loaded.Entries()
+	if len(out) != len(want) {
+		t.Fatalf("OpenPeerBook() = %+v; not %+v", out, want)
+	}
+	for i, entry := range out {
+		w := want[i]
+		if entry.Addr != w.Addr || entry.KeyPin != w.KeyPin || entry.Failures != w.Failures ||
+			!entry.LastSeen.Equal(w.LastSeen) || !entry.NextAttempt.Equal(w.NextAttempt) {
+			t.Errorf("%d. OpenPeerBook() = %+v; not %+v", i, entry, w)
+		}
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	t.Parallel()
+
+	s, _ := keyedServer(t)
+	defer s.Stop()
+	s2, _ := keyedServer(t)
+	defer s2.Stop()
+
+	s2.peerBook.Seen(s.LocalID(), s.keyPin)
+	connected := func() *Conn {
+		for i := 0; i < retryCount; i++ {
+			s2.reconnect()
+			s2.peersLock.RLock()
+			conn := s2.Peers[s.LocalID()]
+			s2.peersLock.RUnlock()
+			if conn != nil {
+				return conn
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Fatalf("%s didn't connect to %s", s2.LocalID(), s.LocalID())
+		return nil
+	}
+	conn := connected()
+
+	// Dropped connections are reconnected.
+	conn.Close()
+	for i := 0; i < retryCount; i++ {
+		s2.peersLock.RLock()
+		_, ok := s2.Peers[s.LocalID()]
+		s2.peersLock.RUnlock()
+		if !ok {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if reconnected := connected(); reconnected == conn {
+		t.Errorf("connection to %s wasn't replaced", s.LocalID())
+	}
+}
+
+func TestOpenDamagedPeerBook(t *testing.T) {
+	t.Parallel()
+
+	f, err := ioutil.TempFile("", "degdb-peerbook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString(`[{"addr": "a:1"}]garbage`)
+	f.Close()
+
+	if _, err := OpenPeerBook(f.Name()); err == nil {
+		t.Errorf("OpenPeerBook() of a damaged file didn't fail")
+	}
+	book := NewPeerBook(f.Name())
+	book.Add("b:1")
+	if err := book.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := OpenPeerBook(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := loaded.Entries(); len(entries) != 1 || entries[0].Addr != "b:1" {
+		t.Errorf("OpenPeerBook() after saving over a damaged file = %+v; expected b:1", entries)
+	}
+}
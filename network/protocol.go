@@ -6,6 +6,7 @@ import (
 
 	"github.com/fatih/color"
 
+	"github.com/degdb/degdb/crypto"
 	"github.com/degdb/degdb/protocol"
 )
 
@@ -13,58 +14,98 @@ func (s *Server) handlePeerNotify(conn *Conn, msg *protocol.Message) {
 	conn.peerRequest <- true
 	peers := msg.GetPeerNotify().Peers
 	for _, peer := range peers {
-		s.peersLock.RLock()
-		_, ok := s.Peers[peer.Id]
-		s.peersLock.RUnlock()
-
-		if ok {
-			continue
-		}
+		s.peerBook.Add(peer.Id)
 
+		// Only connect to peers that fit in the routing table so nodes don't
+		// form a full mesh. The placeholder is set under the same lock so
+		// concurrent notifies don't connect twice.
 		s.peersLock.Lock()
-		s.Peers[peer.Id] = nil
+		_, ok := s.Peers[peer.Id]
+		connect := !ok && s.unproven[peer.Id] == nil && peer.Id != s.LocalID() && s.routes.fits(peer)
+		if connect {
+			s.Peers[peer.Id] = nil
+		}
 		s.peersLock.Unlock()
+		if !connect {
+			continue
+		}
 
-		if err := s.Connect(peer.Id); err != nil {
+		if len(peer.Relay) > 0 && peer.Relay != s.LocalID() {
+			if err := s.ConnectRelay(peer.Relay, peer.Id); err == nil {
+				continue
+			}
+		}
+		if err := s.ConnectKey(peer.Id, peer.KeyPin); err != nil {
 			s.Printf("ERR failed to connect to peer %s", err)
+			s.peersLock.Lock()
+			if s.Peers[peer.Id] == nil {
+				delete(s.Peers, peer.Id)
+			}
+			s.peersLock.Unlock()
 		}
 	}
 }
 
+// handlePeerRequest responds with the peers in the routing table. If the
+// request has a keyspace, it responds with the peers owning its start instead.
 func (s *Server) handlePeerRequest(conn *Conn, msg *protocol.Message) {
-	// TODO(d4l3k): Handle keyspace check.
 	req := msg.GetPeerRequest()
 
 	var peers []*protocol.Peer
-	s.peersLock.RLock()
-	for id, v := range s.Peers {
-		if conn.Peer.Id == id || v == nil {
-			continue
+	if req.Keyspace != nil {
+		var err error
+		if peers, err = s.Lookup(req.Keyspace.Start); err != nil {
+			if err := conn.RespondTo(msg, &protocol.Message{Error: err.Error()}); err != nil {
+				s.Printf("ERR sending PeerNotify: %s", err)
+			}
+			return
 		}
-		peers = append(peers, v.Peer)
-		if req.Limit > 0 && int32(len(peers)) >= req.Limit {
-			break
+	} else {
+		for _, v := range s.routes.conns() {
+			if conn.Peer().Id == v.Peer().Id {
+				continue
+			}
+			peers = append(peers, v.Peer())
+			if req.Limit > 0 && int32(len(peers)) >= req.Limit {
+				break
+			}
 		}
 	}
-	s.peersLock.RUnlock()
 	wrapper := &protocol.Message{Message: &protocol.Message_PeerNotify{
 		PeerNotify: &protocol.PeerNotify{
 			Peers: peers,
 		}}}
-	if err := conn.Send(wrapper); err != nil {
+	if err := conn.RespondTo(msg, wrapper); err != nil {
 		s.Printf("ERR sending PeerNotify: %s", err)
 	}
 }
 
 func (s *Server) handleHandshake(conn *Conn, msg *protocol.Message) {
 	handshake := msg.GetHandshake()
-	conn.Peer = handshake.GetSender()
+	if handshake.Type == protocol.HANDSHAKE_UPDATE {
+		s.handleHandshakeUpdate(conn, handshake)
+		return
+	}
+	// The handshake is authenticated before the peer is recorded or its id is
+	// dialed back.
+	if !s.authenticHandshake(conn, handshake) {
+		s.Printf("ERR handshake from %s doesn't match its key %s", conn.PrettyID(), conn.Author)
+		if err := conn.Close(); err != nil && err != io.EOF {
+			s.Printf("ERR closing connection %s", err)
+		}
+		return
+	}
+	conn.setPeer(handshake.Sender)
 
-	s.peersLock.RLock()
-	peer := s.Peers[conn.Peer.Id]
-	s.peersLock.RUnlock()
+	id := handshake.Sender.Id
+	s.peersLock.Lock()
+	duplicate := s.Peers[id] != nil || s.unproven[id] != nil
+	if !duplicate {
+		s.unproven[id] = conn
+	}
+	s.peersLock.Unlock()
 
-	if peer != nil {
+	if duplicate {
 		s.Printf("Ignoring duplicate peer %s.", conn.PrettyID())
 		if err := conn.Close(); err != nil && err != io.EOF {
 			s.Printf("ERR closing connection %s", err)
@@ -72,21 +113,147 @@ func (s *Server) handleHandshake(conn *Conn, msg *protocol.Message) {
 		return
 	}
 
-	s.peersLock.Lock()
-	s.Peers[conn.Peer.Id] = conn
-	s.peersLock.Unlock()
+	// Proving the id dials back to it, so it's done in the background to not
+	// hold up the connection.
+	go s.provePeer(conn, handshake)
+}
 
-	s.Print(color.GreenString("New peer %s", conn.PrettyID()))
+// authenticHandshake returns whether the sender of a handshake is the key the
+// connection is authenticated as. Direct peers must match their TLS key and
+// relayed ones must have signed the handshake for the node.
+func (s *Server) authenticHandshake(conn *Conn, handshake *protocol.Handshake) bool {
+	sender := handshake.GetSender()
+	if sender == nil {
+		return false
+	}
+	if conn.relay != nil {
+		return sender.Id == conn.relayTo && s.verifyRelayed(conn, handshake)
+	}
+	return sender.Author == conn.Author && sender.KeyPin == conn.KeyPin
+}
+
+// provePeer checks that a peer is the node answering at its id before it's
+// routed to. Peers that can't be reached at their id are only accepted once
+// they advertise the relay they're reached through, see reachedThroughRelay.
+func (s *Server) provePeer(conn *Conn, handshake *protocol.Handshake) {
+	id := conn.Peer().Id
+	reachable, err := s.dialBack(id, conn.KeyPin)
+	if err != nil {
+		s.Printf("ERR handshake from %s: %s", conn.PrettyID(), err)
+		s.peersLock.Lock()
+		if s.unproven[id] == conn {
+			delete(s.unproven, id)
+		}
+		s.peersLock.Unlock()
+		if err := conn.Close(); err != nil && err != io.EOF {
+			s.Printf("ERR closing connection %s", err)
+		}
+		return
+	}
+
+	if reachable || s.reachedThroughRelay(conn) {
+		if !s.acceptPeer(conn) {
+			return
+		}
+	} else {
+		s.Printf("Peer %s can't be reached at its id, waiting for it to relay through the node", conn.PrettyID())
+	}
+	if handshake.Type == protocol.HANDSHAKE_RESPONSE && conn.relay == nil {
+		s.updateReachability(conn, handshake)
+	}
 	if handshake.Type == protocol.HANDSHAKE_INITIAL {
-		if err := s.sendHandshake(conn, protocol.HANDSHAKE_RESPONSE); err != nil {
+		if err := s.sendHandshakeResponse(conn, reachable); err != nil {
 			s.Printf("ERR sendHandshake %s", err)
 		}
-	} else {
+	} else if s.acceptedPeer(conn) {
 		if err := s.sendPeerRequest(conn); err != nil {
 			s.Printf("ERR sendPeerRequest %s", err)
 		}
 	}
+}
+
+// reachedThroughRelay returns whether a peer advertises the relay the node
+// reaches it through, or the node itself for a direct connection. Its id
+// can't be reached any other way, so it can't be taken from another node.
+func (s *Server) reachedThroughRelay(conn *Conn) bool {
+	peer := conn.Peer()
+	if conn.relay != nil {
+		return conn.relay.Peer() != nil && peer.Relay == conn.relay.Peer().Id
+	}
+	return len(peer.Relay) > 0 && peer.Relay == s.LocalID()
+}
+
+// acceptPeer moves a proven connection into the peers it routes to. It returns
+// false if the connection was closed or replaced in the meantime.
+func (s *Server) acceptPeer(conn *Conn) bool {
+	id := conn.Peer().Id
+	s.peersLock.Lock()
+	ok := s.unproven[id] == conn && !conn.Closed
+	if ok {
+		delete(s.unproven, id)
+		s.Peers[id] = conn
+		if len(conn.Author) > 0 {
+			s.nodes[conn.Author] = true
+		}
+	}
+	s.peersLock.Unlock()
+	if !ok {
+		return false
+	}
+
+	s.routes.add(conn)
+	s.peerBook.Seen(id, conn.KeyPin)
+
+	s.Print(color.GreenString("New peer %s", conn.PrettyID()))
 	go s.connHeartbeat(conn)
+	for _, f := range s.peerHandlers {
+		go f(conn)
+	}
+	return true
+}
+
+// acceptedPeer returns whether the connection is routed to.
+func (s *Server) acceptedPeer(conn *Conn) bool {
+	peer := conn.Peer()
+	if peer == nil {
+		return false
+	}
+	s.peersLock.RLock()
+	defer s.peersLock.RUnlock()
+	return s.Peers[peer.Id] == conn
+}
+
+// handleHandshakeUpdate updates the peer information of an established
+// connection, such as a changed keyspace.
+func (s *Server) handleHandshakeUpdate(conn *Conn, handshake *protocol.Handshake) {
+	sender := handshake.GetSender()
+	if conn.relay != nil && !s.verifyRelayed(conn, handshake) {
+		s.Printf("ERR ignoring unsigned handshake update from %s", conn.PrettyID())
+		return
+	}
+	s.peersLock.Lock()
+	accepted := sender != nil && s.Peers[sender.Id] == conn
+	unproven := sender != nil && s.unproven[sender.Id] == conn
+	peer := conn.Peer()
+	valid := (accepted || unproven) && peer != nil && peer.Id == sender.Id && sender.Author == conn.Author && sender.KeyPin == conn.KeyPin
+	if valid {
+		conn.setPeer(sender)
+	}
+	s.peersLock.Unlock()
+
+	if !valid {
+		s.Printf("ERR ignoring handshake update from unknown peer %s", conn.PrettyID())
+		return
+	}
+	if unproven {
+		// Unreachable peers are accepted once they relay through the node.
+		if s.reachedThroughRelay(conn) {
+			s.acceptPeer(conn)
+		}
+		return
+	}
+	s.routes.add(conn)
+	s.Printf("Updated peer %s keyspace %+v", conn.PrettyID(), sender.Keyspace)
 }
 
 func (s *Server) connHeartbeat(conn *Conn) {
@@ -125,10 +292,7 @@ func (s *Server) sendPeerRequest(conn *Conn) error {
 			msg := color.RedString("Peer timed out! %s %+v", conn.PrettyID(), conn)
 			conn.peerRequestRetries++
 			if conn.peerRequestRetries >= 3 {
-				s.peersLock.Lock()
-				delete(s.Peers, conn.Peer.Id)
-				s.peersLock.Unlock()
-
+				s.removePeer(conn)
 				conn.Close()
 			} else {
 				msg += "Retrying..."
@@ -144,12 +308,65 @@ func (s *Server) sendPeerRequest(conn *Conn) error {
 }
 
 func (s *Server) sendHandshake(conn *Conn, typ protocol.Handshake_Type) error {
+	return s.writeHandshake(conn, &protocol.Handshake{
+		Type:   typ,
+		Sender: s.LocalPeer(),
+	})
+}
+
+// sendHandshakeResponse responds to a HANDSHAKE_INITIAL with the address the
+// connection came from and whether the peer's id can be connected to.
+func (s *Server) sendHandshakeResponse(conn *Conn, reachable bool) error {
+	handshake := &protocol.Handshake{
+		Type:      protocol.HANDSHAKE_RESPONSE,
+		Sender:    s.LocalPeer(),
+		Reachable: reachable,
+	}
+	if conn.relay == nil && conn.Conn != nil {
+		handshake.Observed = conn.RemoteAddr().String()
+	}
+	return s.writeHandshake(conn, handshake)
+}
+
+// writeHandshake sends a handshake, signing it if it's relayed.
+func (s *Server) writeHandshake(conn *Conn, handshake *protocol.Handshake) error {
+	if conn.relay != nil && s.key != nil {
+		handshake.To = conn.relayTo
+		handshake.Created = time.Now().Unix()
+		if err := s.key.SignHandshake(handshake); err != nil {
+			return err
+		}
+	}
 	return conn.Send(&protocol.Message{
 		Message: &protocol.Message_Handshake{
-			Handshake: &protocol.Handshake{
-				Type:   typ,
-				Sender: s.LocalPeer(),
-			},
+			Handshake: handshake,
 		},
 	})
 }
+
+// verifyRelayed returns whether a relayed handshake was signed for the node
+// by the sender's key recently. It authenticates the connection as the
+// sender's key. Nodes without a key trust the relay.
+func (s *Server) verifyRelayed(conn *Conn, handshake *protocol.Handshake) bool {
+	if s.key == nil {
+		if len(conn.KeyPin) == 0 {
+			conn.Author = handshake.GetSender().GetAuthor()
+			conn.KeyPin = handshake.GetSender().GetKeyPin()
+		}
+		return true
+	}
+	age := time.Since(time.Unix(handshake.Created, 0))
+	if handshake.To != s.LocalID() || age > RelayedHandshakeMaxAge || age < -RelayedHandshakeMaxAge {
+		return false
+	}
+	if err := crypto.VerifyHandshake(handshake); err != nil {
+		s.Printf("ERR relayed handshake from %s: %s", conn.PrettyID(), err)
+		return false
+	}
+	if len(conn.KeyPin) > 0 {
+		return conn.KeyPin == handshake.Sender.KeyPin
+	}
+	conn.Author = handshake.Sender.Author
+	conn.KeyPin = handshake.Sender.KeyPin
+	return true
+}
@@ -0,0 +1,175 @@
+package network
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// DialBackTimeout is how long a node tries to connect back to a new peer and
+// authenticate it to check whether it can be reached.
+var DialBackTimeout = 2 * time.Second
+
+// RelayedHandshakeMaxAge is how old the signature of a relayed handshake may
+// be so relays can't replay it much later.
+var RelayedHandshakeMaxAge = time.Minute
+
+var ErrNoRelay = errors.New("not connected to the relay peer")
+
+// dialBack connects to the id a peer claims and returns whether it can be
+// reached. If the node has a key, the node answering at the id must
+// authenticate with the key pin so peers can't take the id of another node:
+// ErrPeerIDMismatch is returned if a different key answers and
+// ErrPeerIDUnproven if the TLS handshake fails. Ids that can't be reached can't
+// be proven, so they must not have been seen with a different key before. It's
+// only called once the handshake is authenticated, so a relayed handshake
+// can't make the node dial an id it didn't sign for.
+func (s *Server) dialBack(id, pin string) (bool, error) {
+	netConn, err := net.DialTimeout("tcp", id, DialBackTimeout)
+	if err != nil {
+		if seen := s.peerBook.KeyPin(id); len(seen) > 0 && seen != pin {
+			return false, ErrPeerIDMismatch
+		}
+		return false, nil
+	}
+	defer netConn.Close()
+	// Bound the TLS handshake too, so peers can't stall their proof.
+	timer := time.AfterFunc(DialBackTimeout, func() { netConn.Close() })
+	defer timer.Stop()
+	if s.tlsConfig == nil {
+		return true, nil
+	}
+	if _, err := s.dialTLS(netConn, pin); err == ErrPeerKeyMismatch {
+		return true, ErrPeerIDMismatch
+	} else if err != nil {
+		return true, ErrPeerIDUnproven
+	}
+	return true, nil
+}
+
+// updateReachability records a peer's view of the node from a
+// HANDSHAKE_RESPONSE. If the peer couldn't connect back, the node asks peers to
+// relay through it and announces the change.
+func (s *Server) updateReachability(conn *Conn, handshake *protocol.Handshake) {
+	if host, _, err := net.SplitHostPort(handshake.Observed); err == nil && host != s.IP {
+		s.Printf("Peer %s sees connections from %s, advertising %s", conn.PrettyID(), host, s.IP)
+	}
+	s.relayLock.Lock()
+	changed := false
+	if handshake.Reachable && len(s.relay) > 0 {
+		s.Printf("Reachable at %s, no longer relaying", s.LocalID())
+		s.relay = ""
+		changed = true
+	} else if !handshake.Reachable && len(s.relay) == 0 {
+		s.Print(color.YellowString("Not reachable at %s, relaying through %s", s.LocalID(), conn.Peer().Id))
+		s.relay = conn.Peer().Id
+		changed = true
+	}
+	s.relayLock.Unlock()
+	if changed {
+		s.announce()
+	}
+}
+
+// relayID returns the id of the peer relaying for the node or "" if it's
+// reachable.
+func (s *Server) relayID() string {
+	s.relayLock.Lock()
+	defer s.relayLock.Unlock()
+	return s.relay
+}
+
+// ConnectRelay connects to a peer through a connected peer that relays for
+// it. The handshakes are signed by the peers so the relay can't impersonate
+// them.
+func (s *Server) ConnectRelay(relay, id string) error {
+	s.peersLock.RLock()
+	via := s.Peers[relay]
+	s.peersLock.RUnlock()
+	if via == nil || via.relay != nil {
+		return ErrNoRelay
+	}
+	return s.sendHandshake(s.relayedConn(via, id), protocol.HANDSHAKE_INITIAL)
+}
+
+// relayedConn returns the connection for messages relayed through via to and
+// from the peer id.
+func (s *Server) relayedConn(via *Conn, id string) *Conn {
+	s.relayLock.Lock()
+	defer s.relayLock.Unlock()
+	if conn, ok := s.relayed[id]; ok && conn.relay == via && !conn.Closed {
+		return conn
+	}
+	conn := s.NewConn(nil)
+	conn.peer = &protocol.Peer{Id: id}
+	conn.relay = via
+	conn.relayTo = id
+	s.relayed[id] = conn
+	return conn
+}
+
+// removeRelayed forgets a closed relayed connection.
+func (s *Server) removeRelayed(conn *Conn) {
+	s.relayLock.Lock()
+	if s.relayed[conn.relayTo] == conn {
+		delete(s.relayed, conn.relayTo)
+	}
+	s.relayLock.Unlock()
+	s.removePeer(conn)
+}
+
+// handleRelay handles messages relayed to the node and forwards messages for
+// the peers it relays for.
+func (s *Server) handleRelay(conn *Conn, msg *protocol.Message) {
+	relay := msg.GetRelay()
+	if conn.relay != nil || !s.acceptedPeer(conn) {
+		s.Printf("ERR dropping relay message from unknown peer %s", conn.PrettyID())
+		return
+	}
+	if relay.To == s.LocalID() {
+		req := &protocol.Message{}
+		if err := req.Unmarshal(relay.Message); err != nil || len(relay.From) == 0 {
+			s.Printf("ERR invalid relayed message from %s via %s: %v", relay.From, conn.PrettyID(), err)
+			return
+		}
+		relayed := s.relayedConn(conn, relay.From)
+		// Until the signed handshake is accepted the sender is unproven, so
+		// anything but the handshake is dropped.
+		s.peersLock.RLock()
+		accepted := s.Peers[relay.From] == relayed
+		s.peersLock.RUnlock()
+		if !accepted && req.GetHandshake() == nil {
+			s.Printf("ERR dropping relayed message from %s via %s before its handshake", relay.From, conn.PrettyID())
+			return
+		}
+		if err := s.dispatch(relayed, req); err != nil {
+			s.Printf("ERR relayed message from %s: %s", relayed.PrettyID(), err)
+			relayed.Close()
+		}
+		return
+	}
+
+	s.peersLock.RLock()
+	to := s.Peers[relay.To]
+	s.peersLock.RUnlock()
+	if to == nil || to.relay != nil {
+		s.Printf("ERR can't relay message from %s to %s", conn.PrettyID(), relay.To)
+		return
+	}
+	forward := &protocol.Message{
+		Message: &protocol.Message_Relay{
+			Relay: &protocol.Relay{
+				From:    conn.Peer().Id,
+				To:      relay.To,
+				Message: relay.Message,
+			},
+		},
+	}
+	if err := to.Send(forward); err != nil {
+		s.Printf("ERR relaying message to %s: %s", to.PrettyID(), err)
+	}
+}
@@ -0,0 +1,278 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// closedPort returns a port nothing is listening on.
+func closedPort(t *testing.T) int {
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+	return port
+}
+
+// waitPeer waits until s has a connection to id that satisfies ok.
+func waitPeer(t *testing.T, s *Server, id string, ok func(*Conn) bool) *Conn {
+	for i := 0; i < retryCount; i++ {
+		s.peersLock.RLock()
+		conn := s.Peers[id]
+		s.peersLock.RUnlock()
+		if conn != nil && ok(conn) {
+			return conn
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("%s has no matching connection to %s", s.LocalID(), id)
+	return nil
+}
+
+func TestRelay(t *testing.T) {
+	t.Parallel()
+
+	r, _ := keyedServer(t)
+	defer r.Stop()
+	p, _ := keyedServer(t)
+	defer p.Stop()
+	a, _ := keyedServer(t)
+	defer a.Stop()
+
+	// p advertises an address it can't be reached at.
+	p.Port = closedPort(t)
+	if err := p.Connect(r.LocalID()); err != nil {
+		t.Fatal(err)
+	}
+	waitPeer(t, r, p.LocalID(), func(conn *Conn) bool {
+		return conn.Peer().Relay == r.LocalID()
+	})
+	if relay := p.relayID(); relay != r.LocalID() {
+		t.Errorf("%s relays through %q; not %q", p.LocalID(), relay, r.LocalID())
+	}
+
+	// a learns about p from r and connects through it.
+	if err := a.Connect(r.LocalID()); err != nil {
+		t.Fatal(err)
+	}
+	waitPeer(t, a, p.LocalID(), func(conn *Conn) bool {
+		return conn.relay != nil
+	})
+	waitPeer(t, p, a.LocalID(), func(*Conn) bool { return true })
+	if relay := a.relayID(); relay != "" {
+		t.Errorf("%s relays through %q; it's reachable", a.LocalID(), relay)
+	}
+}
+
+func TestRelayImpersonation(t *testing.T) {
+	t.Parallel()
+
+	r, rAuthor := keyedServer(t)
+	defer r.Stop()
+	a, _ := keyedServer(t)
+	defer a.Stop()
+	victim, victimAuthor := keyedServer(t)
+	defer victim.Stop()
+
+	if err := r.Connect(a.LocalID()); err != nil {
+		t.Fatal(err)
+	}
+	toA := waitPeer(t, r, a.LocalID(), func(*Conn) bool { return true })
+
+	signed := &protocol.Handshake{
+		Sender:  &protocol.Peer{Id: victim.LocalID(), Author: rAuthor, KeyPin: r.keyPin},
+		To:      a.LocalID(),
+		Created: time.Now().Unix(),
+	}
+	if err := r.key.SignHandshake(signed); err != nil {
+		t.Fatal(err)
+	}
+	testData := []*protocol.Handshake{
+		// Unsigned handshake claiming the victim's key.
+		{Sender: &protocol.Peer{Id: victim.LocalID(), Author: victimAuthor, KeyPin: victim.keyPin}},
+		// Handshake signed by the relay claiming the victim's id.
+		signed,
+	}
+	for i, handshake := range testData {
+		data, err := (&protocol.Message{
+			Message: &protocol.Message_Handshake{Handshake: handshake},
+		}).Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = toA.Send(&protocol.Message{
+			Message: &protocol.Message_Relay{
+				Relay: &protocol.Relay{From: victim.LocalID(), To: a.LocalID(), Message: data},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(200 * time.Millisecond)
+		a.peersLock.RLock()
+		conn := a.Peers[victim.LocalID()]
+		a.peersLock.RUnlock()
+		if conn != nil {
+			t.Errorf("%d. relay impersonated %s with %+v", i, victim.LocalID(), handshake)
+		}
+	}
+}
+
+func TestRelayBeforeHandshake(t *testing.T) {
+	t.Parallel()
+
+	r, _ := keyedServer(t)
+	defer r.Stop()
+	a, _ := keyedServer(t)
+	defer a.Stop()
+	handled := make(chan *Conn, 1)
+	a.Handle("QueryRequest", func(conn *Conn, msg *protocol.Message) {
+		handled <- conn
+	})
+
+	if err := r.Connect(a.LocalID()); err != nil {
+		t.Fatal(err)
+	}
+	toA := waitPeer(t, r, a.LocalID(), func(*Conn) bool { return true })
+
+	// Messages relayed before the sender's handshake is accepted are dropped.
+	data, err := (&protocol.Message{
+		Message: &protocol.Message_QueryRequest{QueryRequest: &protocol.QueryRequest{}},
+	}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = toA.Send(&protocol.Message{
+		Message: &protocol.Message_Relay{
+			Relay: &protocol.Relay{From: "localhost:1", To: a.LocalID(), Message: data},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case conn := <-handled:
+		t.Errorf("QueryRequest relayed from %s before its handshake was handled", conn.PrettyID())
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestUnprovenPeer(t *testing.T) {
+	t.Parallel()
+
+	r, _ := keyedServer(t)
+	defer r.Stop()
+	p, _ := keyedServer(t)
+	defer p.Stop()
+
+	// p's id accepts connections but never authenticates.
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	held := make(chan net.Conn, 10)
+	defer func() {
+		for {
+			select {
+			case conn := <-held:
+				conn.Close()
+			default:
+				return
+			}
+		}
+	}()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			held <- conn
+		}
+	}()
+	p.Port = ln.Addr().(*net.TCPAddr).Port
+
+	if err := p.Connect(r.LocalID()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	r.peersLock.RLock()
+	conn, unproven := r.Peers[p.LocalID()], r.unproven[p.LocalID()]
+	r.peersLock.RUnlock()
+	if conn != nil || unproven == nil {
+		t.Fatalf("%s accepted %s before proving its id", r.LocalID(), p.LocalID())
+	}
+	for _, conn := range r.routes.conns() {
+		if conn.Peer().Id == p.LocalID() {
+			t.Errorf("%s routes to unproven %s", r.LocalID(), p.LocalID())
+		}
+	}
+
+	// The dial back times out and the peer is dropped.
+	time.Sleep(DialBackTimeout)
+	for i := 0; i < retryCount && unproven != nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		r.peersLock.RLock()
+		unproven = r.unproven[p.LocalID()]
+		r.peersLock.RUnlock()
+	}
+	if unproven != nil {
+		t.Errorf("%s kept unproven %s after the dial back timed out", r.LocalID(), p.LocalID())
+	}
+}
+
+func TestDialBack(t *testing.T) {
+	t.Parallel()
+
+	s, _ := keyedServer(t)
+	defer s.Stop()
+	owner, _ := keyedServer(t)
+	defer owner.Stop()
+
+	// Something that answers without TLS.
+	ln, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	// An unreachable id that was seen with the owner's key.
+	seen := fmt.Sprintf("localhost:%d", closedPort(t))
+	s.peerBook.Seen(seen, owner.keyPin)
+	unseen := fmt.Sprintf("localhost:%d", closedPort(t))
+
+	testData := []struct {
+		id, pin   string
+		reachable bool
+		err       error
+	}{
+		{owner.LocalID(), owner.keyPin, true, nil},
+		{owner.LocalID(), s.keyPin, true, ErrPeerIDMismatch},
+		{ln.Addr().String(), s.keyPin, true, ErrPeerIDUnproven},
+		{seen, owner.keyPin, false, nil},
+		{seen, s.keyPin, false, ErrPeerIDMismatch},
+		{unseen, s.keyPin, false, nil},
+	}
+	for i, td := range testData {
+		reachable, err := s.dialBack(td.id, td.pin)
+		if reachable != td.reachable || err != td.err {
+			t.Errorf("%d. dialBack(%q, %q) = %t, %v; not %t, %v", i, td.id, td.pin, reachable, err, td.reachable, td.err)
+		}
+	}
+}
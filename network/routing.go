@@ -0,0 +1,294 @@
+package network
+
+import (
+	"math/bits"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+var (
+	// BucketSize is the number of peers kept in each bucket of the routing
+	// table.
+	BucketSize = 8
+	// LookupConnectTimeout is how long Replicas waits for the handshake with
+	// an owner found by a lookup.
+	LookupConnectTimeout = 5 * time.Second
+)
+
+// routingTable is a bounded set of peers the node routes through. Peers are
+// placed on the keyspace ring by the start of their keyspace and bucket i holds
+// the peers between 2^i and 2^(i+1) clockwise from the local keyspace start.
+// Like Chord's fingers, each bucket keeps the peers closest to the start of its
+// range so every hop of a lookup at least halves the distance to the owner.
+type routingTable struct {
+	local   func() uint64
+	lock    sync.RWMutex
+	buckets [64][]*Conn
+}
+
+func newRoutingTable(local func() uint64) *routingTable {
+	return &routingTable{local: local}
+}
+
+// routable returns whether the connection has the peer information needed to
+// place it on the ring.
+func routable(conn *Conn) bool {
+	if conn == nil {
+		return false
+	}
+	peer := conn.Peer()
+	return peer != nil && peer.Keyspace != nil
+}
+
+// offset returns the clockwise distance of the peer from the local keyspace
+// start.
+func (t *routingTable) offset(peer *protocol.Peer) uint64 {
+	return peer.Keyspace.Start - t.local()
+}
+
+// bucket returns the index of the bucket for a clockwise distance.
+func bucket(offset uint64) int {
+	if offset == 0 {
+		return 0
+	}
+	return bits.Len64(offset) - 1
+}
+
+// fits returns whether the peer would be kept in the routing table.
+func (t *routingTable) fits(peer *protocol.Peer) bool {
+	if peer == nil || peer.Keyspace == nil {
+		return false
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	offset := t.offset(peer)
+	b := t.buckets[bucket(offset)]
+	if len(b) < BucketSize {
+		return true
+	}
+	for _, conn := range b {
+		if conn.Peer().Id == peer.Id {
+			return true
+		}
+	}
+	return offset < t.offset(b[len(b)-1].Peer())
+}
+
+// add places a connection in the routing table, replacing the connection with
+// the same peer ID. If the bucket is full, the farthest peer is dropped. It
+// returns whether the connection was kept.
+func (t *routingTable) add(conn *Conn) bool {
+	if !routable(conn) {
+		return false
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.removeLocked(conn.Peer().Id)
+	return t.insertLocked(conn)
+}
+
+func (t *routingTable) insertLocked(conn *Conn) bool {
+	offset := t.offset(conn.Peer())
+	i := bucket(offset)
+	b := t.buckets[i]
+	pos := sort.Search(len(b), func(j int) bool {
+		return t.offset(b[j].Peer()) > offset
+	})
+	if pos >= BucketSize {
+		return false
+	}
+	b = append(b, nil)
+	copy(b[pos+1:], b[pos:])
+	b[pos] = conn
+	if len(b) > BucketSize {
+		b = b[:BucketSize]
+	}
+	t.buckets[i] = b
+	return true
+}
+
+// remove drops a connection from the routing table unless it was replaced.
+func (t *routingTable) remove(conn *Conn) {
+	if conn == nil || conn.Peer() == nil {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for i, b := range t.buckets {
+		for j, c := range b {
+			if c == conn {
+				t.buckets[i] = append(b[:j:j], b[j+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (t *routingTable) removeLocked(id string) {
+	for i, b := range t.buckets {
+		for j, c := range b {
+			if c.Peer().Id == id {
+				t.buckets[i] = append(b[:j:j], b[j+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// rebuild places the connections again after the local keyspace changed.
+func (t *routingTable) rebuild() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var conns []*Conn
+	for i, b := range t.buckets {
+		conns = append(conns, b...)
+		t.buckets[i] = nil
+	}
+	for _, conn := range conns {
+		t.insertLocked(conn)
+	}
+}
+
+// conns returns the connections in the routing table ordered by peer ID.
+func (t *routingTable) conns() []*Conn {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var conns []*Conn
+	for _, b := range t.buckets {
+		conns = append(conns, b...)
+	}
+	sort.Sort(connsByID(conns))
+	return conns
+}
+
+// owners returns the connections in the routing table that have the hash in
+// their keyspace, ordered by peer ID.
+func (t *routingTable) owners(hash uint64) []*Conn {
+	var owners []*Conn
+	for _, conn := range t.conns() {
+		if conn.Peer().Keyspace.Includes(hash) {
+			owners = append(owners, conn)
+		}
+	}
+	return owners
+}
+
+// next returns the peer in the routing table that is closest to the hash and
+// closer than the local node, or nil if there is none.
+func (t *routingTable) next(hash uint64) *Conn {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	var best *Conn
+	distance := hash - t.local()
+	for _, b := range t.buckets {
+		for _, conn := range b {
+			if d := hash - conn.Peer().Keyspace.Start; d < distance {
+				best = conn
+				distance = d
+			}
+		}
+	}
+	return best
+}
+
+type connsByID []*Conn
+
+func (s connsByID) Len() int           { return len(s) }
+func (s connsByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s connsByID) Less(i, j int) bool { return s[i].Peer().Id < s[j].Peer().Id }
+
+// Lookup returns the peers that have the hash in their keyspace. If none are in
+// the routing table, the lookup is forwarded to the peer closest to the hash,
+// which takes O(log n) hops.
+func (s *Server) Lookup(hash uint64) ([]*protocol.Peer, error) {
+	var peers []*protocol.Peer
+	for _, conn := range s.routes.owners(hash) {
+		peers = append(peers, conn.Peer())
+	}
+	if len(peers) > 0 {
+		return peers, nil
+	}
+	next := s.routes.next(hash)
+	if next == nil {
+		return nil, ErrNoRecipients
+	}
+	resp, err := next.Request(&protocol.Message{
+		Message: &protocol.Message_PeerRequest{
+			PeerRequest: &protocol.PeerRequest{
+				Keyspace: &protocol.Keyspace{Start: hash, End: hash + 1},
+			}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Error) > 0 {
+		return nil, ErrNoRecipients
+	}
+	peers = resp.GetPeerNotify().GetPeers()
+	if len(peers) == 0 {
+		return nil, ErrNoRecipients
+	}
+	return peers, nil
+}
+
+// Replicas returns the peers that have the hash in their keyspace, ordered by
+// ID. If the routing table has none, they're found with Lookup and connected
+// to.
+func (s *Server) Replicas(hash uint64) []*Conn {
+	if owners := s.routes.owners(hash); len(owners) > 0 {
+		return owners
+	}
+	peers, err := s.Lookup(hash)
+	if err != nil {
+		return nil
+	}
+	var conns []*Conn
+	for _, peer := range peers {
+		if peer.Id == s.LocalID() {
+			continue
+		}
+		conn, err := s.connectPeer(peer)
+		if err != nil {
+			s.Printf("ERR connecting to replica %s: %s", peer.Id, err)
+			continue
+		}
+		conns = append(conns, conn)
+	}
+	sort.Sort(connsByID(conns))
+	return conns
+}
+
+// connectPeer returns the connection to a peer, connecting to it directly or
+// through its relay and waiting for the handshake if needed.
+func (s *Server) connectPeer(peer *protocol.Peer) (*Conn, error) {
+	s.peersLock.RLock()
+	conn := s.Peers[peer.Id]
+	s.peersLock.RUnlock()
+	if conn != nil {
+		return conn, nil
+	}
+	if len(peer.Relay) == 0 || peer.Relay == s.LocalID() || s.ConnectRelay(peer.Relay, peer.Id) != nil {
+		if err := s.ConnectKey(peer.Id, peer.KeyPin); err != nil {
+			return nil, err
+		}
+	}
+	for deadline := time.Now().Add(LookupConnectTimeout); time.Now().Before(deadline); {
+		s.peersLock.RLock()
+		conn := s.Peers[peer.Id]
+		s.peersLock.RUnlock()
+		if conn != nil {
+			return conn, nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil, Timeout
+}
@@ -0,0 +1,173 @@
+package network
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestBucket(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		offset uint64
+		want   int
+	}{
+		{0, 0},
+		{1, 0},
+		{2, 1},
+		{3, 1},
+		{1 << 40, 40},
+		{math.MaxUint64, 63},
+	}
+	for i, td := range testData {
+		if out := bucket(td.offset); out != td.want {
+			t.Errorf("%d. bucket(%d) = %d; not %d", i, td.offset, out, td.want)
+		}
+	}
+}
+
+// ringConns returns connections for n peers that split the ring into
+// contiguous keyspaces, ordered by keyspace start.
+func ringConns(n int) []*Conn {
+	r := rand.New(rand.NewSource(int64(n)))
+	starts := make([]uint64, n)
+	for i := range starts {
+		starts[i] = uint64(r.Int63())<<1 | uint64(r.Int63n(2))
+	}
+	sort.Sort(uint64Slice(starts))
+	conns := make([]*Conn, n)
+	for i, start := range starts {
+		conns[i] = &Conn{peer: &protocol.Peer{
+			Id:       strconv.Itoa(i),
+			Keyspace: &protocol.Keyspace{Start: start, End: starts[(i+1)%n]},
+		}}
+	}
+	return conns
+}
+
+type uint64Slice []uint64
+
+func (s uint64Slice) Len() int           { return len(s) }
+func (s uint64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s uint64Slice) Less(i, j int) bool { return s[i] < s[j] }
+
+func TestRoutingTableBounded(t *testing.T) {
+	t.Parallel()
+
+	conns := ringConns(500)
+	table := newRoutingTable(func() uint64 { return 0 })
+	for _, i := range rand.Perm(len(conns)) {
+		table.add(conns[i])
+	}
+	if n := len(table.conns()); n > 64*BucketSize {
+		t.Errorf("len(table.conns()) = %d; expected at most %d", n, 64*BucketSize)
+	}
+	for i, b := range table.buckets {
+		if len(b) > BucketSize {
+			t.Errorf("bucket %d has %d peers; expected at most %d", i, len(b), BucketSize)
+		}
+	}
+
+	// Each bucket keeps the peers closest to the start of its range.
+	kept := make(map[*Conn]bool)
+	for _, conn := range table.conns() {
+		kept[conn] = true
+	}
+	perBucket := make(map[int]int)
+	for _, conn := range conns {
+		b := bucket(conn.Peer().Keyspace.Start)
+		if perBucket[b] < BucketSize && !kept[conn] {
+			t.Errorf("peer %s at %d isn't in bucket %d", conn.Peer().Id, conn.Peer().Keyspace.Start, b)
+		}
+		perBucket[b]++
+		if fits := table.fits(conn.Peer()); fits != kept[conn] {
+			t.Errorf("table.fits(%s) = %t; not %t", conn.Peer().Id, fits, kept[conn])
+		}
+	}
+
+	table.remove(conns[0])
+	for _, conn := range table.conns() {
+		if conn == conns[0] {
+			t.Errorf("peer %s wasn't removed", conns[0].Peer().Id)
+		}
+	}
+}
+
+func TestRoutingHops(t *testing.T) {
+	t.Parallel()
+
+	const n = 500
+	conns := ringConns(n)
+	tables := make(map[string]*routingTable)
+	for _, conn := range conns {
+		start := conn.Peer().Keyspace.Start
+		table := newRoutingTable(func() uint64 { return start })
+		for _, i := range rand.Perm(n) {
+			if conns[i] != conn {
+				table.add(conns[i])
+			}
+		}
+		tables[conn.Peer().Id] = table
+	}
+
+	maxHops := 2 * int(math.Log2(n))
+	for i := 0; i < 100; i++ {
+		hash := uint64(rand.Int63())
+		from := conns[rand.Intn(n)]
+		hops := 0
+		for !from.Peer().Keyspace.Includes(hash) {
+			table := tables[from.Peer().Id]
+			if owners := table.owners(hash); len(owners) > 0 {
+				from = owners[0]
+			} else if from = table.next(hash); from == nil {
+				t.Fatalf("%d. lookup of %d got stuck", i, hash)
+			}
+			hops++
+		}
+		if hops > maxHops {
+			t.Errorf("%d. lookup of %d took %d hops; expected at most %d", i, hash, hops, maxHops)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	a, _ := keyedServer(t)
+	defer a.Stop()
+	b, _ := keyedServer(t)
+	defer b.Stop()
+	c, _ := keyedServer(t)
+	defer c.Stop()
+
+	a.SetKeyspace(&protocol.Keyspace{Start: 0, End: 1 << 62})
+	b.SetKeyspace(&protocol.Keyspace{Start: 1 << 62, End: 1 << 63})
+	c.SetKeyspace(&protocol.Keyspace{Start: 1 << 63, End: 0})
+
+	if err := a.Connect(b.LocalID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Connect(b.LocalID()); err != nil {
+		t.Fatal(err)
+	}
+	waitPeer(t, b, a.LocalID(), func(*Conn) bool { return true })
+	waitPeer(t, b, c.LocalID(), func(*Conn) bool { return true })
+
+	hash := uint64(1<<63 + 1)
+	peers, err := a.Lookup(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) != 1 || peers[0].Id != c.LocalID() {
+		t.Errorf("a.Lookup(%d) = %+v; not %s", hash, peers, c.LocalID())
+	}
+	replicas := a.Replicas(hash)
+	if len(replicas) != 1 || replicas[0].Peer().Id != c.LocalID() {
+		t.Errorf("a.Replicas(%d) = %+v; not %s", hash, replicas, c.LocalID())
+	}
+}
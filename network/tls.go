@@ -0,0 +1,103 @@
+package network
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/degdb/degdb/crypto"
+)
+
+// tlsHandshakeRecord is the first byte of a TLS ClientHello.
+const tlsHandshakeRecord = 0x16
+
+// TLSHandshakeTimeout is how long a peer has to complete the TLS handshake.
+var TLSHandshakeTimeout = 10 * time.Second
+
+var (
+	ErrUnauthenticated = errors.New("peer connections must be authenticated with TLS")
+	ErrPeerKeyMismatch = errors.New("peer's key doesn't match its key pin")
+	ErrPeerIDMismatch  = errors.New("a different key answers at the peer's id")
+	ErrPeerIDUnproven  = errors.New("the peer's key can't be proven to answer at its id")
+)
+
+// SetKey makes the server authenticate peer connections with TLS using a
+// self-signed certificate for the key. Peers are identified by the key pin of
+// their key and plain TCP peer connections are refused. HTTP requests are still
+// served without TLS.
+func (s *Server) SetKey(key *crypto.PrivateKey) error {
+	cert, err := key.Certificate()
+	if err != nil {
+		return err
+	}
+	author, err := key.AuthorID()
+	if err != nil {
+		return err
+	}
+	pin, err := key.KeyPin()
+	if err != nil {
+		return err
+	}
+	s.author = author
+	s.keyPin = pin
+	s.key = key
+	s.tlsConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return &cert, nil
+		},
+		ClientAuth: tls.RequireAnyClientCert,
+		// The certificates are self-signed. The peer's key is checked against
+		// its key pin after the handshake instead.
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS12,
+	}
+	return nil
+}
+
+// dialTLS authenticates an outgoing connection. If pin is set the peer's key
+// must have that key pin.
+func (s *Server) dialTLS(netConn net.Conn, pin string) (*Conn, error) {
+	tlsConn := tls.Client(netConn, s.tlsConfig)
+	author, peerPin, err := handshakeTLS(tlsConn)
+	if err != nil {
+		return nil, err
+	}
+	if len(pin) > 0 && peerPin != pin {
+		return nil, ErrPeerKeyMismatch
+	}
+	conn := s.NewConn(tlsConn)
+	conn.Author = author
+	conn.KeyPin = peerPin
+	return conn, nil
+}
+
+// acceptTLS authenticates an incoming connection whose first bytes have
+// already been read.
+func (s *Server) acceptTLS(conn *Conn, initial []byte) (*Conn, error) {
+	tlsConn := tls.Server(&httpConn{Conn: conn, initial: initial}, s.tlsConfig)
+	author, pin, err := handshakeTLS(tlsConn)
+	if err != nil {
+		return nil, err
+	}
+	authed := s.NewConn(tlsConn)
+	authed.Author = author
+	authed.KeyPin = pin
+	return authed, nil
+}
+
+// handshakeTLS completes the TLS handshake and returns the author ID and the
+// key pin of the peer's key.
+func handshakeTLS(conn *tls.Conn) (author, pin string, err error) {
+	conn.SetDeadline(time.Now().Add(TLSHandshakeTimeout))
+	if err := conn.Handshake(); err != nil {
+		return "", "", err
+	}
+	conn.SetDeadline(time.Time{})
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return "", "", ErrUnauthenticated
+	}
+	return crypto.CertificateKey(certs[0])
+}
@@ -0,0 +1,115 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/degdb/degdb/crypto"
+)
+
+func keyedServer(t *testing.T) (*Server, string) {
+	stunOnce.Do(func() {
+		stunWG.Done()
+	})
+	stunHost = "localhost"
+
+	s, err := NewServer(nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetKey(key); err != nil {
+		t.Fatal(err)
+	}
+	author, err := key.AuthorID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.Listen()
+	s.ListenWait()
+	return s, author
+}
+
+func TestTLSPeers(t *testing.T) {
+	t.Parallel()
+
+	s, _ := keyedServer(t)
+	defer s.Stop()
+	s2, author2 := keyedServer(t)
+	defer s2.Stop()
+	addr := fmt.Sprintf("localhost:%d", s.Port)
+
+	if err := s2.ConnectKey(addr, s2.keyPin); err != ErrPeerKeyMismatch {
+		t.Errorf("ConnectKey() with the wrong key pin = %v; not %v", err, ErrPeerKeyMismatch)
+	}
+	if err := s2.ConnectKey(addr, s.keyPin); err != nil {
+		t.Fatal(err)
+	}
+	var conn *Conn
+	for i := 0; i < retryCount && conn == nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		s.peersLock.RLock()
+		conn = s.Peers[s2.LocalID()]
+		s.peersLock.RUnlock()
+	}
+	if conn == nil {
+		t.Fatal("peers didn't connect")
+	}
+	if conn.Author != author2 || conn.Peer().Author != author2 {
+		t.Errorf("peer authenticated as %q, %q; not %q", conn.Author, conn.Peer().Author, author2)
+	}
+	if conn.KeyPin != s2.keyPin || conn.Peer().KeyPin != s2.keyPin {
+		t.Errorf("peer pinned to %q, %q; not %q", conn.KeyPin, conn.Peer().KeyPin, s2.keyPin)
+	}
+
+	// Plain TCP peer connections are closed.
+	plain, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plain.Close()
+	if _, err := plain.Write([]byte{0, 0, 0, 1, 0}); err != nil {
+		t.Fatal(err)
+	}
+	plain.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = plain.Read(make([]byte, 1))
+	if netErr, ok := err.(net.Error); err == nil || ok && netErr.Timeout() {
+		t.Errorf("unauthenticated connection wasn't closed: %v", err)
+	}
+}
+
+func TestPeerIDSquatting(t *testing.T) {
+	t.Parallel()
+
+	s, _ := keyedServer(t)
+	defer s.Stop()
+	owner, ownerAuthor := keyedServer(t)
+	defer owner.Stop()
+	squatter, _ := keyedServer(t)
+	defer squatter.Stop()
+	// The squatter claims the id of the owner.
+	squatter.Port = owner.Port
+	addr := fmt.Sprintf("localhost:%d", s.Port)
+
+	if err := squatter.Connect(addr); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	s.peersLock.RLock()
+	conn := s.Peers[owner.LocalID()]
+	s.peersLock.RUnlock()
+	if conn != nil {
+		t.Fatalf("peer %s registered with the id of %s", conn.Author, owner.LocalID())
+	}
+
+	// The owner of the id can still connect.
+	if err := owner.Connect(addr); err != nil {
+		t.Fatal(err)
+	}
+	waitPeer(t, s, owner.LocalID(), func(conn *Conn) bool { return conn.Author == ownerAuthor })
+}
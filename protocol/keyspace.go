@@ -1,5 +1,7 @@
 package protocol
 
+import "sort"
+
 // Includes checks if the provided uint64 is inside the keyspace.
 func (k *Keyspace) Includes(hash uint64) bool {
 	if k == nil {
@@ -120,3 +122,83 @@ func (k *Keyspace) Complement() *Keyspace {
 func (k *Keyspace) Clone() *Keyspace {
 	return &Keyspace{Start: k.Start, End: k.End}
 }
+
+// Shrink returns a keyspace with frac of the size centered on the same hash.
+func (k *Keyspace) Shrink(frac float64) *Keyspace {
+	if k == nil {
+		return nil
+	}
+	mag := k.Mag()
+	newMag := uint64(float64(mag) * frac)
+	if frac >= 1 || newMag > mag {
+		return k.Clone()
+	}
+	start := k.Start + (mag-newMag)/2
+	return &Keyspace{Start: start, End: start + newMag}
+}
+
+// Gaps returns the parts of the keyspace covered by fewer than n of the
+// keyspaces, in order of their start.
+func Gaps(keyspaces []*Keyspace, n int) []*Keyspace {
+	// Each keyspace is split into ranges that don't wrap. A range ending at
+	// the end of the keyspace has no end event.
+	deltas := make(map[uint64]int)
+	for _, k := range keyspaces {
+		if k == nil || k.Mag() == 0 {
+			continue
+		}
+		if k.Maxed() {
+			deltas[0]++
+			continue
+		}
+		deltas[k.Start]++
+		if k.End == 0 {
+			continue
+		}
+		if k.End < k.Start {
+			deltas[0]++
+		}
+		deltas[k.End]--
+	}
+	positions := make([]uint64, 0, len(deltas))
+	for pos := range deltas {
+		positions = append(positions, pos)
+	}
+	sort.Sort(uint64Slice(positions))
+
+	var gaps []*Keyspace
+	addGap := func(start, end uint64) {
+		if last := len(gaps) - 1; last >= 0 && gaps[last].End == start {
+			gaps[last].End = end
+			return
+		}
+		gaps = append(gaps, &Keyspace{Start: start, End: end})
+	}
+	var pos uint64
+	count := 0
+	for _, p := range positions {
+		if p > pos && count < n {
+			addGap(pos, p)
+		}
+		count += deltas[p]
+		pos = p
+	}
+	if count < n {
+		addGap(pos, 0)
+	}
+	if len(gaps) == 1 && gaps[0].Start == 0 && gaps[0].End == 0 {
+		return []*Keyspace{{Start: 1, End: 0}}
+	}
+	// Join the gaps at either end of the keyspace.
+	if last := len(gaps) - 1; last > 0 && gaps[0].Start == 0 && gaps[last].End == 0 {
+		gaps[last].End = gaps[0].End
+		gaps = gaps[1:]
+	}
+	return gaps
+}
+
+type uint64Slice []uint64
+
+func (p uint64Slice) Len() int           { return len(p) }
+func (p uint64Slice) Less(i, j int) bool { return p[i] < p[j] }
+func (p uint64Slice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
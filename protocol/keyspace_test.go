@@ -316,3 +316,97 @@ func TestKeyspaceComplement(t *testing.T) {
 		}
 	}
 }
+
+func TestKeyspaceShrink(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		a    *Keyspace
+		frac float64
+		want *Keyspace
+	}{
+		{
+			&Keyspace{0, 100},
+			0.5,
+			&Keyspace{25, 75},
+		},
+		{
+			&Keyspace{math.MaxUint64 - 49, 50},
+			0.5,
+			&Keyspace{math.MaxUint64 - 24, 25},
+		},
+		{
+			&Keyspace{0, 100},
+			1.5,
+			&Keyspace{0, 100},
+		},
+		{
+			nil,
+			0.5,
+			nil,
+		},
+	}
+	for i, td := range testData {
+		out := td.a.Shrink(td.frac)
+		if diff, equal := messagediff.PrettyDiff(td.want, out); !equal {
+			t.Errorf("%d. %+v.Shrink(%f) = %+v not %+v\n%s", i, td.a, td.frac, out, td.want, diff)
+		}
+	}
+}
+
+func TestGaps(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		keyspaces []*Keyspace
+		n         int
+		want      []*Keyspace
+	}{
+		{
+			nil,
+			1,
+			[]*Keyspace{{1, 0}},
+		},
+		{
+			[]*Keyspace{{10, 20}, {30, 40}},
+			1,
+			[]*Keyspace{{20, 30}, {40, 10}},
+		},
+		{
+			[]*Keyspace{{10, 25}, {20, 40}, {0, 5}},
+			1,
+			[]*Keyspace{{5, 10}, {40, 0}},
+		},
+		{
+			[]*Keyspace{{math.MaxUint64 - 9, 10}, {20, 30}},
+			1,
+			[]*Keyspace{{10, 20}, {30, math.MaxUint64 - 9}},
+		},
+		{
+			[]*Keyspace{{0, 100}, {100, 0}},
+			1,
+			nil,
+		},
+		{
+			[]*Keyspace{{5, 4}},
+			1,
+			nil,
+		},
+		{
+			[]*Keyspace{{10, 25}, {20, 40}, {0, 5}},
+			2,
+			[]*Keyspace{{25, 20}},
+		},
+		{
+			[]*Keyspace{{5, 4}, {0, 100}},
+			2,
+			[]*Keyspace{{100, 0}},
+		},
+	}
+	for i, td := range testData {
+		out := Gaps(td.keyspaces, td.n)
+		if diff, equal := messagediff.PrettyDiff(td.want, out); !equal {
+			t.Errorf("%d. Gaps(%+v, %d) = %+v not %+v\n%s", i, td.keyspaces, td.n, out, td.want, diff)
+		}
+	}
+}
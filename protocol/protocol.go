@@ -50,3 +50,34 @@ func (p TripleSlice) Less(i, j int) bool {
 	return abuf.String() < bbuf.String()
 }
 func (p TripleSlice) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+// Get returns the binding of the variable or nil if it is unbound.
+func (r *Row) Get(v string) *Binding {
+	if r == nil {
+		return nil
+	}
+	for _, b := range r.Bindings {
+		if b.Var == v {
+			return b
+		}
+	}
+	return nil
+}
+
+// Clone makes a copy of the row that can be extended without modifying r.
+// The bindings are shared.
+func (r *Row) Clone() *Row {
+	return &Row{Bindings: append([]*Binding(nil), r.Bindings...)}
+}
+
+// Project returns a row with the bindings of vars in that order. Unbound
+// variables are left out.
+func (r *Row) Project(vars []string) *Row {
+	row := &Row{}
+	for _, v := range vars {
+		if b := r.Get(v); b != nil {
+			row.Bindings = append(row.Bindings, b)
+		}
+	}
+	return row
+}
@@ -70,3 +70,29 @@ func TestSortTriples(t *testing.T) {
 		}
 	}
 }
+
+func TestRow(t *testing.T) {
+	t.Parallel()
+
+	row := &Row{Bindings: []*Binding{
+		{Var: "x", Value: "/m/02mjmr"},
+		{Var: "name", Value: "Barack Obama", Lang: "en"},
+	}}
+	if b := row.Get("name"); b != row.Bindings[1] {
+		t.Errorf("row.Get(name) = %#v; not %#v", b, row.Bindings[1])
+	}
+	if b := row.Get("y"); b != nil {
+		t.Errorf("row.Get(y) = %#v; not nil", b)
+	}
+
+	clone := row.Clone()
+	clone.Bindings = append(clone.Bindings, &Binding{Var: "y"})
+	if len(row.Bindings) != 2 {
+		t.Errorf("row.Clone() shares the bindings slice")
+	}
+
+	want := &Row{Bindings: []*Binding{row.Bindings[1], row.Bindings[0]}}
+	if diff, equal := messagediff.PrettyDiff(want, row.Project([]string{"name", "y", "x"})); !equal {
+		t.Errorf("row.Project() = %#v\n%s", row.Project([]string{"name", "y", "x"}), diff)
+	}
+}
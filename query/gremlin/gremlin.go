@@ -0,0 +1,353 @@
+// Package gremlin implements a subset of Gremlin graph traversals:
+//
+//	g.V("/m/02mjmr").out("/people/person/spouse").values("/type/object/name")
+//
+// The supported steps are V(ids...), out(preds...), in(preds...),
+// has(pred[, value]), values(preds...), limit(n) and dedup(). Each hop is run
+// as a BASIC query request with an OR of one triple per traverser so outgoing
+// hops stay rooted and are routed to the nodes owning the subjects. Incoming
+// hops are routed by the object index to the nodes owning the objects.
+package gremlin
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/query"
+)
+
+var (
+	ErrStartStep = errors.New("gremlin: traversals must start with V()")
+	ErrUnknown   = errors.New("gremlin: unknown step")
+)
+
+// Step is a single step of a traversal.
+type Step struct {
+	Name string
+	Args []string
+}
+
+// Traversal is a parsed Gremlin traversal.
+type Traversal struct {
+	Steps []Step
+}
+
+// stepArgs is the minimum and maximum number of arguments of each step. -1 is
+// unbounded.
+var stepArgs = map[string][2]int{
+	"V":      {0, -1},
+	"out":    {0, -1},
+	"in":     {0, -1},
+	"has":    {1, 2},
+	"values": {0, -1},
+	"limit":  {1, 1},
+	"dedup":  {0, 0},
+}
+
+// Parse parses a traversal such as `g.V("id").out("pred").limit(10)`.
+func Parse(q string) (*Traversal, error) {
+	p := &parser{s: strings.TrimSpace(q)}
+	if strings.HasPrefix(p.s, "g.") {
+		p.i = 2
+	}
+	t := &Traversal{}
+	for {
+		step, err := p.step()
+		if err != nil {
+			return nil, err
+		}
+		bounds, ok := stepArgs[step.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s %q", ErrUnknown, step.Name)
+		}
+		if len(step.Args) < bounds[0] || (bounds[1] >= 0 && len(step.Args) > bounds[1]) {
+			return nil, fmt.Errorf("gremlin: wrong number of arguments to %s()", step.Name)
+		}
+		if step.Name == "limit" {
+			if n, err := strconv.Atoi(step.Args[0]); err != nil || n < 0 {
+				return nil, fmt.Errorf("gremlin: invalid limit %q", step.Args[0])
+			}
+		}
+		if (len(t.Steps) == 0) != (step.Name == "V") {
+			return nil, ErrStartStep
+		}
+		t.Steps = append(t.Steps, step)
+
+		p.space()
+		if p.i == len(p.s) {
+			return t, nil
+		}
+		if p.s[p.i] != '.' {
+			return nil, p.unexpected(`"."`)
+		}
+		p.i++
+	}
+}
+
+type parser struct {
+	s string
+	i int
+}
+
+func (p *parser) space() {
+	for p.i < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.i]) >= 0 {
+		p.i++
+	}
+}
+
+func (p *parser) unexpected(want string) error {
+	if p.i >= len(p.s) {
+		return fmt.Errorf("gremlin: expected %s, got end of query", want)
+	}
+	return fmt.Errorf("gremlin: offset %d: expected %s, got %q", p.i, want, p.s[p.i])
+}
+
+// step parses `name(arg, ...)`.
+func (p *parser) step() (Step, error) {
+	p.space()
+	start := p.i
+	for p.i < len(p.s) && (p.s[p.i] == '_' || isAlphaNum(p.s[p.i])) {
+		p.i++
+	}
+	step := Step{Name: p.s[start:p.i]}
+	if len(step.Name) == 0 {
+		return step, p.unexpected("step")
+	}
+	p.space()
+	if p.i >= len(p.s) || p.s[p.i] != '(' {
+		return step, p.unexpected(`"("`)
+	}
+	p.i++
+	for {
+		p.space()
+		if p.i < len(p.s) && p.s[p.i] == ')' && len(step.Args) == 0 {
+			p.i++
+			return step, nil
+		}
+		arg, err := p.arg()
+		if err != nil {
+			return step, err
+		}
+		step.Args = append(step.Args, arg)
+		p.space()
+		if p.i < len(p.s) && p.s[p.i] == ',' {
+			p.i++
+			continue
+		}
+		if p.i < len(p.s) && p.s[p.i] == ')' {
+			p.i++
+			return step, nil
+		}
+		return step, p.unexpected(`"," or ")"`)
+	}
+}
+
+// arg parses a quoted string or a number.
+func (p *parser) arg() (string, error) {
+	if p.i >= len(p.s) {
+		return "", p.unexpected("argument")
+	}
+	start := p.i
+	quote := p.s[p.i]
+	if quote != '"' && quote != '\'' {
+		for p.i < len(p.s) && (isAlphaNum(p.s[p.i]) || p.s[p.i] == '.' || p.s[p.i] == '-') {
+			p.i++
+		}
+		if _, err := strconv.ParseFloat(p.s[start:p.i], 64); err != nil {
+			p.i = start
+			return "", p.unexpected("string or number")
+		}
+		return p.s[start:p.i], nil
+	}
+	for p.i++; p.i < len(p.s) && p.s[p.i] != quote; p.i++ {
+		if p.s[p.i] == '\\' {
+			p.i++
+		}
+	}
+	if p.i >= len(p.s) {
+		return "", fmt.Errorf("gremlin: offset %d: unterminated string", start)
+	}
+	p.i++
+	lit := p.s[start:p.i]
+	if quote == '\'' {
+		lit = `"` + strings.Replace(strings.Replace(lit[1:len(lit)-1], `\'`, `'`, -1), `"`, `\"`, -1) + `"`
+	}
+	arg, err := strconv.Unquote(lit)
+	if err != nil {
+		return "", fmt.Errorf("gremlin: offset %d: invalid string %s", start, lit)
+	}
+	return arg, nil
+}
+
+func isAlphaNum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// traverser is a position in the graph and the triple it was reached by.
+type traverser struct {
+	value  string
+	triple *protocol.Triple
+}
+
+// Result is the outcome of a traversal.
+type Result struct {
+	// Values are the vertices or property values the traversal ended on.
+	Values []string `json:"values"`
+	// Triples are the triples the last hop used to reach the values. Values
+	// given to V() have no triple.
+	Triples []*protocol.Triple `json:"triples"`
+}
+
+// Execute runs the traversal using exec for each hop.
+func (t *Traversal) Execute(exec query.Executor) (*Result, error) {
+	var traversers []traverser
+	steps := t.Steps
+	if len(steps) == 0 || steps[0].Name != "V" {
+		return nil, ErrStartStep
+	}
+	if len(steps[0].Args) > 0 {
+		for _, id := range steps[0].Args {
+			traversers = append(traversers, traverser{value: id})
+		}
+		steps = steps[1:]
+	} else {
+		// V() without ids is only supported when narrowed by has(pred, value).
+		if len(steps) < 2 || steps[1].Name != "has" || len(steps[1].Args) != 2 {
+			return nil, query.ErrUnRooted
+		}
+		triples, err := query.Hop(exec, []*protocol.Triple{{
+			Pred: steps[1].Args[0],
+			Obj:  steps[1].Args[1],
+		}})
+		if err != nil {
+			return nil, err
+		}
+		for _, triple := range triples {
+			traversers = append(traversers, traverser{triple.Subj, triple})
+		}
+		steps = steps[2:]
+	}
+
+	for _, step := range steps {
+		var err error
+		switch step.Name {
+		case "out", "values":
+			traversers, err = walk(exec, traversers, step.Args, false)
+		case "in":
+			traversers, err = walk(exec, traversers, step.Args, true)
+		case "has":
+			traversers, err = has(exec, traversers, step.Args)
+		case "limit":
+			n, _ := strconv.Atoi(step.Args[0])
+			if n < len(traversers) {
+				traversers = traversers[:n]
+			}
+		case "dedup":
+			seen := make(map[string]bool)
+			var deduped []traverser
+			for _, tr := range traversers {
+				if !seen[tr.value] {
+					seen[tr.value] = true
+					deduped = append(deduped, tr)
+				}
+			}
+			traversers = deduped
+		default:
+			err = fmt.Errorf("%s %q", ErrUnknown, step.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &Result{}
+	for _, tr := range traversers {
+		result.Values = append(result.Values, tr.value)
+		if tr.triple != nil {
+			result.Triples = append(result.Triples, tr.triple)
+		}
+	}
+	return result, nil
+}
+
+// walk follows the edges with the given predicates, or all edges if there are
+// none. Outgoing edges end on the object and incoming edges on the subject.
+func walk(exec query.Executor, traversers []traverser, preds []string, in bool) ([]traverser, error) {
+	if len(preds) == 0 {
+		preds = []string{""}
+	}
+	var filters []*protocol.Triple
+	seen := make(map[string]bool)
+	for _, tr := range traversers {
+		if seen[tr.value] {
+			continue
+		}
+		seen[tr.value] = true
+		for _, pred := range preds {
+			filter := &protocol.Triple{Subj: tr.value, Pred: pred}
+			if in {
+				filter = &protocol.Triple{Obj: tr.value, Pred: pred}
+			}
+			filters = append(filters, filter)
+		}
+	}
+	triples, err := query.Hop(exec, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make(map[string][]*protocol.Triple)
+	for _, triple := range triples {
+		from := triple.Subj
+		if in {
+			from = triple.Obj
+		}
+		edges[from] = append(edges[from], triple)
+	}
+	var out []traverser
+	for _, tr := range traversers {
+		for _, triple := range edges[tr.value] {
+			to := triple.Obj
+			if in {
+				to = triple.Subj
+			}
+			out = append(out, traverser{to, triple})
+		}
+	}
+	return out, nil
+}
+
+// has keeps the traversers that have the property, with the value if given.
+func has(exec query.Executor, traversers []traverser, args []string) ([]traverser, error) {
+	var filters []*protocol.Triple
+	seen := make(map[string]bool)
+	for _, tr := range traversers {
+		if seen[tr.value] {
+			continue
+		}
+		seen[tr.value] = true
+		filter := &protocol.Triple{Subj: tr.value, Pred: args[0]}
+		if len(args) > 1 {
+			filter.Obj = args[1]
+		}
+		filters = append(filters, filter)
+	}
+	triples, err := query.Hop(exec, filters)
+	if err != nil {
+		return nil, err
+	}
+	matched := make(map[string]bool)
+	for _, triple := range triples {
+		matched[triple.Subj] = true
+	}
+	var out []traverser
+	for _, tr := range traversers {
+		if matched[tr.value] {
+			out = append(out, tr)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,188 @@
+package gremlin
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/query"
+	"github.com/degdb/degdb/triplestore"
+)
+
+var testTriples = []*protocol.Triple{
+	{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Barack Obama"},
+	{Subj: "/m/02mjmr", Pred: "/type/object/type", Obj: "/people/person"},
+	{Subj: "/m/02mjmr", Pred: "/people/person/spouse", Obj: "/m/025s5v9"},
+	{Subj: "/m/02mjmr", Pred: "/people/person/children", Obj: "/m/04ls81"},
+	{Subj: "/m/02mjmr", Pred: "/people/person/children", Obj: "/m/04ls53"},
+	{Subj: "/m/025s5v9", Pred: "/type/object/name", Obj: "Michelle Obama"},
+	{Subj: "/m/025s5v9", Pred: "/type/object/type", Obj: "/people/person"},
+	{Subj: "/m/025s5v9", Pred: "/people/person/children", Obj: "/m/04ls81"},
+	{Subj: "/m/025s5v9", Pred: "/people/person/children", Obj: "/m/04ls53"},
+	{Subj: "/m/04ls81", Pred: "/type/object/name", Obj: "Malia Obama"},
+	{Subj: "/m/04ls53", Pred: "/type/object/name", Obj: "Sasha Obama"},
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		in   string
+		want *Traversal
+	}{
+		{
+			`g.V("/m/02mjmr").out("/people/person/spouse")`,
+			&Traversal{Steps: []Step{
+				{"V", []string{"/m/02mjmr"}},
+				{"out", []string{"/people/person/spouse"}},
+			}},
+		},
+		{
+			` V().has('/type/object/name', 'Bob\'s "team"') . in( ) .values("a", "b\n").dedup().limit(10)`,
+			&Traversal{Steps: []Step{
+				{"V", nil},
+				{"has", []string{"/type/object/name", `Bob's "team"`}},
+				{"in", nil},
+				{"values", []string{"a", "b\n"}},
+				{"dedup", nil},
+				{"limit", []string{"10"}},
+			}},
+		},
+	}
+	for i, td := range testData {
+		out, err := Parse(td.in)
+		if err != nil {
+			t.Errorf("%d. Parse(%q) error %s", i, td.in, err)
+			continue
+		}
+		if diff, eq := messagediff.PrettyDiff(td.want, out); !eq {
+			t.Errorf("%d. Parse(%q) = %#v\ndiff %s", i, td.in, out, diff)
+		}
+	}
+
+	errors := []string{
+		``,
+		`g.out("a")`,
+		`g.V("a").V("b")`,
+		`g.V("a").both()`,
+		`g.V("a").has()`,
+		`g.V("a").limit("ten")`,
+		`g.V("a").limit(-1)`,
+		`g.V("a"`,
+		`g.V("a)`,
+		`g.V(a)`,
+		`g.V("a").`,
+		`g.V("a") out()`,
+	}
+	for i, td := range errors {
+		if out, err := Parse(td); err == nil {
+			t.Errorf("%d. Parse(%q) = %#v; expected error", i, td, out)
+		}
+	}
+}
+
+// testExecutor returns an Executor backed by a local triple store and a
+// pointer to the number of requests it has run.
+func testExecutor(t *testing.T) (query.Executor, *int) {
+	file, err := ioutil.TempFile(os.TempDir(), "gremlin.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := triplestore.NewTripleStore(file.Name(), log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.Write(testTriples)
+	var requests int
+	return func(q *protocol.QueryRequest) ([]*protocol.Triple, error) {
+		requests++
+		return ts.QueryArrayOp(q.Steps[0], int(q.Limit))
+	}, &requests
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	exec, requests := testExecutor(t)
+
+	testData := []struct {
+		query    string
+		want     []string
+		requests int
+	}{
+		{`g.V("/m/02mjmr")`, []string{"/m/02mjmr"}, 0},
+		{
+			`g.V("/m/02mjmr").out("/people/person/spouse").values("/type/object/name")`,
+			[]string{"Michelle Obama"},
+			2,
+		},
+		{
+			`g.V("/m/02mjmr", "/m/025s5v9").out("/people/person/children").values("/type/object/name")`,
+			[]string{"Malia Obama", "Malia Obama", "Sasha Obama", "Sasha Obama"},
+			2,
+		},
+		{
+			`g.V("/m/02mjmr", "/m/025s5v9").out("/people/person/children").dedup().values("/type/object/name")`,
+			[]string{"Malia Obama", "Sasha Obama"},
+			2,
+		},
+		{
+			`g.V("/m/02mjmr", "/m/025s5v9").limit(1).out("/people/person/spouse")`,
+			[]string{"/m/025s5v9"},
+			1,
+		},
+		{
+			`g.V("/m/04ls81").in("/people/person/children").has("/type/object/name", "Michelle Obama")`,
+			[]string{"/m/025s5v9"},
+			2,
+		},
+		{
+			`g.V().has("/type/object/type", "/people/person").out("/people/person/spouse")`,
+			[]string{"/m/025s5v9"},
+			2,
+		},
+		{
+			`g.V("/m/04ls53").has("/people/person/spouse").values()`,
+			nil,
+			1,
+		},
+		{
+			`g.V("/m/nobody").out().out().values()`,
+			nil,
+			1,
+		},
+	}
+	for i, td := range testData {
+		traversal, err := Parse(td.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*requests = 0
+		result, err := traversal.Execute(exec)
+		if err != nil {
+			t.Errorf("%d. Execute(%q) error %s", i, td.query, err)
+			continue
+		}
+		if *requests != td.requests {
+			t.Errorf("%d. Execute(%q) made %d requests; not %d", i, td.query, *requests, td.requests)
+		}
+		// The order of edges depends on the store.
+		sort.Strings(result.Values)
+		if diff, eq := messagediff.PrettyDiff(td.want, result.Values); !eq {
+			t.Errorf("%d. Execute(%q) = %#v\ndiff %s", i, td.query, result.Values, diff)
+		}
+		if len(result.Triples) > 0 && len(result.Triples) != len(result.Values) {
+			t.Errorf("%d. Execute(%q) returned %d triples for %d values", i, td.query, len(result.Triples), len(result.Values))
+		}
+	}
+
+	traversal, _ := Parse(`g.V().out()`)
+	if _, err := traversal.Execute(exec); err != query.ErrUnRooted {
+		t.Errorf("Execute(g.V().out()) = %v; not %v", err, query.ErrUnRooted)
+	}
+}
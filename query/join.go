@@ -0,0 +1,292 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// IsVar returns whether a field of a query triple is a variable such as "?x".
+func IsVar(s string) bool {
+	return len(s) > 1 && s[0] == '?'
+}
+
+// HasVars returns whether any triple of the steps uses a variable.
+func HasVars(steps []*protocol.ArrayOp) bool {
+	for _, step := range steps {
+		for _, triple := range step.Triples {
+			if IsVar(triple.Subj) || IsVar(triple.Pred) || IsVar(triple.Obj) {
+				return true
+			}
+		}
+		if HasVars(step.Arguments) {
+			return true
+		}
+	}
+	return false
+}
+
+// Vars returns the variables used by the steps in order of appearance.
+func Vars(steps []*protocol.ArrayOp) []string {
+	var vars []string
+	seen := make(map[string]bool)
+	var walk func(steps []*protocol.ArrayOp)
+	walk = func(steps []*protocol.ArrayOp) {
+		for _, step := range steps {
+			for _, triple := range step.Triples {
+				for _, field := range []string{triple.Subj, triple.Pred, triple.Obj} {
+					if IsVar(field) && !seen[field] {
+						seen[field] = true
+						vars = append(vars, field[1:])
+					}
+				}
+			}
+			walk(step.Arguments)
+		}
+	}
+	walk(steps)
+	return vars
+}
+
+// Match is a row produced by Join.
+type Match struct {
+	Row *protocol.Row
+	// Parent is the index of the row that was extended.
+	Parent int
+	// Triple is the triple that matched the pattern.
+	Triple *protocol.Triple
+}
+
+// substitute returns the value of the field in the row. Unbound variables are
+// empty so they match anything.
+func substitute(field string, row *protocol.Row) *protocol.Binding {
+	if !IsVar(field) {
+		return &protocol.Binding{Value: field}
+	}
+	if b := row.Get(field[1:]); b != nil {
+		return b
+	}
+	return &protocol.Binding{}
+}
+
+// mask is the set of fields a filter triple sets.
+type mask uint8
+
+const allFields mask = 1<<4 - 1
+
+func tripleMask(t *protocol.Triple) mask {
+	var m mask
+	for i, field := range []string{t.Subj, t.Pred, t.Obj, t.Lang} {
+		if len(field) > 0 {
+			m |= 1 << uint(i)
+		}
+	}
+	return m
+}
+
+// maskKey returns a key of the fields of t selected by m.
+func maskKey(t *protocol.Triple, m mask) string {
+	fields := []string{t.Subj, t.Pred, t.Obj, t.Lang}
+	for i := range fields {
+		if m&(1<<uint(i)) == 0 {
+			fields[i] = ""
+		}
+	}
+	return strings.Join(fields, "\x00")
+}
+
+// request is a single filter triple and the rows it was built from.
+type request struct {
+	triple *protocol.Triple
+	rows   []int
+}
+
+// Join extends each row with the matches of the pattern, a triple whose
+// fields may be variables. Variables bound by a row are substituted so the
+// pattern stays rooted when its subject is known. Each distinct substitution is
+// one triple of an OR step and at most MaxStepTriples are sent per request. A
+// limit greater than zero is passed on to the requests.
+func Join(exec Executor, rows []*protocol.Row, pattern *protocol.Triple, limit int) ([]Match, error) {
+	var requests []*request
+	index := make(map[string]*request)
+	for i, row := range rows {
+		obj := substitute(pattern.Obj, row)
+		t := &protocol.Triple{
+			Subj: substitute(pattern.Subj, row).Value,
+			Pred: substitute(pattern.Pred, row).Value,
+			Obj:  obj.Value,
+			Lang: obj.Lang,
+		}
+		if !IsVar(pattern.Obj) {
+			t.Lang = pattern.Lang
+		}
+		key := maskKey(t, allFields)
+		req, ok := index[key]
+		if !ok {
+			req = &request{triple: t}
+			index[key] = req
+			requests = append(requests, req)
+		}
+		req.rows = append(req.rows, i)
+	}
+
+	var out []Match
+	for len(requests) > 0 {
+		n := len(requests)
+		if n > MaxStepTriples {
+			n = MaxStepTriples
+		}
+		batch := requests[:n]
+		requests = requests[n:]
+
+		step := &protocol.ArrayOp{Mode: protocol.OR}
+		masks := make(map[mask]map[string]*request)
+		for _, req := range batch {
+			step.Triples = append(step.Triples, req.triple)
+			m := tripleMask(req.triple)
+			if masks[m] == nil {
+				masks[m] = make(map[string]*request)
+			}
+			masks[m][maskKey(req.triple, m)] = req
+		}
+		triples, err := exec(&protocol.QueryRequest{
+			Type:  protocol.BASIC,
+			Steps: []*protocol.ArrayOp{step},
+			Limit: int32(limit),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		seen := make(map[string]bool)
+		for _, triple := range triples {
+			key := maskKey(triple, allFields)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			for m, reqs := range masks {
+				req, ok := reqs[maskKey(triple, m)]
+				if !ok {
+					continue
+				}
+				for _, i := range req.rows {
+					if row, ok := bind(pattern, rows[i], triple); ok {
+						out = append(out, Match{row, i, triple})
+					}
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+// bind extends the row with the variables of the pattern. It returns false if
+// the triple conflicts with the values already bound.
+func bind(pattern *protocol.Triple, row *protocol.Row, triple *protocol.Triple) (*protocol.Row, bool) {
+	row = row.Clone()
+	values := []*protocol.Binding{
+		{Value: triple.Subj},
+		{Value: triple.Pred},
+		{Value: triple.Obj, Lang: triple.Lang},
+	}
+	for i, field := range []string{pattern.Subj, pattern.Pred, pattern.Obj} {
+		if !IsVar(field) {
+			continue
+		}
+		value := values[i]
+		if bound := row.Get(field[1:]); bound != nil {
+			if bound.Value != value.Value || bound.Lang != value.Lang {
+				return nil, false
+			}
+			continue
+		}
+		row.Bindings = append(row.Bindings, &protocol.Binding{
+			Var:   field[1:],
+			Value: value.Value,
+			Lang:  value.Lang,
+		})
+	}
+	return row, true
+}
+
+// NextPattern picks the pattern to join next. Patterns with a known subject
+// are preferred since they can be routed to the nodes that own it, then those
+// with the most known fields.
+func NextPattern(patterns []*protocol.Triple, row *protocol.Row) int {
+	best, bestScore := 0, -1
+	for i, p := range patterns {
+		score := 0
+		for j, field := range []string{p.Subj, p.Pred, p.Obj} {
+			if len(field) == 0 || (IsVar(field) && row.Get(field[1:]) == nil) {
+				continue
+			}
+			if j == 0 {
+				score += 4
+			} else {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// JoinSteps joins all the triple patterns of the steps and returns the rows
+// projected to vars, or all variables if vars is empty, along with the triples
+// that produced them. Every pattern has to match regardless of the step mode.
+func JoinSteps(exec Executor, steps []*protocol.ArrayOp, vars []string, limit int) ([]*protocol.Row, []*protocol.Triple, error) {
+	var patterns []*protocol.Triple
+	var collect func(steps []*protocol.ArrayOp)
+	collect = func(steps []*protocol.ArrayOp) {
+		for _, step := range steps {
+			patterns = append(patterns, step.Triples...)
+			collect(step.Arguments)
+		}
+	}
+	collect(steps)
+	if len(vars) == 0 {
+		vars = Vars(steps)
+	}
+
+	rows := []*protocol.Row{{}}
+	// matched holds the triples that produced each row.
+	matched := [][]*protocol.Triple{nil}
+	joinLimit := 0
+	if len(patterns) == 1 {
+		joinLimit = limit
+	}
+	for len(patterns) > 0 && len(rows) > 0 {
+		i := NextPattern(patterns, rows[0])
+		matches, err := Join(exec, rows, patterns[i], joinLimit)
+		if err != nil {
+			return nil, nil, err
+		}
+		patterns = append(patterns[:i], patterns[i+1:]...)
+		var nextRows []*protocol.Row
+		var nextMatched [][]*protocol.Triple
+		for _, m := range matches {
+			nextRows = append(nextRows, m.Row)
+			nextMatched = append(nextMatched, append(append([]*protocol.Triple(nil), matched[m.Parent]...), m.Triple))
+		}
+		rows, matched = nextRows, nextMatched
+	}
+
+	if limit > 0 && len(rows) > limit {
+		rows, matched = rows[:limit], matched[:limit]
+	}
+	var triples []*protocol.Triple
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		rows[i] = row.Project(vars)
+		for _, triple := range matched[i] {
+			if key := maskKey(triple, allFields); !seen[key] {
+				seen[key] = true
+				triples = append(triples, triple)
+			}
+		}
+	}
+	return rows, triples, nil
+}
@@ -0,0 +1,148 @@
+package query
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/triplestore"
+)
+
+var testTriples = []*protocol.Triple{
+	{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Barack Obama"},
+	{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Barack Hussein Obama", Lang: "fr"},
+	{Subj: "/m/02mjmr", Pred: "/people/person/spouse", Obj: "/m/025s5v9"},
+	{Subj: "/m/025s5v9", Pred: "/type/object/name", Obj: "Michelle Obama"},
+	{Subj: "/m/025s5v9", Pred: "/people/person/spouse", Obj: "/m/02mjmr"},
+	{Subj: "/m/0hume", Pred: "/type/object/name", Obj: "Hume"},
+	{Subj: "/m/0hume", Pred: "/sports/team/mascot", Obj: "/m/0hume"},
+}
+
+func testExecutor(t *testing.T) (Executor, *int) {
+	file, err := ioutil.TempFile(os.TempDir(), "join.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := triplestore.NewTripleStore(file.Name(), log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.Write(testTriples)
+	var requests int
+	return func(q *protocol.QueryRequest) ([]*protocol.Triple, error) {
+		requests++
+		return ts.QueryArrayOp(q.Steps[0], int(q.Limit))
+	}, &requests
+}
+
+func TestVars(t *testing.T) {
+	t.Parallel()
+
+	steps := []*protocol.ArrayOp{
+		{Triples: []*protocol.Triple{{Subj: "?x", Pred: "/type/object/name", Obj: "?name"}}},
+		{Arguments: []*protocol.ArrayOp{{Triples: []*protocol.Triple{{Subj: "?y", Pred: "?p", Obj: "?x"}}}}},
+	}
+	if !HasVars(steps) {
+		t.Errorf("HasVars(%#v) = false", steps)
+	}
+	want := []string{"x", "name", "y", "p"}
+	if diff, eq := messagediff.PrettyDiff(want, Vars(steps)); !eq {
+		t.Errorf("Vars(%#v) = %#v\n%s", steps, Vars(steps), diff)
+	}
+	if steps := []*protocol.ArrayOp{{Triples: []*protocol.Triple{{Subj: "?", Obj: "what?"}}}}; HasVars(steps) {
+		t.Errorf("HasVars(%#v) = true", steps)
+	}
+}
+
+func row(bindings ...string) *protocol.Row {
+	r := &protocol.Row{}
+	for i := 0; i < len(bindings); i += 2 {
+		r.Bindings = append(r.Bindings, &protocol.Binding{Var: bindings[i], Value: bindings[i+1]})
+	}
+	return r
+}
+
+func TestJoinSteps(t *testing.T) {
+	t.Parallel()
+
+	exec, requests := testExecutor(t)
+
+	testData := []struct {
+		steps    []*protocol.ArrayOp
+		vars     []string
+		limit    int
+		want     []*protocol.Row
+		requests int
+	}{
+		// Join on subject and object across steps.
+		{
+			[]*protocol.ArrayOp{
+				{Triples: []*protocol.Triple{{Subj: "/m/02mjmr", Pred: "/people/person/spouse", Obj: "?spouse"}}},
+				{Triples: []*protocol.Triple{{Subj: "?spouse", Pred: "/type/object/name", Obj: "?name"}}},
+			},
+			nil, 0,
+			[]*protocol.Row{row("spouse", "/m/025s5v9", "name", "Michelle Obama")},
+			2,
+		},
+		// Join on predicate, projected.
+		{
+			[]*protocol.ArrayOp{{Triples: []*protocol.Triple{
+				{Subj: "/m/0hume", Pred: "?p", Obj: "Hume"},
+				{Subj: "/m/02mjmr", Pred: "?p", Obj: "?name"},
+			}}},
+			[]string{"name"}, 0,
+			[]*protocol.Row{
+				{Bindings: []*protocol.Binding{{Var: "name", Value: "Barack Hussein Obama", Lang: "fr"}}},
+				row("name", "Barack Obama"),
+			},
+			2,
+		},
+		// The same variable twice in one pattern.
+		{
+			[]*protocol.ArrayOp{{Triples: []*protocol.Triple{{Subj: "?x", Pred: "?p", Obj: "?x"}}}},
+			[]string{"x"}, 0,
+			[]*protocol.Row{row("x", "/m/0hume")},
+			1,
+		},
+		// Symmetric spouses, unrooted first then rooted.
+		{
+			[]*protocol.ArrayOp{{Triples: []*protocol.Triple{
+				{Subj: "?a", Pred: "/people/person/spouse", Obj: "?b"},
+				{Subj: "?b", Pred: "/people/person/spouse", Obj: "?a"},
+			}}},
+			nil, 1,
+			[]*protocol.Row{row("a", "/m/02mjmr", "b", "/m/025s5v9")},
+			2,
+		},
+		{
+			[]*protocol.ArrayOp{{Triples: []*protocol.Triple{
+				{Subj: "?x", Pred: "/type/object/name", Obj: "Nobody"},
+				{Subj: "?x", Pred: "?p", Obj: "?o"},
+			}}},
+			nil, 0,
+			nil,
+			1,
+		},
+	}
+	for i, td := range testData {
+		*requests = 0
+		rows, triples, err := JoinSteps(exec, td.steps, td.vars, td.limit)
+		if err != nil {
+			t.Errorf("%d. JoinSteps error %s", i, err)
+			continue
+		}
+		if *requests != td.requests {
+			t.Errorf("%d. JoinSteps made %d requests; not %d", i, *requests, td.requests)
+		}
+		if diff, eq := messagediff.PrettyDiff(td.want, rows); !eq {
+			t.Errorf("%d. JoinSteps(%#v) = %#v\n%s", i, td.steps, rows, diff)
+		}
+		if len(rows) > 0 && len(triples) == 0 {
+			t.Errorf("%d. JoinSteps returned no triples", i)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+// Package mql implements Metaweb Query Language style read queries. A query is
+// a JSON template where null, [] and {} placeholders are filled in with the
+// values from the graph and other values constrain the matches:
+//
+//	[{"type": "/people/person", "name": null, "spouse": {"name": null}}]
+//
+// "id" is the subject, "name" and "type" are the /type/object properties and
+// keys starting with "/" are full predicates. Other keys are relative to the
+// object's "type". Lists of objects support the "limit" and "optional"
+// directives.
+package mql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/query"
+)
+
+var (
+	ErrNotUnique = errors.New("mql: unique query may have at most one result")
+	ErrTemplate  = errors.New("mql: query must be an object or a list of one object")
+)
+
+// kind is the type of a template value.
+type kind int
+
+const (
+	constant kind = iota
+	fillOne
+	fillAll
+	object
+	objects
+)
+
+type property struct {
+	key   string
+	pred  string
+	kind  kind
+	value string
+	child *node
+}
+
+// node is a template object.
+type node struct {
+	id       string
+	fillID   bool
+	props    []*property
+	limit    int
+	optional bool
+}
+
+// constrained returns whether the node restricts which objects match.
+func (n *node) constrained() bool {
+	if len(n.id) > 0 {
+		return true
+	}
+	for _, prop := range n.props {
+		if prop.kind == constant || ((prop.kind == object || prop.kind == objects) && !prop.child.optional && prop.child.constrained()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Query is a parsed MQL query.
+type Query struct {
+	root   *node
+	unique bool
+}
+
+// Parse parses an MQL query.
+func Parse(template string) (*Query, error) {
+	dec := json.NewDecoder(strings.NewReader(template))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	q := &Query{}
+	obj, ok := v.(map[string]interface{})
+	if ok {
+		q.unique = true
+	} else if list, _ := v.([]interface{}); len(list) == 1 {
+		obj, ok = list[0].(map[string]interface{})
+	}
+	if !ok {
+		return nil, ErrTemplate
+	}
+	root, err := parseNode(obj)
+	if err != nil {
+		return nil, err
+	}
+	q.root = root
+	return q, nil
+}
+
+func parseNode(template map[string]interface{}) (*node, error) {
+	// An empty object is filled with its id.
+	n := &node{limit: -1, fillID: len(template) == 0}
+	typ, _ := template["type"].(string)
+	// Iterate in a fixed order so the requests are deterministic.
+	var keys []string
+	for key := range template {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := template[key]
+		switch key {
+		case "id":
+			switch v := value.(type) {
+			case nil:
+				n.fillID = true
+			case string:
+				n.id = v
+			default:
+				return nil, fmt.Errorf("mql: id must be a string or null")
+			}
+			continue
+		case "limit":
+			limit, err := strconv.Atoi(fmt.Sprint(value))
+			if _, ok := value.(json.Number); !ok || err != nil || limit < 0 {
+				return nil, fmt.Errorf("mql: invalid limit %v", value)
+			}
+			n.limit = limit
+			continue
+		case "optional":
+			optional, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("mql: optional must be a boolean")
+			}
+			n.optional = optional
+			continue
+		}
+
+		prop := &property{key: key, pred: resolve(key, typ)}
+		if len(prop.pred) == 0 {
+			return nil, fmt.Errorf("mql: can't resolve property %q without a type", key)
+		}
+		switch v := value.(type) {
+		case nil:
+			prop.kind = fillOne
+		case string, json.Number, bool:
+			prop.kind = constant
+			prop.value = fmt.Sprint(v)
+		case map[string]interface{}:
+			child, err := parseNode(v)
+			if err != nil {
+				return nil, err
+			}
+			prop.kind = object
+			prop.child = child
+		case []interface{}:
+			prop.kind = fillAll
+			if len(v) == 0 {
+				break
+			}
+			obj, ok := v[0].(map[string]interface{})
+			if len(v) > 1 || !ok {
+				return nil, fmt.Errorf("mql: list %q must be empty or hold one object", key)
+			}
+			child, err := parseNode(obj)
+			if err != nil {
+				return nil, err
+			}
+			prop.kind = objects
+			prop.child = child
+		}
+		n.props = append(n.props, prop)
+	}
+	return n, nil
+}
+
+// resolve returns the predicate for a template key.
+func resolve(key, typ string) string {
+	switch {
+	case strings.HasPrefix(key, "/"):
+		return key
+	case key == "name" || key == "type":
+		return "/type/object/" + key
+	case len(typ) > 0:
+		return typ + "/" + key
+	}
+	return ""
+}
+
+// Result is the filled in template and the triples used to fill it.
+type Result struct {
+	Result  interface{}        `json:"result"`
+	Triples []*protocol.Triple `json:"-"`
+}
+
+// match is an object that matched a template node.
+type match struct {
+	object  map[string]interface{}
+	triples []*protocol.Triple
+}
+
+// Execute runs the query using exec for the individual steps.
+func (q *Query) Execute(exec query.Executor) (*Result, error) {
+	var candidates []string
+	if len(q.root.id) > 0 {
+		candidates = []string{q.root.id}
+	} else {
+		// Start from the objects with one of the constant properties.
+		var start *property
+		for _, prop := range q.root.props {
+			if prop.kind == constant {
+				start = prop
+				break
+			}
+		}
+		if start == nil {
+			return nil, query.ErrUnRooted
+		}
+		triples, err := query.Hop(exec, []*protocol.Triple{{Pred: start.pred, Obj: start.value}})
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, triple := range triples {
+			if !seen[triple.Subj] {
+				seen[triple.Subj] = true
+				candidates = append(candidates, triple.Subj)
+			}
+		}
+	}
+
+	e := &evaluator{exec: exec}
+	matches, err := e.match(q.root, candidates)
+	if err != nil {
+		return nil, err
+	}
+	var objects []map[string]interface{}
+	result := &Result{}
+	for _, candidate := range limited(q.root, candidates, matches) {
+		m := matches[candidate]
+		objects = append(objects, m.object)
+		result.Triples = append(result.Triples, m.triples...)
+	}
+	if !q.unique {
+		if objects == nil {
+			objects = []map[string]interface{}{}
+		}
+		result.Result = objects
+	} else if len(objects) > 1 {
+		return nil, ErrNotUnique
+	} else if len(objects) == 1 {
+		result.Result = objects[0]
+	}
+	return result, nil
+}
+
+// limited returns the matched candidates in order, up to the node's limit.
+func limited(n *node, candidates []string, matches map[string]*match) []string {
+	var out []string
+	for _, candidate := range candidates {
+		if n.limit >= 0 && len(out) >= n.limit {
+			break
+		}
+		if _, ok := matches[candidate]; ok {
+			out = append(out, candidate)
+		}
+	}
+	return out
+}
+
+type evaluator struct {
+	exec query.Executor
+}
+
+// match returns the candidates that match the node and their filled objects.
+func (e *evaluator) match(n *node, candidates []string) (map[string]*match, error) {
+	if len(n.id) > 0 {
+		var filtered []string
+		for _, candidate := range candidates {
+			if candidate == n.id {
+				filtered = append(filtered, candidate)
+			}
+		}
+		candidates = filtered
+	}
+
+	matches := make(map[string]*match)
+	for _, candidate := range candidates {
+		matches[candidate] = &match{object: make(map[string]interface{})}
+	}
+
+	// Constraints
+	for _, prop := range n.props {
+		if prop.kind != constant || len(matches) == 0 {
+			continue
+		}
+		var filters []*protocol.Triple
+		for _, candidate := range candidates {
+			if _, ok := matches[candidate]; ok {
+				filters = append(filters, &protocol.Triple{Subj: candidate, Pred: prop.pred, Obj: prop.value})
+			}
+		}
+		triples, err := query.Hop(e.exec, filters)
+		if err != nil {
+			return nil, err
+		}
+		found := make(map[string]*protocol.Triple)
+		for _, triple := range triples {
+			found[triple.Subj] = triple
+		}
+		for candidate, m := range matches {
+			triple, ok := found[candidate]
+			if !ok {
+				delete(matches, candidate)
+				continue
+			}
+			m.object[prop.key] = prop.value
+			m.triples = append(m.triples, triple)
+		}
+	}
+
+	// Placeholders
+	var filters []*protocol.Triple
+	preds := make(map[string]bool)
+	for _, prop := range n.props {
+		if prop.kind == constant || preds[prop.pred] {
+			continue
+		}
+		preds[prop.pred] = true
+		for _, candidate := range candidates {
+			if _, ok := matches[candidate]; ok {
+				filters = append(filters, &protocol.Triple{Subj: candidate, Pred: prop.pred})
+			}
+		}
+	}
+	triples, err := query.Hop(e.exec, filters)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[[2]string][]*protocol.Triple)
+	for _, triple := range triples {
+		key := [2]string{triple.Subj, triple.Pred}
+		values[key] = append(values[key], triple)
+	}
+
+	for _, prop := range n.props {
+		var children map[string]*match
+		if prop.kind == object || prop.kind == objects {
+			var objs []string
+			seen := make(map[string]bool)
+			for candidate := range matches {
+				for _, triple := range values[[2]string{candidate, prop.pred}] {
+					if !seen[triple.Obj] {
+						seen[triple.Obj] = true
+						objs = append(objs, triple.Obj)
+					}
+				}
+			}
+			sort.Strings(objs)
+			if children, err = e.match(prop.child, objs); err != nil {
+				return nil, err
+			}
+		}
+
+		for candidate, m := range matches {
+			edges := values[[2]string{candidate, prop.pred}]
+			switch prop.kind {
+			case fillOne:
+				m.object[prop.key] = nil
+				if len(edges) > 0 {
+					m.object[prop.key] = edges[0].Obj
+					m.triples = append(m.triples, edges[0])
+				}
+			case fillAll:
+				list := []interface{}{}
+				for _, triple := range edges {
+					list = append(list, triple.Obj)
+					m.triples = append(m.triples, triple)
+				}
+				m.object[prop.key] = list
+			case object, objects:
+				var objs []string
+				for _, triple := range edges {
+					objs = append(objs, triple.Obj)
+				}
+				var list []interface{}
+				for _, obj := range limited(prop.child, objs, children) {
+					list = append(list, children[obj].object)
+					m.triples = append(m.triples, children[obj].triples...)
+					if prop.kind == object {
+						break
+					}
+				}
+				if len(list) == 0 && !prop.child.optional && prop.child.constrained() {
+					delete(matches, candidate)
+					continue
+				}
+				if prop.kind == object {
+					m.object[prop.key] = nil
+					if len(list) > 0 {
+						m.object[prop.key] = list[0]
+					}
+				} else {
+					if list == nil {
+						list = []interface{}{}
+					}
+					m.object[prop.key] = list
+				}
+			}
+		}
+	}
+
+	if n.fillID || len(n.id) > 0 {
+		for candidate, m := range matches {
+			m.object["id"] = candidate
+		}
+	}
+	return matches, nil
+}
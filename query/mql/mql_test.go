@@ -0,0 +1,156 @@
+package mql
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/query"
+	"github.com/degdb/degdb/triplestore"
+)
+
+var testTriples = []*protocol.Triple{
+	{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Barack Obama"},
+	{Subj: "/m/02mjmr", Pred: "/type/object/type", Obj: "/people/person"},
+	{Subj: "/m/02mjmr", Pred: "/people/person/spouse", Obj: "/m/025s5v9"},
+	{Subj: "/m/02mjmr", Pred: "/people/person/children", Obj: "/m/04ls81"},
+	{Subj: "/m/02mjmr", Pred: "/people/person/children", Obj: "/m/04ls53"},
+	{Subj: "/m/025s5v9", Pred: "/type/object/name", Obj: "Michelle Obama"},
+	{Subj: "/m/025s5v9", Pred: "/type/object/type", Obj: "/people/person"},
+	{Subj: "/m/04ls81", Pred: "/type/object/name", Obj: "Malia Obama"},
+	{Subj: "/m/04ls81", Pred: "/people/person/gender", Obj: "Female"},
+	{Subj: "/m/04ls53", Pred: "/type/object/name", Obj: "Sasha Obama"},
+	{Subj: "/m/0hume", Pred: "/type/object/name", Obj: "Hume"},
+	{Subj: "/m/0hume", Pred: "/type/object/type", Obj: "/organization/team"},
+}
+
+func testExecutor(t *testing.T) query.Executor {
+	file, err := ioutil.TempFile(os.TempDir(), "mql.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := triplestore.NewTripleStore(file.Name(), log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.Write(testTriples)
+	return func(q *protocol.QueryRequest) ([]*protocol.Triple, error) {
+		return ts.QueryArrayOp(q.Steps[0], int(q.Limit))
+	}
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	exec := testExecutor(t)
+
+	testData := []struct {
+		query, want string
+	}{
+		{
+			`{"id": "/m/02mjmr", "name": null, "type": []}`,
+			`{"id": "/m/02mjmr", "name": "Barack Obama", "type": ["/people/person"]}`,
+		},
+		{
+			`[{"type": "/people/person", "name": null, "spouse": {"name": null}}]`,
+			`[
+				{"type": "/people/person", "name": "Barack Obama", "spouse": {"name": "Michelle Obama"}},
+				{"type": "/people/person", "name": "Michelle Obama", "spouse": null}
+			]`,
+		},
+		{
+			`[{"type": "/people/person", "id": null, "spouse": {"name": "Michelle Obama"}}]`,
+			`[{"type": "/people/person", "id": "/m/02mjmr", "spouse": {"name": "Michelle Obama"}}]`,
+		},
+		{
+			`{"id": "/m/02mjmr", "/people/person/children": [{"name": null, "gender": null, "type": "/people/person", "optional": true}]}`,
+			`{"id": "/m/02mjmr", "/people/person/children": []}`,
+		},
+		{
+			`{"name": "Barack Obama", "children": [{"name": null, "/people/person/gender": "Female"}], "type": "/people/person"}`,
+			`{"name": "Barack Obama", "type": "/people/person", "children": [{"name": "Malia Obama", "/people/person/gender": "Female"}]}`,
+		},
+		{
+			`{"name": "Barack Obama", "children": [{}], "type": "/people/person"}`,
+			`{"name": "Barack Obama", "type": "/people/person", "children": [{"id": "/m/04ls53"}, {"id": "/m/04ls81"}]}`,
+		},
+		{
+			`{"name": "Barack Obama", "children": [{"limit": 1, "name": null}], "type": "/people/person"}`,
+			`{"name": "Barack Obama", "type": "/people/person", "children": [{"name": "Sasha Obama"}]}`,
+		},
+		{
+			`[{"type": "/people/person", "name": null, "limit": 1}]`,
+			`[{"type": "/people/person", "name": "Barack Obama"}]`,
+		},
+		{
+			`{"name": "Nobody", "id": null}`,
+			`null`,
+		},
+		{
+			`[{"name": "Nobody", "id": null}]`,
+			`[]`,
+		},
+	}
+	for i, td := range testData {
+		q, err := Parse(td.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := q.Execute(exec)
+		if err != nil {
+			t.Errorf("%d. Execute(%s) error %s", i, td.query, err)
+			continue
+		}
+		// Round trip through JSON to compare against the expected template.
+		out, err := json.Marshal(result.Result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got, want interface{}
+		if err := json.Unmarshal(out, &got); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal([]byte(td.want), &want); err != nil {
+			t.Fatal(err)
+		}
+		if diff, eq := messagediff.PrettyDiff(want, got); !eq {
+			t.Errorf("%d. Execute(%s) = %s\ndiff %s", i, td.query, out, diff)
+		}
+	}
+
+	q, _ := Parse(`{"type": "/people/person", "name": null}`)
+	if _, err := q.Execute(exec); err != ErrNotUnique {
+		t.Errorf("Execute(unique query with two results) = %v; not %v", err, ErrNotUnique)
+	}
+	q, _ = Parse(`[{"name": null}]`)
+	if _, err := q.Execute(exec); err != query.ErrUnRooted {
+		t.Errorf("Execute(unrooted) = %v; not %v", err, query.ErrUnRooted)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	testData := []string{
+		``,
+		`"name"`,
+		`[]`,
+		`[{}, {}]`,
+		`{"id": 5}`,
+		`{"limit": "ten"}`,
+		`{"optional": 1}`,
+		`{"spouse": null}`,
+		`{"name": [null]}`,
+		`{"name": {"limit": -1}}`,
+	}
+	for i, td := range testData {
+		if out, err := Parse(td); err == nil {
+			t.Errorf("%d. Parse(%q) = %#v; expected error", i, td, out)
+		}
+	}
+}
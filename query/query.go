@@ -8,6 +8,10 @@ import (
 	"github.com/spaolacci/murmur3"
 )
 
+// MaxStepTriples is the maximum number of triples Hop sends in a single query
+// request.
+var MaxStepTriples = 1000
+
 var (
 	ErrNotImplemented = errors.New("query protocol type is not implemented")
 	ErrUnRooted       = errors.New("unrooted queries are not implemented")
@@ -21,7 +25,21 @@ func Parse(query string) ([]*protocol.Triple, error) {
 	return filters, nil
 }
 
+// ShardQueryByHash splits a query step by the hashes of the subjects it
+// matches. Steps that don't have a subject on every triple are returned under
+// the hash 0 and need to be sent to the whole keyspace.
 func ShardQueryByHash(step *protocol.ArrayOp) map[uint64]*protocol.ArrayOp {
+	return shardQuery(step, func(t *protocol.Triple) string { return t.Subj })
+}
+
+// ShardQueryByObjectHash splits a query step by the hashes of the objects it
+// matches, for routing to the object index. Steps that don't have an object on
+// every triple are returned under the hash 0.
+func ShardQueryByObjectHash(step *protocol.ArrayOp) map[uint64]*protocol.ArrayOp {
+	return shardQuery(step, func(t *protocol.Triple) string { return t.Obj })
+}
+
+func shardQuery(step *protocol.ArrayOp, field func(*protocol.Triple) string) map[uint64]*protocol.ArrayOp {
 	if step == nil {
 		return nil
 	}
@@ -30,11 +48,11 @@ func ShardQueryByHash(step *protocol.ArrayOp) map[uint64]*protocol.ArrayOp {
 	var bad bool
 	if len(step.Triples) > 0 {
 		for _, triple := range step.Triples {
-			if len(triple.Subj) == 0 {
+			if len(field(triple)) == 0 {
 				bad = true
 				break
 			}
-			hash := murmur3.Sum64([]byte(triple.Subj))
+			hash := murmur3.Sum64([]byte(field(triple)))
 			m[hash] = step
 		}
 	} else {
@@ -45,3 +63,39 @@ func ShardQueryByHash(step *protocol.ArrayOp) map[uint64]*protocol.ArrayOp {
 	}
 	return m
 }
+
+// Executor runs a query request against the graph, usually the server's
+// ExecuteQuery.
+type Executor func(*protocol.QueryRequest) ([]*protocol.Triple, error)
+
+// Hop runs an OR of the filters as BASIC query requests of at most
+// MaxStepTriples triples and returns the distinct matching triples.
+func Hop(exec Executor, filters []*protocol.Triple) ([]*protocol.Triple, error) {
+	var out []*protocol.Triple
+	seen := make(map[string]bool)
+	for len(filters) > 0 {
+		n := len(filters)
+		if n > MaxStepTriples {
+			n = MaxStepTriples
+		}
+		triples, err := exec(&protocol.QueryRequest{
+			Type: protocol.BASIC,
+			Steps: []*protocol.ArrayOp{{
+				Mode:    protocol.OR,
+				Triples: filters[:n],
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		filters = filters[n:]
+		for _, triple := range triples {
+			key := triple.Subj + "\x00" + triple.Pred + "\x00" + triple.Obj
+			if !seen[key] {
+				seen[key] = true
+				out = append(out, triple)
+			}
+		}
+	}
+	return out, nil
+}
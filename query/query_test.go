@@ -90,3 +90,88 @@ func TestShardQueryByHash(t *testing.T) {
 		}
 	}
 }
+
+func TestHop(t *testing.T) {
+	defer func(max int) { MaxStepTriples = max }(MaxStepTriples)
+	MaxStepTriples = 2
+
+	var requests []*protocol.QueryRequest
+	exec := func(q *protocol.QueryRequest) ([]*protocol.Triple, error) {
+		requests = append(requests, q)
+		// Every request returns the same triple which should be deduplicated.
+		return []*protocol.Triple{{Subj: "foo", Pred: "bar", Obj: "baz"}}, nil
+	}
+	filters := []*protocol.Triple{{Subj: "a"}, {Subj: "b"}, {Subj: "c"}}
+	out, err := Hop(exec, filters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*protocol.Triple{{Subj: "foo", Pred: "bar", Obj: "baz"}}
+	if diff, eq := messagediff.PrettyDiff(want, out); !eq {
+		t.Errorf("Hop(%#v) = %#v\ndiff %s", filters, out, diff)
+	}
+	wantRequests := []*protocol.QueryRequest{
+		{Type: protocol.BASIC, Steps: []*protocol.ArrayOp{{Triples: filters[:2]}}},
+		{Type: protocol.BASIC, Steps: []*protocol.ArrayOp{{Triples: filters[2:]}}},
+	}
+	if diff, eq := messagediff.PrettyDiff(wantRequests, requests); !eq {
+		t.Errorf("Hop(%#v) requests = %#v\ndiff %s", filters, requests, diff)
+	}
+}
+
+func TestShardQueryByObjectHash(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		step *protocol.ArrayOp
+		want map[uint64]*protocol.ArrayOp
+	}{
+		{
+			&protocol.ArrayOp{
+				Triples: []*protocol.Triple{
+					{Pred: "a", Obj: "foo"},
+					{Subj: "b", Obj: "bar"},
+					{Obj: "foo"},
+				},
+			},
+			map[uint64]*protocol.ArrayOp{
+				0xe271865701f54561: {
+					Triples: []*protocol.Triple{
+						{Pred: "a", Obj: "foo"},
+						{Subj: "b", Obj: "bar"},
+						{Obj: "foo"},
+					},
+				},
+				0x923658dbfd3ae604: {
+					Triples: []*protocol.Triple{
+						{Pred: "a", Obj: "foo"},
+						{Subj: "b", Obj: "bar"},
+						{Obj: "foo"},
+					},
+				},
+			},
+		},
+		{
+			&protocol.ArrayOp{
+				Triples: []*protocol.Triple{
+					{Obj: "foo"},
+					{Subj: "bar"},
+				},
+			},
+			map[uint64]*protocol.ArrayOp{
+				0: {
+					Triples: []*protocol.Triple{
+						{Obj: "foo"},
+						{Subj: "bar"},
+					},
+				},
+			},
+		},
+	}
+	for i, td := range testData {
+		out := ShardQueryByObjectHash(td.step)
+		if diff, eq := messagediff.PrettyDiff(td.want, out); !eq {
+			t.Errorf("%d. ShardQueryByObjectHash(%#v) = %#v\ndiff %s", i, td.step, out, diff)
+		}
+	}
+}
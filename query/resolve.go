@@ -0,0 +1,139 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// Strategies are the names of the conflict resolution strategies.
+var Strategies = []string{"latest", "trust", "majority"}
+
+// Result is a triple with the confidence a Strategy has in its object out of
+// the competing objects for the subject and predicate.
+type Result struct {
+	*protocol.Triple
+	Confidence float64 `json:"confidence"`
+}
+
+// A Strategy scores the competing values of a subject and predicate. It's
+// given the triples asserting each object, keyed by object.
+type Strategy func(values map[string][]*protocol.Triple) map[string]float64
+
+// Latest gives the object of the most recently created triple all of the
+// confidence.
+func Latest(values map[string][]*protocol.Triple) map[string]float64 {
+	var newest int64
+	for _, triples := range values {
+		for _, triple := range triples {
+			if triple.Created > newest {
+				newest = triple.Created
+			}
+		}
+	}
+	scores := make(map[string]float64)
+	for obj, triples := range values {
+		for _, triple := range triples {
+			if triple.Created == newest {
+				scores[obj] = 1
+			}
+		}
+	}
+	return scores
+}
+
+// Majority scores each object by the number of authors asserting it.
+func Majority(values map[string][]*protocol.Triple) map[string]float64 {
+	return Trust(func(string) float64 { return 1 })(values)
+}
+
+// Trust returns a Strategy that scores each object by the total trust of the
+// authors asserting it.
+func Trust(trust func(author string) float64) Strategy {
+	return func(values map[string][]*protocol.Triple) map[string]float64 {
+		scores := make(map[string]float64)
+		for obj, triples := range values {
+			authors := make(map[string]bool)
+			for _, triple := range triples {
+				if authors[triple.Author] {
+					continue
+				}
+				authors[triple.Author] = true
+				scores[obj] += trust(triple.Author)
+			}
+		}
+		return scores
+	}
+}
+
+// GetStrategy returns the Strategy with the name. The trust function is used
+// by the "trust" strategy.
+func GetStrategy(name string, trust func(author string) float64) (Strategy, error) {
+	switch name {
+	case "latest":
+		return Latest, nil
+	case "trust":
+		return Trust(trust), nil
+	case "majority":
+		return Majority, nil
+	}
+	return nil, fmt.Errorf("unknown resolution strategy %q, expected one of %v", name, Strategies)
+}
+
+// Resolve groups the triples by subject and predicate and returns one result
+// per distinct object, ranked by the strategy's confidence within each group.
+// The newest triple asserting an object represents it.
+func Resolve(triples []*protocol.Triple, strategy Strategy) []*Result {
+	var order []string
+	groups := make(map[string]map[string][]*protocol.Triple)
+	for _, triple := range triples {
+		key := triple.Subj + "\x00" + triple.Pred
+		values, ok := groups[key]
+		if !ok {
+			values = make(map[string][]*protocol.Triple)
+			groups[key] = values
+			order = append(order, key)
+		}
+		values[triple.Obj] = append(values[triple.Obj], triple)
+	}
+
+	var results []*Result
+	for _, key := range order {
+		values := groups[key]
+		scores := strategy(values)
+		total := 0.0
+		for _, score := range scores {
+			total += score
+		}
+		var ranked []*Result
+		for obj, triples := range values {
+			newest := triples[0]
+			for _, triple := range triples[1:] {
+				if triple.Created > newest.Created {
+					newest = triple
+				}
+			}
+			result := &Result{Triple: newest}
+			if total > 0 {
+				result.Confidence = scores[obj] / total
+			}
+			ranked = append(ranked, result)
+		}
+		sort.Sort(resultSlice(ranked))
+		results = append(results, ranked...)
+	}
+	return results
+}
+
+// resultSlice sorts results by descending confidence and then by object.
+type resultSlice []*Result
+
+func (s resultSlice) Len() int      { return len(s) }
+func (s resultSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s resultSlice) Less(i, j int) bool {
+	if s[i].Confidence != s[j].Confidence {
+		return s[i].Confidence > s[j].Confidence
+	}
+	return s[i].Obj < s[j].Obj
+}
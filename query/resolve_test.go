@@ -0,0 +1,70 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/d4l3k/messagediff"
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	triples := []*protocol.Triple{
+		{Subj: "a", Pred: "name", Obj: "Alice", Author: "1", Created: 100},
+		{Subj: "a", Pred: "name", Obj: "Alice", Author: "2", Created: 110},
+		{Subj: "a", Pred: "name", Obj: "Alice", Author: "2", Created: 120},
+		{Subj: "a", Pred: "name", Obj: "Alicia", Author: "3", Created: 200},
+		{Subj: "b", Pred: "name", Obj: "Bob", Author: "1", Created: 100},
+	}
+	trust := func(author string) float64 {
+		if author == "3" {
+			return 2
+		}
+		return 0.5
+	}
+
+	testData := []struct {
+		strategy string
+		want     []*Result
+	}{
+		{
+			"latest",
+			[]*Result{
+				{triples[3], 1},
+				{triples[2], 0},
+				{triples[4], 1},
+			},
+		},
+		{
+			"majority",
+			[]*Result{
+				{triples[2], 2.0 / 3},
+				{triples[3], 1.0 / 3},
+				{triples[4], 1},
+			},
+		},
+		{
+			"trust",
+			[]*Result{
+				{triples[3], 2.0 / 3},
+				{triples[2], 1.0 / 3},
+				{triples[4], 1},
+			},
+		},
+	}
+	for i, td := range testData {
+		strategy, err := GetStrategy(td.strategy, trust)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := Resolve(triples, strategy)
+		if diff, eq := messagediff.PrettyDiff(td.want, out); !eq {
+			t.Errorf("%d. Resolve(%q) = %#v\ndiff %s", i, td.strategy, out, diff)
+		}
+	}
+
+	if _, err := GetStrategy("foo", trust); err == nil {
+		t.Errorf("GetStrategy(%q) should fail", "foo")
+	}
+}
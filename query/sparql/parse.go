@@ -0,0 +1,534 @@
+package sparql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// rdfType is the predicate the "a" keyword expands to.
+const rdfType = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+
+// SyntaxError is returned by Parse for malformed queries.
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("sparql: offset %d: %s", e.Offset, e.Msg)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIRI
+	tokPName
+	tokVar
+	tokBlank
+	tokString
+	tokLang
+	tokNumber
+	tokWord
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+// lex splits a query into tokens.
+func lex(query string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(query) {
+		c := query[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '<':
+			end := strings.IndexByte(query[i:], '>')
+			if end < 0 {
+				return nil, &SyntaxError{i, "unterminated IRI"}
+			}
+			iri := query[i+1 : i+end]
+			if strings.ContainsAny(iri, " \t\n<\"{}|^`") {
+				// Could be a less than comparison, which isn't supported.
+				return nil, &SyntaxError{i, "invalid IRI " + strconv.Quote(iri)}
+			}
+			tokens = append(tokens, token{tokIRI, iri, start})
+			i += end + 1
+			continue
+		case c == '"' || c == '\'':
+			val, n, err := lexString(query[i:])
+			if err != nil {
+				return nil, &SyntaxError{i, err.Error()}
+			}
+			tokens = append(tokens, token{tokString, val, start})
+			i += n
+			continue
+		case c == '@':
+			i++
+			for i < len(query) && (isNameChar(query[i]) || query[i] == '-') {
+				i++
+			}
+			if i == start+1 {
+				return nil, &SyntaxError{start, "empty language tag"}
+			}
+			tokens = append(tokens, token{tokLang, query[start+1 : i], start})
+			continue
+		case c == '?' || c == '$':
+			i++
+			for i < len(query) && isNameChar(query[i]) {
+				i++
+			}
+			if i == start+1 {
+				return nil, &SyntaxError{start, "empty variable name"}
+			}
+			tokens = append(tokens, token{tokVar, query[start+1 : i], start})
+			continue
+		case strings.HasPrefix(query[i:], "_:"):
+			i += 2
+			for i < len(query) && isNameChar(query[i]) {
+				i++
+			}
+			if i == start+2 {
+				return nil, &SyntaxError{start, "empty blank node label"}
+			}
+			tokens = append(tokens, token{tokBlank, query[start:i], start})
+			continue
+		}
+
+		for _, punct := range []string{"^^", "!=", "&&", "||", "{", "}", "(", ")", ".", ";", ",", "*", "=", "!"} {
+			if strings.HasPrefix(query[i:], punct) {
+				// Leading dots of decimals are handled below.
+				if punct == "." && i+1 < len(query) && isDigit(query[i+1]) {
+					break
+				}
+				tokens = append(tokens, token{tokPunct, punct, start})
+				i += len(punct)
+				break
+			}
+		}
+		if i > start {
+			continue
+		}
+
+		if c == '+' || c == '-' {
+			i++
+		}
+		for i < len(query) && (isNameChar(query[i]) || query[i] == '-' || query[i] == '.' || query[i] == ':') {
+			i++
+		}
+		// Names can't end in a dot, it terminates the triple instead.
+		for i > start && query[i-1] == '.' {
+			i--
+		}
+		name := query[start:i]
+		switch {
+		case len(name) == 0 || name == "+" || name == "-":
+			r, _ := utf8.DecodeRuneInString(query[start:])
+			return nil, &SyntaxError{start, fmt.Sprintf("unexpected %q", r)}
+		case strings.ContainsRune(name, ':'):
+			tokens = append(tokens, token{tokPName, name, start})
+		case isDigit(name[0]) || name[0] == '.' || name[0] == '+' || name[0] == '-':
+			if _, err := strconv.ParseFloat(name, 64); err != nil {
+				return nil, &SyntaxError{start, "invalid number " + name}
+			}
+			tokens = append(tokens, token{tokNumber, name, start})
+		default:
+			tokens = append(tokens, token{tokWord, name, start})
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(query)}), nil
+}
+
+// lexString reads a quoted string literal and returns the unescaped value and
+// the number of bytes read.
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+	var buf []byte
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == quote:
+			return string(buf), i + 1, nil
+		case c == '\n' || c == '\r':
+			return "", 0, fmt.Errorf("newline in string")
+		case c != '\\':
+			buf = append(buf, c)
+			continue
+		}
+		i++
+		if i >= len(s) {
+			break
+		}
+		switch s[i] {
+		case 't':
+			buf = append(buf, '\t')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case '"', '\'', '\\':
+			buf = append(buf, s[i])
+		case 'u', 'U':
+			n := 4
+			if s[i] == 'U' {
+				n = 8
+			}
+			if i+n >= len(s) {
+				return "", 0, fmt.Errorf("short unicode escape")
+			}
+			r, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+			if err != nil {
+				return "", 0, fmt.Errorf("invalid unicode escape %q", s[i-1:i+1+n])
+			}
+			buf = append(buf, string(rune(r))...)
+			i += n
+		default:
+			return "", 0, fmt.Errorf("invalid escape %q", s[i-1:i+1])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+type parser struct {
+	tokens   []token
+	i        int
+	prefixes map[string]string
+	vars     []string
+	seen     map[string]bool
+}
+
+// Parse parses a SPARQL SELECT query.
+func Parse(query string) (*Query, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{
+		tokens:   tokens,
+		prefixes: make(map[string]string),
+		seen:     make(map[string]bool),
+	}
+	return p.query()
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// keyword returns whether the next token is the case insensitive keyword.
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokWord && strings.EqualFold(t.val, word)
+}
+
+func (p *parser) punct(punct string) bool {
+	t := p.peek()
+	return t.kind == tokPunct && t.val == punct
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.punct(punct) {
+		return p.unexpected(strconv.Quote(punct))
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) unexpected(want string) error {
+	t := p.peek()
+	got := strconv.Quote(t.val)
+	if t.kind == tokEOF {
+		got = "end of query"
+	}
+	return &SyntaxError{t.pos, fmt.Sprintf("expected %s, got %s", want, got)}
+}
+
+func (p *parser) query() (*Query, error) {
+	for p.keyword("PREFIX") {
+		p.next()
+		name := p.next()
+		if name.kind != tokPName || !strings.HasSuffix(name.val, ":") {
+			p.i--
+			return nil, p.unexpected("prefix name")
+		}
+		iri := p.next()
+		if iri.kind != tokIRI {
+			p.i--
+			return nil, p.unexpected("IRI")
+		}
+		p.prefixes[strings.TrimSuffix(name.val, ":")] = iri.val
+	}
+
+	if !p.keyword("SELECT") {
+		return nil, p.unexpected("SELECT")
+	}
+	p.next()
+	q := &Query{Limit: -1}
+	if p.keyword("DISTINCT") {
+		p.next()
+		q.Distinct = true
+	}
+	if p.punct("*") {
+		p.next()
+	} else {
+		for p.peek().kind == tokVar {
+			q.Vars = append(q.Vars, p.next().val)
+		}
+		if len(q.Vars) == 0 {
+			return nil, p.unexpected("variable or *")
+		}
+	}
+
+	if p.keyword("WHERE") {
+		p.next()
+	}
+	where, err := p.group()
+	if err != nil {
+		return nil, err
+	}
+	q.Where = where
+
+	if p.keyword("LIMIT") {
+		p.next()
+		t := p.next()
+		limit, err := strconv.Atoi(t.val)
+		if t.kind != tokNumber || err != nil || limit < 0 {
+			p.i--
+			return nil, p.unexpected("limit")
+		}
+		q.Limit = limit
+	}
+	if p.peek().kind != tokEOF {
+		return nil, p.unexpected("end of query")
+	}
+	if q.Vars == nil {
+		q.Vars = p.vars
+	}
+	return q, nil
+}
+
+// group parses a group graph pattern: "{ triples FILTER(...) OPTIONAL {...} }".
+func (p *parser) group() (*Group, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	g := &Group{}
+	for {
+		switch {
+		case p.punct("}"):
+			p.next()
+			return g, nil
+		case p.punct("."):
+			p.next()
+		case p.keyword("FILTER"):
+			p.next()
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			expr, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			g.Filters = append(g.Filters, expr)
+		case p.keyword("OPTIONAL"):
+			p.next()
+			optional, err := p.group()
+			if err != nil {
+				return nil, err
+			}
+			g.Optionals = append(g.Optionals, optional)
+		default:
+			if err := p.triples(g); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// triples parses a subject followed by a property list using the ";" and ","
+// shorthands.
+func (p *parser) triples(g *Group) error {
+	subj, err := p.term("subject", false)
+	if err != nil {
+		return err
+	}
+	for {
+		var pred Term
+		if p.keyword("a") {
+			p.next()
+			pred = Term{Value: rdfType, Kind: IRI}
+		} else if pred, err = p.term("predicate", false); err != nil {
+			return err
+		}
+		for {
+			obj, err := p.term("object", true)
+			if err != nil {
+				return err
+			}
+			g.Patterns = append(g.Patterns, Pattern{subj, pred, obj})
+			if !p.punct(",") {
+				break
+			}
+			p.next()
+		}
+		if !p.punct(";") {
+			return nil
+		}
+		p.next()
+		// A trailing ";" is allowed.
+		if p.punct(".") || p.punct("}") {
+			return nil
+		}
+	}
+}
+
+// term parses a variable, IRI or, if literal is set, a literal.
+func (p *parser) term(want string, literal bool) (Term, error) {
+	t := p.next()
+	switch t.kind {
+	case tokVar:
+		if !p.seen[t.val] {
+			p.seen[t.val] = true
+			p.vars = append(p.vars, t.val)
+		}
+		return Term{Var: t.val}, nil
+	case tokBlank:
+		// Blank nodes in queries act as variables that aren't projected.
+		return Term{Var: t.val}, nil
+	case tokIRI:
+		return Term{Value: t.val, Kind: IRI}, nil
+	case tokPName:
+		iri, err := p.expand(t)
+		return Term{Value: iri, Kind: IRI}, err
+	}
+	if literal {
+		switch {
+		case t.kind == tokString:
+			term := Term{Value: t.val, Kind: Literal}
+			if p.peek().kind == tokLang {
+				term.Lang = p.next().val
+			} else if p.punct("^^") {
+				// Datatypes aren't stored.
+				p.next()
+				if dt := p.next(); dt.kind == tokPName {
+					if _, err := p.expand(dt); err != nil {
+						return Term{}, err
+					}
+				} else if dt.kind != tokIRI {
+					p.i--
+					return Term{}, p.unexpected("datatype")
+				}
+			}
+			return term, nil
+		case t.kind == tokNumber:
+			return Term{Value: t.val, Kind: Literal}, nil
+		case t.kind == tokWord && (t.val == "true" || t.val == "false"):
+			return Term{Value: t.val, Kind: Literal}, nil
+		}
+	}
+	p.i--
+	return Term{}, p.unexpected(want)
+}
+
+func (p *parser) expand(t token) (string, error) {
+	i := strings.IndexByte(t.val, ':')
+	prefix, ok := p.prefixes[t.val[:i]]
+	if !ok {
+		return "", &SyntaxError{t.pos, "undefined prefix " + strconv.Quote(t.val[:i])}
+	}
+	return prefix + t.val[i+1:], nil
+}
+
+func (p *parser) or() (*Expr, error) {
+	return p.binary("||", p.and)
+}
+
+func (p *parser) and() (*Expr, error) {
+	return p.binary("&&", p.unary)
+}
+
+func (p *parser) binary(op string, operand func() (*Expr, error)) (*Expr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.punct(op) {
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &Expr{Op: op, Args: []*Expr{left, right}}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (*Expr, error) {
+	if p.punct("!") {
+		p.next()
+		arg, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &Expr{Op: "!", Args: []*Expr{arg}}, nil
+	}
+	if p.punct("(") {
+		p.next()
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(")")
+	}
+	left, err := p.term("filter operand", true)
+	if err != nil {
+		return nil, err
+	}
+	if !p.punct("=") && !p.punct("!=") {
+		return nil, p.unexpected(`"=" or "!="`)
+	}
+	op := p.next().val
+	right, err := p.term("filter operand", true)
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{Op: op, Args: []*Expr{{Term: left}, {Term: right}}}, nil
+}
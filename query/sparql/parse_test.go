@@ -0,0 +1,110 @@
+package sparql
+
+import (
+	"testing"
+
+	"github.com/d4l3k/messagediff"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		in   string
+		want *Query
+	}{
+		{
+			`SELECT ?name WHERE { </m/02mjmr> </type/object/name> ?name }`,
+			&Query{
+				Vars:  []string{"name"},
+				Limit: -1,
+				Where: &Group{Patterns: []Pattern{{
+					Term{Value: "/m/02mjmr", Kind: IRI},
+					Term{Value: "/type/object/name", Kind: IRI},
+					Term{Var: "name"},
+				}}},
+			},
+		},
+		{
+			`PREFIX fb: </type/object/>
+			# Teams and their names.
+			select distinct * {
+				?team a </organization/team> ;
+				      fb:name "Hume"@en, 'Hume' .
+				_:b fb:type $team
+			} LIMIT 10`,
+			&Query{
+				Vars:     []string{"team"},
+				Distinct: true,
+				Limit:    10,
+				Where: &Group{Patterns: []Pattern{
+					{Term{Var: "team"}, Term{Value: rdfType, Kind: IRI}, Term{Value: "/organization/team", Kind: IRI}},
+					{Term{Var: "team"}, Term{Value: "/type/object/name", Kind: IRI}, Term{Value: "Hume", Lang: "en", Kind: Literal}},
+					{Term{Var: "team"}, Term{Value: "/type/object/name", Kind: IRI}, Term{Value: "Hume", Kind: Literal}},
+					{Term{Var: "_:b"}, Term{Value: "/type/object/type", Kind: IRI}, Term{Var: "team"}},
+				}},
+			},
+		},
+		{
+			`SELECT ?s ?o WHERE {
+				?s ?p "4.5e1"^^<http://www.w3.org/2001/XMLSchema#double> .
+				OPTIONAL { ?s </p> ?o . FILTER(?o != 42) }
+				FILTER(!(?s = ?p || ?p = "é\n") && ?s != _:x)
+			}`,
+			&Query{
+				Vars:  []string{"s", "o"},
+				Limit: -1,
+				Where: &Group{
+					Patterns: []Pattern{{Term{Var: "s"}, Term{Var: "p"}, Term{Value: "4.5e1", Kind: Literal}}},
+					Optionals: []*Group{{
+						Patterns: []Pattern{{Term{Var: "s"}, Term{Value: "/p", Kind: IRI}, Term{Var: "o"}}},
+						Filters: []*Expr{{Op: "!=", Args: []*Expr{
+							{Term: Term{Var: "o"}},
+							{Term: Term{Value: "42", Kind: Literal}},
+						}}},
+					}},
+					Filters: []*Expr{{Op: "&&", Args: []*Expr{
+						{Op: "!", Args: []*Expr{{Op: "||", Args: []*Expr{
+							{Op: "=", Args: []*Expr{{Term: Term{Var: "s"}}, {Term: Term{Var: "p"}}}},
+							{Op: "=", Args: []*Expr{{Term: Term{Var: "p"}}, {Term: Term{Value: "é\n", Kind: Literal}}}},
+						}}}},
+						{Op: "!=", Args: []*Expr{{Term: Term{Var: "s"}}, {Term: Term{Var: "_:x"}}}},
+					}}},
+				},
+			},
+		},
+	}
+	for i, td := range testData {
+		out, err := Parse(td.in)
+		if err != nil {
+			t.Errorf("%d. Parse(%q) error %s", i, td.in, err)
+			continue
+		}
+		if diff, eq := messagediff.PrettyDiff(td.want, out); !eq {
+			t.Errorf("%d. Parse(%q) = %#v\ndiff %s", i, td.in, out, diff)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	testData := []string{
+		``,
+		`SELECT WHERE { ?s ?p ?o }`,
+		`SELECT * WHERE { ?s ?p }`,
+		`SELECT * WHERE { ?s ?p ?o `,
+		`SELECT * WHERE { "lit" ?p ?o }`,
+		`SELECT * WHERE { ?s fb:name ?o }`,
+		`SELECT * WHERE { ?s ?p ?o FILTER(?o) }`,
+		`SELECT * WHERE { ?s ?p ?o FILTER(?o < 3) }`,
+		`SELECT * WHERE { ?s ?p "unterminated }`,
+		`SELECT * WHERE { ?s ?p ?o } LIMIT ten`,
+		`SELECT * WHERE { ?s ?p ?o } ORDER BY ?s`,
+	}
+	for i, td := range testData {
+		if out, err := Parse(td); err == nil {
+			t.Errorf("%d. Parse(%q) = %#v; expected error", i, td, out)
+		}
+	}
+}
@@ -0,0 +1,410 @@
+// Package sparql implements a subset of SPARQL SELECT queries: basic graph
+// patterns, FILTER with equality, OPTIONAL, LIMIT and DISTINCT.
+//
+// Queries are evaluated as a series of BASIC query requests, one per triple
+// pattern. Each request is an OR of the pattern with the bindings found so far
+// substituted in, which keeps it rooted whenever the subject is known. Since
+// the graph stores plain strings, IRIs and literals with the same value are
+// equal and datatypes are ignored.
+package sparql
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/query"
+	"github.com/degdb/degdb/rdf"
+)
+
+var errUnbound = errors.New("sparql: unbound variable")
+
+// Kind is the type of an RDF term.
+type Kind int
+
+const (
+	IRI Kind = iota
+	Literal
+	Blank
+)
+
+var kindNames = []string{"uri", "literal", "bnode"}
+
+// Term is a variable or a value in a query, or a value in a result.
+type Term struct {
+	Var   string
+	Value string
+	Lang  string
+	Kind  Kind
+}
+
+// IsVar returns whether the term is a variable.
+func (t Term) IsVar() bool {
+	return len(t.Var) > 0
+}
+
+func (t Term) equal(b Term) bool {
+	return t.Value == b.Value && t.Lang == b.Lang
+}
+
+// MarshalJSON encodes the term as a SPARQL JSON results binding.
+func (t Term) MarshalJSON() ([]byte, error) {
+	binding := map[string]string{
+		"type":  kindNames[t.Kind],
+		"value": t.Value,
+	}
+	if t.Kind == Blank {
+		binding["value"] = strings.TrimPrefix(t.Value, "_:")
+	}
+	if len(t.Lang) > 0 {
+		binding["xml:lang"] = t.Lang
+	}
+	return json.Marshal(binding)
+}
+
+// Pattern is a triple pattern.
+type Pattern struct {
+	Subj, Pred, Obj Term
+}
+
+// Expr is a FILTER expression. Op is one of "||", "&&", "!", "=" or "!=". The
+// arguments of "=" and "!=" are terms.
+type Expr struct {
+	Op   string
+	Args []*Expr
+	Term Term
+}
+
+// Group is a group graph pattern.
+type Group struct {
+	Patterns  []Pattern
+	Filters   []*Expr
+	Optionals []*Group
+}
+
+// Query is a parsed SELECT query.
+type Query struct {
+	Vars     []string
+	Distinct bool
+	Where    *Group
+	// Limit is the maximum number of results or -1 for no limit.
+	Limit int
+}
+
+// Results are the solutions of a query. They encode to the SPARQL JSON results
+// format.
+type Results struct {
+	Vars     []string
+	Bindings []map[string]Term
+}
+
+// MarshalJSON encodes the results in the SPARQL JSON results format.
+func (r *Results) MarshalJSON() ([]byte, error) {
+	bindings := r.Bindings
+	if bindings == nil {
+		bindings = []map[string]Term{}
+	}
+	vars := r.Vars
+	if vars == nil {
+		vars = []string{}
+	}
+	return json.Marshal(map[string]interface{}{
+		"head":    map[string]interface{}{"vars": vars},
+		"results": map[string]interface{}{"bindings": bindings},
+	})
+}
+
+// solution is a set of variable bindings. id tracks which solution it was
+// extended from while evaluating an OPTIONAL group.
+type solution struct {
+	bindings map[string]Term
+	id       int
+}
+
+func (s solution) extend() solution {
+	bindings := make(map[string]Term, len(s.bindings)+3)
+	for k, v := range s.bindings {
+		bindings[k] = v
+	}
+	return solution{bindings, s.id}
+}
+
+type evaluator struct {
+	exec  query.Executor
+	limit int
+}
+
+// Execute evaluates the query using exec to run the individual steps.
+func (q *Query) Execute(exec query.Executor) (*Results, error) {
+	e := &evaluator{exec: exec}
+	// The limit can only be pushed down if every triple is a result.
+	if w := q.Where; q.Limit >= 0 && !q.Distinct && len(w.Patterns) == 1 && len(w.Filters) == 0 && len(w.Optionals) == 0 {
+		e.limit = q.Limit
+	}
+	solutions, err := e.group(q.Where, []solution{{bindings: map[string]Term{}}})
+	if err != nil {
+		return nil, err
+	}
+
+	results := &Results{Vars: q.Vars}
+	seen := make(map[string]bool)
+	for _, sol := range solutions {
+		if q.Limit >= 0 && len(results.Bindings) >= q.Limit {
+			break
+		}
+		row := make(map[string]Term, len(q.Vars))
+		var key []string
+		for _, v := range q.Vars {
+			term, ok := sol.bindings[v]
+			if ok {
+				row[v] = term
+			}
+			key = append(key, term.Value, term.Lang)
+		}
+		if q.Distinct {
+			k := strings.Join(key, "\x00")
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+		}
+		results.Bindings = append(results.Bindings, row)
+	}
+	return results, nil
+}
+
+// group extends the solutions with the matches of the group.
+func (e *evaluator) group(g *Group, solutions []solution) ([]solution, error) {
+	solutions = seedEqualities(g, solutions)
+	patterns := append([]Pattern(nil), g.Patterns...)
+	for len(patterns) > 0 && len(solutions) > 0 {
+		i := nextPattern(patterns, solutions[0])
+		var err error
+		if solutions, err = e.join(patterns[i], solutions); err != nil {
+			return nil, err
+		}
+		patterns = append(patterns[:i], patterns[i+1:]...)
+	}
+	for _, optional := range g.Optionals {
+		var err error
+		if solutions, err = e.optional(optional, solutions); err != nil {
+			return nil, err
+		}
+	}
+	if len(g.Filters) == 0 {
+		return solutions, nil
+	}
+	var filtered []solution
+Solutions:
+	for _, sol := range solutions {
+		for _, filter := range g.Filters {
+			if ok, err := filter.eval(sol.bindings); err != nil || !ok {
+				continue Solutions
+			}
+		}
+		filtered = append(filtered, sol)
+	}
+	return filtered, nil
+}
+
+// optional left joins the solutions with the group.
+func (e *evaluator) optional(g *Group, solutions []solution) ([]solution, error) {
+	ids := make([]int, len(solutions))
+	for i := range solutions {
+		ids[i] = solutions[i].id
+		solutions[i].id = i
+	}
+	extended, err := e.group(g, solutions)
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]bool, len(solutions))
+	var out []solution
+	for _, sol := range extended {
+		matched[sol.id] = true
+		sol.id = ids[sol.id]
+		out = append(out, sol)
+	}
+	for i, sol := range solutions {
+		if !matched[i] {
+			sol.id = ids[i]
+			out = append(out, sol)
+		}
+	}
+	return out, nil
+}
+
+// seedEqualities binds variables that a filter of the group requires to be
+// equal to a constant, so the patterns using them become more specific. Only
+// variables that are bound by a pattern of the group are seeded since the
+// filters are still applied afterwards.
+func seedEqualities(g *Group, solutions []solution) []solution {
+	used := make(map[string]bool)
+	for _, p := range g.Patterns {
+		for _, term := range []Term{p.Subj, p.Pred, p.Obj} {
+			if term.IsVar() {
+				used[term.Var] = true
+			}
+		}
+	}
+	seeds := make(map[string]Term)
+	for _, filter := range g.Filters {
+		filter.equalities(seeds)
+	}
+	for v := range seeds {
+		if !used[v] {
+			delete(seeds, v)
+		}
+	}
+	if len(seeds) == 0 {
+		return solutions
+	}
+	out := make([]solution, len(solutions))
+	for i, sol := range solutions {
+		out[i] = sol.extend()
+		for v, term := range seeds {
+			if _, ok := out[i].bindings[v]; !ok {
+				out[i].bindings[v] = term
+			}
+		}
+	}
+	return out
+}
+
+// nextPattern picks the pattern to evaluate next.
+func nextPattern(patterns []Pattern, sol solution) int {
+	triples := make([]*protocol.Triple, len(patterns))
+	for i, p := range patterns {
+		triples[i] = p.triple()
+	}
+	return query.NextPattern(triples, sol.row())
+}
+
+// field returns the term as a query triple field.
+func (t Term) field() string {
+	if t.IsVar() {
+		return "?" + t.Var
+	}
+	return t.Value
+}
+
+// triple returns the pattern as a query triple with variables.
+func (p Pattern) triple() *protocol.Triple {
+	return &protocol.Triple{
+		Subj: p.Subj.field(),
+		Pred: p.Pred.field(),
+		Obj:  p.Obj.field(),
+		Lang: p.Obj.Lang,
+	}
+}
+
+// row returns the bindings of the solution as a row.
+func (s solution) row() *protocol.Row {
+	row := &protocol.Row{}
+	for v, term := range s.bindings {
+		row.Bindings = append(row.Bindings, &protocol.Binding{Var: v, Value: term.Value, Lang: term.Lang})
+	}
+	return row
+}
+
+// join extends each solution with the matches of the pattern.
+func (e *evaluator) join(p Pattern, solutions []solution) ([]solution, error) {
+	rows := make([]*protocol.Row, len(solutions))
+	for i, sol := range solutions {
+		rows[i] = sol.row()
+	}
+	matches, err := query.Join(e.exec, rows, p.triple(), e.limit)
+	if err != nil {
+		return nil, err
+	}
+	var out []solution
+	for _, m := range matches {
+		// Unlike BASIC queries, a plain literal doesn't match one with a
+		// language.
+		if !p.Obj.IsVar() && p.Obj.Lang != m.Triple.Lang {
+			continue
+		}
+		sol := solutions[m.Parent].extend()
+		values := []Term{resource(m.Triple.Subj), resource(m.Triple.Pred), object(m.Triple)}
+		for i, term := range []Term{p.Subj, p.Pred, p.Obj} {
+			if _, ok := sol.bindings[term.Var]; term.IsVar() && !ok {
+				sol.bindings[term.Var] = values[i]
+			}
+		}
+		out = append(out, sol)
+	}
+	return out, nil
+}
+
+func object(triple *protocol.Triple) Term {
+	obj := Term{Value: triple.Obj, Lang: triple.Lang, Kind: Literal}
+	if rdf.IsBlank(triple.Obj) {
+		obj.Kind = Blank
+	} else if rdf.ObjectIsIRI(triple) {
+		obj.Kind = IRI
+	}
+	return obj
+}
+
+func resource(value string) Term {
+	if rdf.IsBlank(value) {
+		return Term{Value: value, Kind: Blank}
+	}
+	return Term{Value: value, Kind: IRI}
+}
+
+// equalities adds the variables the expression requires to equal a constant.
+func (expr *Expr) equalities(m map[string]Term) {
+	switch expr.Op {
+	case "&&":
+		for _, arg := range expr.Args {
+			arg.equalities(m)
+		}
+	case "=":
+		a, b := expr.Args[0].Term, expr.Args[1].Term
+		if b.IsVar() {
+			a, b = b, a
+		}
+		if a.IsVar() && !b.IsVar() {
+			m[a.Var] = b
+		}
+	}
+}
+
+// eval evaluates the expression in the bindings. Unbound variables are an
+// error, which fails the filter unless the other side of an "||" is true.
+func (expr *Expr) eval(bindings map[string]Term) (bool, error) {
+	switch expr.Op {
+	case "||", "&&":
+		a, errA := expr.Args[0].eval(bindings)
+		b, errB := expr.Args[1].eval(bindings)
+		if expr.Op == "||" && ((a && errA == nil) || (b && errB == nil)) {
+			return true, nil
+		}
+		if expr.Op == "&&" && ((!a && errA == nil) || (!b && errB == nil)) {
+			return false, nil
+		}
+		if errA != nil {
+			return false, errA
+		}
+		if errB != nil {
+			return false, errB
+		}
+		return expr.Op == "&&", nil
+	case "!":
+		v, err := expr.Args[0].eval(bindings)
+		return !v, err
+	}
+	var terms [2]Term
+	for i, arg := range expr.Args {
+		terms[i] = arg.Term
+		if arg.Term.IsVar() {
+			var ok bool
+			if terms[i], ok = bindings[arg.Term.Var]; !ok {
+				return false, errUnbound
+			}
+		}
+	}
+	return terms[0].equal(terms[1]) == (expr.Op == "="), nil
+}
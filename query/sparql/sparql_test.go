@@ -0,0 +1,207 @@
+package sparql
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/protocol"
+	"github.com/degdb/degdb/query"
+	"github.com/degdb/degdb/triplestore"
+)
+
+var testTriples = []*protocol.Triple{
+	{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Barack Obama"},
+	{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Barack H. Obama", Lang: "fr"},
+	{Subj: "/m/02mjmr", Pred: "/type/object/type", Obj: "/people/person"},
+	{Subj: "/m/02mjmr", Pred: "/people/person/spouse", Obj: "/m/025s5v9"},
+	{Subj: "/m/025s5v9", Pred: "/type/object/name", Obj: "Michelle Obama"},
+	{Subj: "/m/025s5v9", Pred: "/type/object/type", Obj: "/people/person"},
+	{Subj: "/m/0hume", Pred: "/type/object/name", Obj: "Hume"},
+	{Subj: "/m/0hume", Pred: "/type/object/type", Obj: "/organization/team"},
+	{Subj: "_:b1", Pred: "/type/object/type", Obj: "/people/person"},
+}
+
+// testExecutor returns an Executor backed by a local triple store and a
+// pointer to the number of requests it has run.
+func testExecutor(t *testing.T) (query.Executor, *int) {
+	file, err := ioutil.TempFile(os.TempDir(), "sparql.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := triplestore.NewTripleStore(file.Name(), log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.Write(testTriples)
+	var requests int
+	return func(q *protocol.QueryRequest) ([]*protocol.Triple, error) {
+		requests++
+		if len(q.Steps) != 1 || q.Type != protocol.BASIC {
+			t.Errorf("unexpected request %+v", q)
+		}
+		return ts.QueryArrayOp(q.Steps[0], int(q.Limit))
+	}, &requests
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	exec, requests := testExecutor(t)
+
+	person := Term{Value: "/people/person", Kind: IRI}
+	barack := Term{Value: "/m/02mjmr", Kind: IRI}
+	michelle := Term{Value: "/m/025s5v9", Kind: IRI}
+	testData := []struct {
+		query    string
+		want     []map[string]Term
+		requests int
+	}{
+		{
+			`SELECT ?name WHERE { </m/02mjmr> </type/object/name> ?name }`,
+			[]map[string]Term{
+				{"name": {Value: "Barack Obama", Kind: Literal}},
+				{"name": {Value: "Barack H. Obama", Lang: "fr", Kind: Literal}},
+			},
+			1,
+		},
+		{
+			`SELECT ?name WHERE {
+				?x </type/object/type> </people/person> ;
+				   </type/object/name> ?name .
+				FILTER(?name != "Barack H. Obama"@fr)
+			}`,
+			[]map[string]Term{
+				{"name": {Value: "Barack Obama", Kind: Literal}},
+				{"name": {Value: "Michelle Obama", Kind: Literal}},
+			},
+			2,
+		},
+		{
+			`SELECT ?name WHERE {
+				?x </people/person/spouse> ?y .
+				?y </type/object/name> ?name
+			}`,
+			[]map[string]Term{
+				{"name": {Value: "Michelle Obama", Kind: Literal}},
+			},
+			2,
+		},
+		{
+			`SELECT ?x ?spouse WHERE {
+				?x </type/object/type> </people/person>
+				OPTIONAL { ?x </people/person/spouse> ?spouse }
+			}`,
+			[]map[string]Term{
+				{"x": michelle},
+				{"x": barack, "spouse": michelle},
+				{"x": {Value: "_:b1", Kind: Blank}},
+			},
+			2,
+		},
+		{
+			`SELECT ?x WHERE { ?x ?p ?type FILTER(?type = </organization/team> || ?type = "Hume") }`,
+			[]map[string]Term{
+				{"x": {Value: "/m/0hume", Kind: IRI}},
+				{"x": {Value: "/m/0hume", Kind: IRI}},
+			},
+			1,
+		},
+		{
+			`SELECT ?x WHERE { ?x ?p ?o FILTER(?o = "Hume" && ?p = </type/object/name>) }`,
+			[]map[string]Term{
+				{"x": {Value: "/m/0hume", Kind: IRI}},
+			},
+			1,
+		},
+		{
+			`SELECT DISTINCT ?type WHERE { ?x </type/object/type> ?type }`,
+			[]map[string]Term{
+				{"type": person},
+				{"type": {Value: "/organization/team", Kind: IRI}},
+			},
+			1,
+		},
+		{
+			`SELECT * WHERE { ?x ?p ?o } LIMIT 3`,
+			nil,
+			1,
+		},
+		{
+			`SELECT ?x WHERE { ?x </type/object/name> "Nobody" . ?x ?p ?o }`,
+			nil,
+			1,
+		},
+	}
+	for i, td := range testData {
+		q, err := Parse(td.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		*requests = 0
+		results, err := q.Execute(exec)
+		if err != nil {
+			t.Errorf("%d. Execute(%q) error %s", i, td.query, err)
+			continue
+		}
+		if *requests != td.requests {
+			t.Errorf("%d. Execute(%q) made %d requests; not %d", i, td.query, *requests, td.requests)
+		}
+		if q.Limit >= 0 {
+			if len(results.Bindings) != q.Limit {
+				t.Errorf("%d. Execute(%q) = %d results; not %d", i, td.query, len(results.Bindings), q.Limit)
+			}
+			continue
+		}
+		sortBindings(results.Bindings, q.Vars)
+		sortBindings(td.want, q.Vars)
+		if diff, eq := messagediff.PrettyDiff(td.want, results.Bindings); !eq {
+			t.Errorf("%d. Execute(%q) = %#v\ndiff %s", i, td.query, results.Bindings, diff)
+		}
+	}
+}
+
+func sortBindings(bindings []map[string]Term, vars []string) {
+	less := func(a, b map[string]Term) bool {
+		for _, v := range vars {
+			if a[v].Value != b[v].Value {
+				return a[v].Value < b[v].Value
+			}
+			if a[v].Lang != b[v].Lang {
+				return a[v].Lang < b[v].Lang
+			}
+		}
+		return false
+	}
+	for i := 1; i < len(bindings); i++ {
+		for j := i; j > 0 && less(bindings[j], bindings[j-1]); j-- {
+			bindings[j], bindings[j-1] = bindings[j-1], bindings[j]
+		}
+	}
+}
+
+func TestResultsJSON(t *testing.T) {
+	t.Parallel()
+
+	results := &Results{
+		Vars: []string{"x", "name"},
+		Bindings: []map[string]Term{
+			{"x": {Value: "/m/02mjmr", Kind: IRI}, "name": {Value: "Barack Obama", Lang: "en", Kind: Literal}},
+			{"x": {Value: "_:b1", Kind: Blank}},
+		},
+	}
+	out, err := json.Marshal(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"head":{"vars":["x","name"]},"results":{"bindings":[` +
+		`{"name":{"type":"literal","value":"Barack Obama","xml:lang":"en"},"x":{"type":"uri","value":"/m/02mjmr"}},` +
+		`{"x":{"type":"bnode","value":"b1"}}]}}`
+	if string(out) != want {
+		t.Errorf("json.Marshal(results) = %s; not %s", out, want)
+	}
+}
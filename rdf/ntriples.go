@@ -0,0 +1,296 @@
+// Package rdf reads and writes triples in standard RDF serializations.
+//
+// IRIs are stored in triples without the surrounding angle brackets and IRIs
+// under BaseIRI are stored relative to it. Blank nodes keep their "_:" prefix
+// and literals are stored as their lexical value with the language tag in
+// Triple.Lang. Literal datatypes aren't stored.
+//
+// Triples don't record whether their object was an IRI or a literal, so the
+// writers guess from the object's text with ObjectIsIRI. A plain literal that
+// looks like a path or an http, https or urn IRI is written back as an IRI and
+// an IRI object with another scheme is written back as a literal.
+package rdf
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+var (
+	ErrUnexpectedEOL = errors.New("unexpected end of line")
+	ErrMissingDot    = errors.New("statement is missing terminating '.'")
+)
+
+// ParseError is returned when a line can't be parsed. The Reader can continue
+// reading after a ParseError.
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// Reader reads triples from an N-Triples or N-Quads stream. The graph label of
+// N-Quads statements is ignored.
+type Reader struct {
+	r    *bufio.Reader
+	base string
+	line int
+}
+
+// NewReader returns a Reader that reads from r. IRIs under BaseIRI are made
+// relative.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r), base: BaseIRI}
+}
+
+// Read returns the next triple in the stream. At the end of the stream it
+// returns io.EOF. Malformed statements return a *ParseError.
+func (r *Reader) Read() (*protocol.Triple, error) {
+	for {
+		line, err := r.r.ReadString('\n')
+		if len(line) == 0 && err != nil {
+			return nil, err
+		}
+		r.line++
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		triple, perr := parseStatement(line, r.base)
+		if perr != nil {
+			return nil, &ParseError{Line: r.line, Err: perr}
+		}
+		return triple, nil
+	}
+}
+
+// parseStatement parses a single N-Triples or N-Quads statement.
+func parseStatement(line, base string) (*protocol.Triple, error) {
+	p := &lineParser{s: line, base: base}
+	triple := &protocol.Triple{}
+	var err error
+	if triple.Subj, err = p.resource(); err != nil {
+		return nil, err
+	}
+	if triple.Pred, err = p.iri(); err != nil {
+		return nil, err
+	}
+	if triple.Obj, triple.Lang, err = p.object(); err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	// Optional graph label for N-Quads.
+	if p.peek() == '<' || p.peek() == '_' {
+		if _, err := p.resource(); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+	}
+	if p.peek() != '.' {
+		return nil, ErrMissingDot
+	}
+	p.pos++
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] != '#' {
+		return nil, fmt.Errorf("unexpected %q after statement", p.s[p.pos:])
+	}
+	return triple, nil
+}
+
+type lineParser struct {
+	s    string
+	base string
+	pos  int
+}
+
+func (p *lineParser) peek() byte {
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *lineParser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// resource parses an IRI or a blank node.
+func (p *lineParser) resource() (string, error) {
+	p.skipSpace()
+	if p.peek() == '_' {
+		return p.blankNode()
+	}
+	return p.iri()
+}
+
+func (p *lineParser) iri() (string, error) {
+	p.skipSpace()
+	if p.peek() != '<' {
+		return "", p.unexpected("IRI")
+	}
+	p.pos++
+	end := strings.IndexByte(p.s[p.pos:], '>')
+	if end < 0 {
+		return "", ErrUnexpectedEOL
+	}
+	raw := p.s[p.pos : p.pos+end]
+	p.pos += end + 1
+	if strings.ContainsAny(raw, " <\"{}|^`") {
+		return "", fmt.Errorf("invalid IRI <%s>", raw)
+	}
+	iri, err := unescape(raw)
+	if err != nil {
+		return "", err
+	}
+	return relativeIRI(p.base, iri), nil
+}
+
+func (p *lineParser) blankNode() (string, error) {
+	if !strings.HasPrefix(p.s[p.pos:], "_:") {
+		return "", p.unexpected("blank node")
+	}
+	start := p.pos
+	p.pos += 2
+	for p.pos < len(p.s) && !strings.ContainsRune(" \t", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	// A trailing '.' terminates the statement rather than the label.
+	if p.pos == len(p.s) && p.s[p.pos-1] == '.' {
+		p.pos--
+	}
+	if p.pos-start <= 2 {
+		return "", fmt.Errorf("empty blank node label")
+	}
+	return p.s[start:p.pos], nil
+}
+
+// object parses an IRI, blank node or literal. For literals the language tag
+// is also returned.
+func (p *lineParser) object() (string, string, error) {
+	p.skipSpace()
+	if p.peek() != '"' {
+		obj, err := p.resource()
+		return obj, "", err
+	}
+	p.pos++
+	start := p.pos
+	for {
+		if p.pos >= len(p.s) {
+			return "", "", ErrUnexpectedEOL
+		}
+		c := p.s[p.pos]
+		if c == '\\' {
+			p.pos += 2
+			continue
+		}
+		if c == '"' {
+			break
+		}
+		p.pos++
+	}
+	value, err := unescape(p.s[start:p.pos])
+	if err != nil {
+		return "", "", err
+	}
+	p.pos++
+
+	var lang string
+	switch {
+	case p.peek() == '@':
+		p.pos++
+		start := p.pos
+		for p.pos < len(p.s) && (isAlphaNum(p.s[p.pos]) || p.s[p.pos] == '-') {
+			p.pos++
+		}
+		lang = p.s[start:p.pos]
+		if len(lang) == 0 {
+			return "", "", fmt.Errorf("empty language tag")
+		}
+	case strings.HasPrefix(p.s[p.pos:], "^^"):
+		p.pos += 2
+		if _, err := p.iri(); err != nil {
+			return "", "", err
+		}
+	}
+	return value, lang, nil
+}
+
+func (p *lineParser) unexpected(want string) error {
+	if p.pos >= len(p.s) {
+		return ErrUnexpectedEOL
+	}
+	return fmt.Errorf("expected %s at %q", want, p.s[p.pos:])
+}
+
+func isAlphaNum(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// unescape decodes the N-Triples string and IRI escape sequences.
+func unescape(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			buf.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", ErrUnexpectedEOL
+		}
+		switch s[i] {
+		case 't':
+			buf.WriteByte('\t')
+		case 'b':
+			buf.WriteByte('\b')
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 'f':
+			buf.WriteByte('\f')
+		case '"', '\'', '\\':
+			buf.WriteByte(s[i])
+		case 'u', 'U':
+			n := 4
+			if s[i] == 'U' {
+				n = 8
+			}
+			if i+n >= len(s) {
+				return "", fmt.Errorf("short unicode escape in %q", s)
+			}
+			code, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+			if err != nil {
+				return "", err
+			}
+			if !utf8.ValidRune(rune(code)) {
+				return "", fmt.Errorf("invalid unicode escape \\%c%s", s[i], s[i+1:i+1+n])
+			}
+			buf.WriteRune(rune(code))
+			i += n
+		default:
+			return "", fmt.Errorf("invalid escape \\%c", s[i])
+		}
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,104 @@
+package rdf
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	in := `# A comment
+<http://rdf.freebase.com/ns/m.02mjmr> <http://rdf.freebase.com/ns/type.object.name> "Barack Obama"@en .
+<http://rdf.freebase.com/ns/m.02mjmr> <http://rdf.freebase.com/ns/type.object.type> <http://rdf.freebase.com/ns/people.person> .
+
+_:b1 <http://example.com/says> "line\nbreak \"quoted\" é\U0001F600" <http://example.com/graph> .
+_:b1 <http://example.com/age> "54"^^<http://www.w3.org/2001/XMLSchema#integer> .
+<http://example.com/a> <http://example.com/knows> _:b2 _:g1 . # trailing comment
+<http://example.com/a> <http://example.com/name> "Ünïcödé"@de-CH.`
+
+	want := []*protocol.Triple{
+		{
+			Subj: "http://rdf.freebase.com/ns/m.02mjmr",
+			Pred: "http://rdf.freebase.com/ns/type.object.name",
+			Obj:  "Barack Obama",
+			Lang: "en",
+		},
+		{
+			Subj: "http://rdf.freebase.com/ns/m.02mjmr",
+			Pred: "http://rdf.freebase.com/ns/type.object.type",
+			Obj:  "http://rdf.freebase.com/ns/people.person",
+		},
+		{
+			Subj: "_:b1",
+			Pred: "http://example.com/says",
+			Obj:  "line\nbreak \"quoted\" é😀",
+		},
+		{
+			Subj: "_:b1",
+			Pred: "http://example.com/age",
+			Obj:  "54",
+		},
+		{
+			Subj: "http://example.com/a",
+			Pred: "http://example.com/knows",
+			Obj:  "_:b2",
+		},
+		{
+			Subj: "http://example.com/a",
+			Pred: "http://example.com/name",
+			Obj:  "Ünïcödé",
+			Lang: "de-CH",
+		},
+	}
+
+	r := NewReader(strings.NewReader(in))
+	var out []*protocol.Triple
+	for {
+		triple, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, triple)
+	}
+	if diff, equal := messagediff.PrettyDiff(want, out); !equal {
+		t.Errorf("Read() = %+v; not %+v\n%s", out, want, diff)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	t.Parallel()
+
+	testData := []string{
+		`<a> <b> "c"`,
+		`<a> <b> "c .`,
+		`"a" <b> <c> .`,
+		`<a> _:b <c> .`,
+		`<a> <b> "c"@ .`,
+		`<a> <b> "\q" .`,
+		`<a> <b> "\u00" .`,
+		`<a b> <b> <c> .`,
+		`<a> <b> <c> . <d>`,
+		`<a> <b`,
+	}
+	for i, td := range testData {
+		r := NewReader(strings.NewReader(td + "\n<a> <b> <c> .\n"))
+		_, err := r.Read()
+		perr, ok := err.(*ParseError)
+		if !ok || perr.Line != 1 {
+			t.Errorf("%d. Read(%#v) = %#v; expected ParseError on line 1", i, td, err)
+		}
+		// The reader should recover on the next line.
+		triple, err := r.Read()
+		if err != nil || triple.Obj != "c" {
+			t.Errorf("%d. Read() after error = %+v, %+v", i, triple, err)
+		}
+	}
+}
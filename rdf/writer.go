@@ -0,0 +1,346 @@
+package rdf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// Format is an RDF serialization format.
+type Format int
+
+const (
+	NTriples Format = iota
+	Turtle
+	JSONLD
+)
+
+var formats = []struct {
+	format      Format
+	contentType string
+	names       []string
+}{
+	{NTriples, "application/n-triples", []string{"ntriples", "nt"}},
+	{Turtle, "text/turtle", []string{"turtle", "ttl"}},
+	{JSONLD, "application/ld+json", []string{"jsonld", "json-ld"}},
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	for _, info := range formats {
+		if info.format == f {
+			return info.contentType
+		}
+	}
+	return ""
+}
+
+// ParseFormat returns the format with the given name or MIME type.
+func ParseFormat(name string) (Format, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, info := range formats {
+		if name == info.contentType {
+			return info.format, true
+		}
+		for _, n := range info.names {
+			if name == n {
+				return info.format, true
+			}
+		}
+	}
+	return NTriples, false
+}
+
+// Negotiate picks the best format for an HTTP Accept header. If none of the
+// accepted types are supported, false is returned.
+func Negotiate(accept string) (Format, bool) {
+	best := NTriples
+	var bestQ float64
+	found := false
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qs, 64); err != nil {
+				continue
+			}
+		}
+		var format Format
+		var ok bool
+		switch mediaType {
+		case "*/*", "application/*":
+			format, ok = NTriples, true
+		default:
+			format, ok = ParseFormat(mediaType)
+		}
+		if ok && q > 0 && (!found || q > bestQ) {
+			best, bestQ, found = format, q, true
+		}
+	}
+	return best, found
+}
+
+// BaseIRI is the base relative IRIs, such as the Freebase style paths used as
+// ids, are written under so the output only has absolute IRIs. Readers make
+// IRIs under it relative again. If it's empty, relative IRIs are written as is.
+var BaseIRI = "http://degdb.org"
+
+// Writer writes triples in an RDF format. Close must be called to finish the
+// document.
+type Writer interface {
+	Write(triple *protocol.Triple) error
+	Close() error
+}
+
+// NewWriter returns a Writer that writes the format to w. Relative IRIs are
+// written under BaseIRI.
+func NewWriter(w io.Writer, format Format) Writer {
+	bw := bufio.NewWriter(w)
+	switch format {
+	case Turtle:
+		return &turtleWriter{w: bw, base: BaseIRI}
+	case JSONLD:
+		return &jsonldWriter{w: bw, base: BaseIRI}
+	default:
+		return &ntriplesWriter{w: bw, base: BaseIRI}
+	}
+}
+
+var (
+	absoluteIRI = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:[^\s<>"{}|^` + "`" + `\\]*$`)
+	pathIRI     = regexp.MustCompile(`^/[^\s<>"{}|^` + "`" + `\\]*$`)
+	// objectIRI matches the absolute IRIs guessed to be IRI objects. Other
+	// schemes are too easily mistaken for text like "note:foo".
+	objectIRI = regexp.MustCompile(`^(?i:https?://|urn:)[^\s<>"{}|^` + "`" + `\\]+$`)
+)
+
+// IsBlank returns whether the value is a blank node label.
+func IsBlank(s string) bool {
+	return strings.HasPrefix(s, "_:") && len(s) > 2 && !strings.ContainsAny(s, " \t\n")
+}
+
+// ObjectIsIRI returns whether the object of the triple should be written as
+// an IRI instead of a literal. Objects without a language that are http,
+// https or urn IRIs or Freebase style paths are IRIs, including literals that
+// happen to look like them.
+func ObjectIsIRI(t *protocol.Triple) bool {
+	return len(t.Lang) == 0 && (objectIRI.MatchString(t.Obj) || pathIRI.MatchString(t.Obj))
+}
+
+// resolveIRI returns the absolute IRI for a value under base. Absolute IRIs
+// and blank nodes are returned as is.
+func resolveIRI(base, s string) string {
+	if len(base) == 0 || IsBlank(s) || absoluteIRI.MatchString(s) {
+		return s
+	}
+	if strings.HasPrefix(s, "/") {
+		return base + s
+	}
+	return base + "/" + s
+}
+
+// relativeIRI returns the path of an IRI under base, or the IRI if it isn't
+// under base.
+func relativeIRI(base, s string) string {
+	if len(base) > 0 && strings.HasPrefix(s, base+"/") {
+		return s[len(base):]
+	}
+	return s
+}
+
+// formatResource formats a subject or predicate. Values that aren't absolute
+// IRIs are written under base.
+func formatResource(base, s string) string {
+	if IsBlank(s) {
+		return s
+	}
+	return formatIRI(resolveIRI(base, s))
+}
+
+func formatIRI(s string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('<')
+	for _, r := range s {
+		if r <= 0x20 || strings.ContainsRune("<>\"{}|^`\\", r) {
+			fmt.Fprintf(&buf, "\\u%04X", r)
+			continue
+		}
+		buf.WriteRune(r)
+	}
+	buf.WriteByte('>')
+	return buf.String()
+}
+
+func formatLiteral(s, lang string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+	if len(lang) > 0 {
+		buf.WriteByte('@')
+		buf.WriteString(lang)
+	}
+	return buf.String()
+}
+
+func formatObject(base string, t *protocol.Triple) string {
+	if IsBlank(t.Obj) && len(t.Lang) == 0 {
+		return t.Obj
+	}
+	if ObjectIsIRI(t) {
+		return formatIRI(resolveIRI(base, t.Obj))
+	}
+	return formatLiteral(t.Obj, t.Lang)
+}
+
+type ntriplesWriter struct {
+	w    *bufio.Writer
+	base string
+}
+
+func (w *ntriplesWriter) Write(t *protocol.Triple) error {
+	_, err := fmt.Fprintf(w.w, "%s %s %s .\n", formatResource(w.base, t.Subj), formatResource(w.base, t.Pred), formatObject(w.base, t))
+	return err
+}
+
+func (w *ntriplesWriter) Close() error {
+	return w.w.Flush()
+}
+
+// turtleWriter groups consecutive triples with the same subject.
+type turtleWriter struct {
+	w       *bufio.Writer
+	base    string
+	subj    string
+	hasSubj bool
+}
+
+func (w *turtleWriter) Write(t *protocol.Triple) error {
+	var err error
+	if w.hasSubj && t.Subj == w.subj {
+		_, err = fmt.Fprintf(w.w, " ;\n    %s %s", formatResource(w.base, t.Pred), formatObject(w.base, t))
+	} else {
+		if w.hasSubj {
+			w.w.WriteString(" .\n")
+		}
+		_, err = fmt.Fprintf(w.w, "%s %s %s", formatResource(w.base, t.Subj), formatResource(w.base, t.Pred), formatObject(w.base, t))
+	}
+	w.subj = t.Subj
+	w.hasSubj = true
+	return err
+}
+
+func (w *turtleWriter) Close() error {
+	if w.hasSubj {
+		w.w.WriteString(" .\n")
+	}
+	return w.w.Flush()
+}
+
+// jsonldWriter writes an expanded JSON-LD document. Consecutive triples with
+// the same subject are merged into a single node object.
+type jsonldWriter struct {
+	w       *bufio.Writer
+	base    string
+	node    *jsonldNode
+	written bool
+}
+
+type jsonldNode struct {
+	id    string
+	preds []string
+	props map[string][]map[string]string
+}
+
+func (w *jsonldWriter) Write(t *protocol.Triple) error {
+	if w.node != nil && w.node.id != t.Subj {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	if w.node == nil {
+		w.node = &jsonldNode{id: t.Subj, props: make(map[string][]map[string]string)}
+	}
+	var obj map[string]string
+	if (IsBlank(t.Obj) && len(t.Lang) == 0) || ObjectIsIRI(t) {
+		obj = map[string]string{"@id": resolveIRI(w.base, t.Obj)}
+	} else {
+		obj = map[string]string{"@value": t.Obj}
+		if len(t.Lang) > 0 {
+			obj["@language"] = t.Lang
+		}
+	}
+	if _, ok := w.node.props[t.Pred]; !ok {
+		w.node.preds = append(w.node.preds, t.Pred)
+	}
+	w.node.props[t.Pred] = append(w.node.props[t.Pred], obj)
+	return nil
+}
+
+// flush writes out the current node object.
+func (w *jsonldWriter) flush() error {
+	node := w.node
+	w.node = nil
+	if w.written {
+		w.w.WriteString(",\n")
+	} else {
+		w.w.WriteString("[\n")
+		w.written = true
+	}
+	id, err := json.Marshal(resolveIRI(w.base, node.id))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w.w, `{"@id":%s`, id)
+	for _, pred := range node.preds {
+		key, err := json.Marshal(resolveIRI(w.base, pred))
+		if err != nil {
+			return err
+		}
+		values, err := json.Marshal(node.props[pred])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w.w, ",%s:%s", key, values)
+	}
+	_, err = w.w.WriteString("}")
+	return err
+}
+
+func (w *jsonldWriter) Close() error {
+	if w.node != nil {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	if w.written {
+		w.w.WriteString("\n]\n")
+	} else {
+		w.w.WriteString("[]\n")
+	}
+	return w.w.Flush()
+}
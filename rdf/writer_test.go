@@ -0,0 +1,200 @@
+package rdf
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+var testTriples = []*protocol.Triple{
+	{
+		Subj: "/m/02mjmr",
+		Pred: "/type/object/name",
+		Obj:  "Barack \"Obama\"\n",
+		Lang: "en",
+	},
+	{
+		Subj: "/m/02mjmr",
+		Pred: "/type/object/type",
+		Obj:  "/people/person",
+	},
+	{
+		Subj: "http://example.com/a",
+		Pred: "http://example.com/knows",
+		Obj:  "_:b1",
+	},
+	{
+		Subj: "_:b1",
+		Pred: "http://example.com/name",
+		Obj:  "b one",
+	},
+}
+
+func writeAll(t *testing.T, format Format) string {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, format)
+	for _, triple := range testTriples {
+		if err := w.Write(triple); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestWriters(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		format Format
+		want   string
+	}{
+		{
+			NTriples,
+			`<http://degdb.org/m/02mjmr> <http://degdb.org/type/object/name> "Barack \"Obama\"\n"@en .
+<http://degdb.org/m/02mjmr> <http://degdb.org/type/object/type> <http://degdb.org/people/person> .
+<http://example.com/a> <http://example.com/knows> _:b1 .
+_:b1 <http://example.com/name> "b one" .
+`,
+		},
+		{
+			Turtle,
+			`<http://degdb.org/m/02mjmr> <http://degdb.org/type/object/name> "Barack \"Obama\"\n"@en ;
+    <http://degdb.org/type/object/type> <http://degdb.org/people/person> .
+<http://example.com/a> <http://example.com/knows> _:b1 .
+_:b1 <http://example.com/name> "b one" .
+`,
+		},
+		{
+			JSONLD,
+			`[
+{"@id":"http://degdb.org/m/02mjmr","http://degdb.org/type/object/name":[{"@language":"en","@value":"Barack \"Obama\"\n"}],"http://degdb.org/type/object/type":[{"@id":"http://degdb.org/people/person"}]},
+{"@id":"http://example.com/a","http://example.com/knows":[{"@id":"_:b1"}]},
+{"@id":"_:b1","http://example.com/name":[{"@value":"b one"}]}
+]
+`,
+		},
+	}
+	for i, td := range testData {
+		out := writeAll(t, td.format)
+		if out != td.want {
+			t.Errorf("%d. NewWriter(%d) wrote\n%s\nnot\n%s", i, td.format, out, td.want)
+		}
+	}
+
+	var doc []map[string]interface{}
+	if err := json.Unmarshal([]byte(writeAll(t, JSONLD)), &doc); err != nil {
+		t.Errorf("JSON-LD output isn't valid JSON: %s", err)
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	r := NewReader(bytes.NewBufferString(writeAll(t, NTriples)))
+	var out []*protocol.Triple
+	for {
+		triple, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, triple)
+	}
+	if diff, equal := messagediff.PrettyDiff(testTriples, out); !equal {
+		t.Errorf("round trip = %+v; not %+v\n%s", out, testTriples, diff)
+	}
+}
+
+func TestObjectIsIRI(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		triple *protocol.Triple
+		want   bool
+	}{
+		{&protocol.Triple{Obj: "http://example.com/a"}, true},
+		{&protocol.Triple{Obj: "HTTPS://example.com/a"}, true},
+		{&protocol.Triple{Obj: "urn:isbn:0451450523"}, true},
+		{&protocol.Triple{Obj: "/people/person"}, true},
+		{&protocol.Triple{Obj: "note: foo"}, false},
+		{&protocol.Triple{Obj: "note:foo"}, false},
+		{&protocol.Triple{Obj: "http://"}, false},
+		{&protocol.Triple{Obj: "http://example.com/a", Lang: "en"}, false},
+	}
+	for i, td := range testData {
+		if out := ObjectIsIRI(td.triple); out != td.want {
+			t.Errorf("%d. ObjectIsIRI(%+v) = %t; not %t", i, td.triple, out, td.want)
+		}
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, NTriples)
+	if err := w.Write(&protocol.Triple{Subj: "/a", Pred: "/b", Obj: "note: foo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "<http://degdb.org/a> <http://degdb.org/b> \"note: foo\" .\n"
+	if buf.String() != want {
+		t.Errorf("wrote %q; not %q", buf.String(), want)
+	}
+}
+
+func TestResolveIRI(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		base, rel, abs string
+	}{
+		{"http://degdb.org", "/m/02mjmr", "http://degdb.org/m/02mjmr"},
+		{"http://degdb.org", "name", "http://degdb.org/name"},
+		{"http://degdb.org", "http://example.com/a", "http://example.com/a"},
+		{"http://degdb.org", "_:b1", "_:b1"},
+		{"", "/m/02mjmr", "/m/02mjmr"},
+	}
+	for i, td := range testData {
+		if out := resolveIRI(td.base, td.rel); out != td.abs {
+			t.Errorf("%d. resolveIRI(%q, %q) = %q; not %q", i, td.base, td.rel, out, td.abs)
+		}
+	}
+	if out := relativeIRI("http://degdb.org", "http://degdb.org/m/02mjmr"); out != "/m/02mjmr" {
+		t.Errorf("relativeIRI() = %q; not /m/02mjmr", out)
+	}
+	if out := relativeIRI("http://degdb.org", "http://degdb.organic/a"); out != "http://degdb.organic/a" {
+		t.Errorf("relativeIRI() = %q; not http://degdb.organic/a", out)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		accept string
+		want   Format
+		ok     bool
+	}{
+		{"", NTriples, false},
+		{"text/html", NTriples, false},
+		{"*/*", NTriples, true},
+		{"text/turtle", Turtle, true},
+		{"application/ld+json, text/turtle;q=0.5", JSONLD, true},
+		{"application/n-triples;q=0.2, text/turtle;q=0.9", Turtle, true},
+		{"text/html, application/ld+json;q=0", NTriples, false},
+	}
+	for i, td := range testData {
+		format, ok := Negotiate(td.accept)
+		if format != td.want || ok != td.ok {
+			t.Errorf("%d. Negotiate(%#v) = %d, %t; not %d, %t", i, td.accept, format, ok, td.want, td.ok)
+		}
+	}
+}
@@ -10,18 +10,34 @@ import (
 // DefaultTripleBatchSize is the default number of triples to use when streaming.
 var DefaultTripleBatchSize = 1000
 
-// Bloom returns a ScalableBloomFilter containing all the triples the current node has in the optional keyspace.
-func (ts *TripleStore) Bloom(keyspace *protocol.Keyspace) (*boom.ScalableBloomFilter, error) {
+// bloom returns a ScalableBloomFilter containing all the triples of the store
+// in the optional keyspace.
+func bloom(ts TripleStore, keyspace *protocol.Keyspace) (*boom.ScalableBloomFilter, error) {
+	return bloomBy(ts, keyspace, subjectHash)
+}
+
+// ObjectBloom returns a ScalableBloomFilter containing all the triples of an
+// object index whose object hash is in the optional keyspace.
+func ObjectBloom(ts TripleStore, keyspace *protocol.Keyspace) (*boom.ScalableBloomFilter, error) {
+	return bloomBy(ts, keyspace, objectHash)
+}
+
+func subjectHash(triple *protocol.Triple) uint64 {
+	return murmur3.Sum64([]byte(triple.Subj))
+}
+
+func objectHash(triple *protocol.Triple) uint64 {
+	return murmur3.Sum64([]byte(triple.Obj))
+}
+
+func bloomBy(ts TripleStore, keyspace *protocol.Keyspace, hash func(*protocol.Triple) uint64) (*boom.ScalableBloomFilter, error) {
 	filter := boom.NewDefaultScalableBloomFilter(BloomFalsePositiveRate)
 
 	results, errs := ts.EachTripleBatch(DefaultTripleBatchSize)
 	for triples := range results {
 		for _, triple := range triples {
-			if keyspace != nil {
-				hash := murmur3.Sum64([]byte(triple.Subj))
-				if !keyspace.Includes(hash) {
-					continue
-				}
+			if keyspace != nil && !keyspace.Includes(hash(triple)) {
+				continue
 			}
 			data, err := triple.Marshal()
 			if err != nil {
@@ -36,21 +52,51 @@ func (ts *TripleStore) Bloom(keyspace *protocol.Keyspace) (*boom.ScalableBloomFi
 	return filter, nil
 }
 
-// TriplesMatchingBloom streams triples in batches of 1000 that match the bloom filter.
-func (ts *TripleStore) TriplesMatchingBloom(filter *boom.ScalableBloomFilter) (<-chan []*protocol.Triple, <-chan error) {
+// triplesMatchingBloom streams the triples of the store that match the bloom
+// filter in batches of DefaultTripleBatchSize.
+func triplesMatchingBloom(ts TripleStore, filter *boom.ScalableBloomFilter) (<-chan []*protocol.Triple, <-chan error) {
+	return streamBloom(ts, filter, nil, subjectHash, true)
+}
+
+// TriplesMissingBloom streams the triples of the store in the optional
+// keyspace that don't match the bloom filter in batches of
+// DefaultTripleBatchSize. These are the triples missing from the store the
+// filter was made from.
+func TriplesMissingBloom(ts TripleStore, filter *boom.ScalableBloomFilter, keyspace *protocol.Keyspace) (<-chan []*protocol.Triple, <-chan error) {
+	return streamBloom(ts, filter, keyspace, subjectHash, false)
+}
+
+// ObjectTriplesMissingBloom is TriplesMissingBloom for an object index, whose
+// triples are in the keyspace by their object hash.
+func ObjectTriplesMissingBloom(ts TripleStore, filter *boom.ScalableBloomFilter, keyspace *protocol.Keyspace) (<-chan []*protocol.Triple, <-chan error) {
+	return streamBloom(ts, filter, keyspace, objectHash, false)
+}
+
+// streamBloom streams the triples whose hash is in the optional keyspace and
+// whose membership in the bloom filter is match.
+func streamBloom(ts TripleStore, filter *boom.ScalableBloomFilter, keyspace *protocol.Keyspace, hash func(*protocol.Triple) uint64, match bool) (<-chan []*protocol.Triple, <-chan error) {
 	c := make(chan []*protocol.Triple, 10)
 	cerr := make(chan error, 1)
 	go func() {
+		defer close(c)
+		defer close(cerr)
+
 		triples := make([]*protocol.Triple, 0, DefaultTripleBatchSize)
 		results, errs := ts.EachTripleBatch(DefaultTripleBatchSize)
 		for resultTriples := range results {
 			for _, triple := range resultTriples {
+				if keyspace != nil && !keyspace.Includes(hash(triple)) {
+					continue
+				}
 				data, err := triple.Marshal()
 				if err != nil {
 					cerr <- err
-					break
+					// Drain the results so EachTripleBatch finishes.
+					for range results {
+					}
+					return
 				}
-				if !filter.Test(data) {
+				if filter.Test(data) != match {
 					continue
 				}
 				triples = append(triples, triple)
@@ -59,15 +105,13 @@ func (ts *TripleStore) TriplesMatchingBloom(filter *boom.ScalableBloomFilter) (<
 					triples = make([]*protocol.Triple, 0, DefaultTripleBatchSize)
 				}
 			}
-			if len(triples) > 0 {
-				c <- triples
-			}
+		}
+		if len(triples) > 0 {
+			c <- triples
 		}
 		for err := range errs {
 			cerr <- err
 		}
-		close(c)
-		close(cerr)
 	}()
 	return c, cerr
 }
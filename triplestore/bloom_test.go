@@ -1,9 +1,6 @@
 package triplestore
 
 import (
-	"io/ioutil"
-	"log"
-	"os"
 	"strconv"
 	"testing"
 
@@ -14,15 +11,10 @@ import (
 func TestBloom(t *testing.T) {
 	t.Parallel()
 
-	file, err := ioutil.TempFile(os.TempDir(), "triplestore.db")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.Remove(file.Name())
-	db, err := NewTripleStore(file.Name(), log.New(os.Stdout, "", log.Flags()))
-	if err != nil {
-		t.Fatal(err)
-	}
+	forEachBackend(t, testBloom)
+}
+
+func testBloom(t *testing.T, db TripleStore) {
 
 	tripleCount := 5000
 
@@ -37,7 +29,7 @@ func TestBloom(t *testing.T) {
 	additionalTriples = append(additionalTriples, testTriples...)
 	protocol.SortTriples(additionalTriples)
 
-	db.Insert(additionalTriples)
+	db.Write(additionalTriples)
 
 	filter, err := db.Bloom(nil)
 	if err != nil {
@@ -95,3 +87,47 @@ func TestBloom(t *testing.T) {
 		t.Errorf("TriplesMatchingBLoom(nil) incorrectly has %+v", triple)
 	}
 }
+
+func TestTriplesMissingBloom(t *testing.T) {
+	t.Parallel()
+
+	forEachBackend(t, testTriplesMissingBloom)
+}
+
+func testTriplesMissingBloom(t *testing.T, db TripleStore) {
+	other := NewMemoryStore()
+	other.Write(testTriples[:2])
+	filter, err := other.Bloom(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db.Write(testTriples)
+
+	var missing []*protocol.Triple
+	results, errs := TriplesMissingBloom(db, filter, nil)
+	for triples := range results {
+		missing = append(missing, triples...)
+	}
+	for err := range errs {
+		t.Error(err)
+	}
+	want := protocol.CloneTriples(testTriples[2:])
+	protocol.SortTriples(want)
+	protocol.SortTriples(missing)
+	if diff, ok := messagediff.PrettyDiff(want, missing); !ok {
+		t.Errorf("TriplesMissingBloom(filter, nil) = %#v; diff %s", missing, diff)
+	}
+
+	missing = nil
+	results, errs = TriplesMissingBloom(db, filter, &protocol.Keyspace{})
+	for triples := range results {
+		missing = append(missing, triples...)
+	}
+	for err := range errs {
+		t.Error(err)
+	}
+	for _, triple := range missing {
+		t.Errorf("TriplesMissingBloom(filter, empty keyspace) incorrectly has %+v", triple)
+	}
+}
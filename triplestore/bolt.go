@@ -0,0 +1,605 @@
+package triplestore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/d4l3k/go-disk-usage/du"
+	"github.com/tylertreat/BoomFilters"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// The bolt buckets. The triples are stored under spo, keyed by their subject,
+// predicate, object and author, and the pos and osp buckets index them by the
+// other orderings of their fields. Tombstones are keyed like the triple they
+// retract. The history is keyed the same way followed by the time of the
+// change.
+var (
+	spoBucket       = []byte("spo")
+	posBucket       = []byte("pos")
+	ospBucket       = []byte("osp")
+	tombstoneBucket = []byte("tombstones")
+	historyBucket   = []byte("history")
+)
+
+// BoltStore is a TripleStore backed by an embedded bolt key-value store. Keys
+// are the null separated fields of a triple in the order of their bucket so a
+// query with known fields is a prefix scan.
+type BoltStore struct {
+	db     *bolt.DB
+	dbFile string
+	logger *log.Logger
+}
+
+// NewBoltStore returns a BoltStore with the specified file.
+func NewBoltStore(file string, logger *log.Logger) (*BoltStore, error) {
+	db, err := bolt.Open(file, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{spoBucket, posBucket, ospBucket, tombstoneBucket, historyBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db, dbFile: file, logger: logger}, nil
+}
+
+func boltKey(fields ...string) []byte {
+	var buf bytes.Buffer
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(0)
+		}
+		buf.WriteString(field)
+	}
+	return buf.Bytes()
+}
+
+// scan is a prefix scan of a bucket. exact scans look up a single key.
+type scan struct {
+	bucket []byte
+	prefix []byte
+	exact  bool
+}
+
+// tripleScan picks the bucket with the longest prefix of known fields.
+func tripleScan(t *protocol.Triple) *scan {
+	s, p, o := len(t.Subj) > 0, len(t.Pred) > 0, len(t.Obj) > 0
+	switch {
+	case s && p && o && len(t.Author) > 0:
+		return &scan{spoBucket, boltKey(t.Subj, t.Pred, t.Obj, t.Author), true}
+	case s && p && o:
+		return &scan{spoBucket, boltKey(t.Subj, t.Pred, t.Obj, ""), false}
+	case s && p:
+		return &scan{spoBucket, boltKey(t.Subj, t.Pred, ""), false}
+	case s && o:
+		return &scan{ospBucket, boltKey(t.Obj, t.Subj, ""), false}
+	case p && o:
+		return &scan{posBucket, boltKey(t.Pred, t.Obj, ""), false}
+	case s:
+		return &scan{spoBucket, boltKey(t.Subj, ""), false}
+	case p:
+		return &scan{posBucket, boltKey(t.Pred, ""), false}
+	case o:
+		return &scan{ospBucket, boltKey(t.Obj, ""), false}
+	}
+	return nil
+}
+
+// arrayOpScans returns the scans that find every triple that can match the
+// ArrayOp. It returns false if a full scan is needed.
+func arrayOpScans(q *protocol.ArrayOp) ([]*scan, bool) {
+	switch q.Mode {
+	case protocol.OR:
+		var scans []*scan
+		for _, t := range q.Triples {
+			s := tripleScan(t)
+			if s == nil {
+				return nil, false
+			}
+			scans = append(scans, s)
+		}
+		for _, arrayOp := range q.Arguments {
+			argScans, ok := arrayOpScans(arrayOp)
+			if !ok {
+				return nil, false
+			}
+			scans = append(scans, argScans...)
+		}
+		return scans, true
+	case protocol.AND:
+		// Any one of the operands narrows the results enough.
+		for _, t := range q.Triples {
+			if s := tripleScan(t); s != nil {
+				return []*scan{s}, true
+			}
+		}
+		for _, arrayOp := range q.Arguments {
+			if scans, ok := arrayOpScans(arrayOp); ok {
+				return scans, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Query does a WHERE search with the set fields on query. A limit of -1
+// returns all results.
+func (ts *BoltStore) Query(query *protocol.Triple, limit int) ([]*protocol.Triple, error) {
+	return ts.QueryArrayOp(&protocol.ArrayOp{Triples: []*protocol.Triple{query}}, limit)
+}
+
+// QueryArrayOp runs an ArrayOp against the store. Results are ordered by
+// subject, predicate and object.
+func (ts *BoltStore) QueryArrayOp(q *protocol.ArrayOp, limit int) ([]*protocol.Triple, error) {
+	var results []*protocol.Triple
+	err := ts.db.View(func(tx *bolt.Tx) error {
+		spo := tx.Bucket(spoBucket)
+		match := func(v []byte) (bool, error) {
+			triple := &protocol.Triple{}
+			if err := triple.Unmarshal(v); err != nil {
+				return false, err
+			}
+			if !MatchArrayOp(q, triple) {
+				return true, nil
+			}
+			results = append(results, triple)
+			return limit <= 0 || len(results) < limit, nil
+		}
+
+		scans, ok := arrayOpScans(q)
+		if !ok {
+			c := spo.Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				if more, err := match(v); err != nil || !more {
+					return err
+				}
+			}
+			return nil
+		}
+
+		keys := make(map[string]bool)
+		for _, s := range scans {
+			if s.exact {
+				keys[string(s.prefix)] = true
+				continue
+			}
+			c := tx.Bucket(s.bucket).Cursor()
+			for k, v := c.Seek(s.prefix); k != nil && bytes.HasPrefix(k, s.prefix); k, v = c.Next() {
+				if bytes.Equal(s.bucket, spoBucket) {
+					keys[string(k)] = true
+				} else {
+					keys[string(v)] = true
+				}
+			}
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+		for _, key := range sorted {
+			v := spo.Get([]byte(key))
+			if v == nil {
+				continue
+			}
+			if more, err := match(v); err != nil || !more {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Write saves a bunch of triples and returns the triples written and the
+// stored triples they replaced.
+func (ts *BoltStore) Write(triples []*protocol.Triple) (written, replaced []*protocol.Triple, err error) {
+	err = ts.db.Update(func(tx *bolt.Tx) error {
+		spo := tx.Bucket(spoBucket)
+		pos := tx.Bucket(posBucket)
+		osp := tx.Bucket(ospBucket)
+		tombstones := tx.Bucket(tombstoneBucket)
+		for _, triple := range triples {
+			key := boltKey(triple.Subj, triple.Pred, triple.Obj, triple.Author)
+			if v := tombstones.Get(key); v != nil {
+				tombstone := &protocol.Tombstone{}
+				if err := tombstone.Unmarshal(v); err != nil {
+					return err
+				}
+				if Retracts(tombstone, triple) {
+					continue
+				}
+			}
+			if v := spo.Get(key); v != nil {
+				stored := &protocol.Triple{}
+				if err := stored.Unmarshal(v); err != nil {
+					return err
+				}
+				if !Replaces(triple, stored) {
+					continue
+				}
+				replaced = append(replaced, stored)
+			}
+			data, err := triple.Marshal()
+			if err != nil {
+				return err
+			}
+			if err := spo.Put(key, data); err != nil {
+				return err
+			}
+			if err := boltAddChange(tx, &protocol.Change{Triple: triple}); err != nil {
+				return err
+			}
+			if err := pos.Put(boltKey(triple.Pred, triple.Obj, triple.Subj, triple.Author), key); err != nil {
+				return err
+			}
+			if err := osp.Put(boltKey(triple.Obj, triple.Subj, triple.Pred, triple.Author), key); err != nil {
+				return err
+			}
+			written = append(written, triple)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return written, replaced, nil
+}
+
+// Remove removes the triples with the same subject, predicate, object and
+// author and returns the stored triples removed.
+func (ts *BoltStore) Remove(triples []*protocol.Triple) ([]*protocol.Triple, error) {
+	var removed []*protocol.Triple
+	err := ts.db.Update(func(tx *bolt.Tx) error {
+		for _, triple := range triples {
+			stored, err := boltStored(tx, triple)
+			if err != nil {
+				return err
+			}
+			if stored == nil {
+				continue
+			}
+			if err := boltDelete(tx, stored); err != nil {
+				return err
+			}
+			removed = append(removed, stored)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// boltStored returns the stored triple with the same subject, predicate,
+// object and author or nil.
+func boltStored(tx *bolt.Tx, triple *protocol.Triple) (*protocol.Triple, error) {
+	v := tx.Bucket(spoBucket).Get(boltKey(triple.Subj, triple.Pred, triple.Obj, triple.Author))
+	if v == nil {
+		return nil, nil
+	}
+	stored := &protocol.Triple{}
+	if err := stored.Unmarshal(v); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// boltDelete removes the stored triple with the same subject, predicate,
+// object and author.
+func boltDelete(tx *bolt.Tx, triple *protocol.Triple) error {
+	key := boltKey(triple.Subj, triple.Pred, triple.Obj, triple.Author)
+	if err := tx.Bucket(spoBucket).Delete(key); err != nil {
+		return err
+	}
+	if err := tx.Bucket(posBucket).Delete(boltKey(triple.Pred, triple.Obj, triple.Subj, triple.Author)); err != nil {
+		return err
+	}
+	return tx.Bucket(ospBucket).Delete(boltKey(triple.Obj, triple.Subj, triple.Pred, triple.Author))
+}
+
+// Erase saves the tombstones, deletes the triples they retract and returns
+// the stored triples deleted.
+func (ts *BoltStore) Erase(tombstones []*protocol.Tombstone) ([]*protocol.Triple, error) {
+	var removed []*protocol.Triple
+	err := ts.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(tombstoneBucket)
+		for _, tombstone := range tombstones {
+			key := boltKey(tombstone.Subj, tombstone.Pred, tombstone.Obj, tombstone.Author)
+			// Only the newest tombstone for a triple is kept.
+			newest := true
+			if v := bucket.Get(key); v != nil {
+				stored := &protocol.Tombstone{}
+				if err := stored.Unmarshal(v); err != nil {
+					return err
+				}
+				newest = tombstone.Created > stored.Created
+			}
+			if newest {
+				data, err := tombstone.Marshal()
+				if err != nil {
+					return err
+				}
+				if err := bucket.Put(key, data); err != nil {
+					return err
+				}
+			}
+			if err := boltAddChange(tx, &protocol.Change{Tombstone: tombstone}); err != nil {
+				return err
+			}
+			stored, err := boltStored(tx, tombstoneTriple(tombstone))
+			if err != nil {
+				return err
+			}
+			if stored == nil || !Retracts(tombstone, stored) {
+				continue
+			}
+			if err := boltDelete(tx, stored); err != nil {
+				return err
+			}
+			removed = append(removed, stored)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// Forget removes the history and tombstones of the triples and returns the
+// number of changes and tombstones removed.
+func (ts *BoltStore) Forget(triples []*protocol.Triple) int {
+	count := 0
+	err := ts.db.Update(func(tx *bolt.Tx) error {
+		tombstones := tx.Bucket(tombstoneBucket)
+		history := tx.Bucket(historyBucket)
+		for _, triple := range triples {
+			key := boltKey(triple.Subj, triple.Pred, triple.Obj, triple.Author)
+			if tombstones.Get(key) != nil {
+				if err := tombstones.Delete(key); err != nil {
+					return err
+				}
+				count++
+			}
+			// Deleting moves the cursor so the prefix is sought again.
+			prefix := boltKey(triple.Subj, triple.Pred, triple.Obj, triple.Author, "")
+			c := history.Cursor()
+			for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Seek(prefix) {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		ts.logger.Printf("ERR forgetting triples: %s", err)
+		return 0
+	}
+	return count
+}
+
+// Tombstones returns the tombstones with the fields set on query.
+func (ts *BoltStore) Tombstones(query *protocol.Tombstone) ([]*protocol.Tombstone, error) {
+	var prefix []byte
+	if len(query.Subj) > 0 && len(query.Pred) > 0 && len(query.Obj) > 0 {
+		prefix = boltKey(query.Subj, query.Pred, query.Obj, "")
+	} else if len(query.Subj) > 0 {
+		prefix = boltKey(query.Subj, "")
+	}
+	var results []*protocol.Tombstone
+	err := ts.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(tombstoneBucket).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			tombstone := &protocol.Tombstone{}
+			if err := tombstone.Unmarshal(v); err != nil {
+				return err
+			}
+			if MatchTombstone(query, tombstone) {
+				results = append(results, tombstone)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// boltAddChange adds a change to the history.
+func boltAddChange(tx *bolt.Tx, change *protocol.Change) error {
+	triple := change.Triple
+	retracted := byte(0)
+	if triple == nil {
+		triple = tombstoneTriple(change.Tombstone)
+		retracted = 1
+	}
+	data, err := change.Marshal()
+	if err != nil {
+		return err
+	}
+	key := boltKey(triple.Subj, triple.Pred, triple.Obj, triple.Author, "")
+	created := make([]byte, 8)
+	binary.BigEndian.PutUint64(created, uint64(changeCreated(change)))
+	key = append(append(key, created...), retracted)
+	return tx.Bucket(historyBucket).Put(key, data)
+}
+
+// History returns the changes to the triples with the fields set on query,
+// oldest first.
+func (ts *BoltStore) History(query *protocol.Triple) ([]*protocol.Change, error) {
+	var prefix []byte
+	if len(query.Subj) > 0 && len(query.Pred) > 0 && len(query.Obj) > 0 {
+		prefix = boltKey(query.Subj, query.Pred, query.Obj, "")
+	} else if len(query.Subj) > 0 {
+		prefix = boltKey(query.Subj, "")
+	}
+	var results []*protocol.Change
+	err := ts.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(historyBucket).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			change := &protocol.Change{}
+			if err := change.Unmarshal(v); err != nil {
+				return err
+			}
+			if MatchChange(query, change) {
+				results = append(results, change)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortChanges(results)
+	return results, nil
+}
+
+// EachChangeBatch streams the history in batches of the specified size. The
+// history is keyed by triple and then by time so it's streamed in key order.
+func (ts *BoltStore) EachChangeBatch(size int) (<-chan []*protocol.Change, <-chan error) {
+	c := make(chan []*protocol.Change, 10)
+	cerr := make(chan error, 1)
+
+	go func() {
+		var last []byte
+		for {
+			var changes []*protocol.Change
+			err := ts.db.View(func(tx *bolt.Tx) error {
+				cur := tx.Bucket(historyBucket).Cursor()
+				k, v := cur.First()
+				if last != nil {
+					if k, v = cur.Seek(last); bytes.Equal(k, last) {
+						k, v = cur.Next()
+					}
+				}
+				for ; k != nil && len(changes) < size; k, v = cur.Next() {
+					change := &protocol.Change{}
+					if err := change.Unmarshal(v); err != nil {
+						return err
+					}
+					changes = append(changes, change)
+					last = append(last[:0], k...)
+				}
+				return nil
+			})
+			if err != nil {
+				cerr <- err
+				break
+			}
+			if len(changes) == 0 {
+				break
+			}
+			c <- changes
+		}
+		close(c)
+		close(cerr)
+	}()
+	return c, cerr
+}
+
+// Compact does nothing. Bolt reuses the pages of removed triples instead of
+// shrinking the file so Size doesn't count them.
+func (ts *BoltStore) Compact() error {
+	return nil
+}
+
+// Size returns an info object about the number of triples and disk usage of
+// the database. Free pages waiting to be reused aren't counted.
+func (ts *BoltStore) Size() (*Info, error) {
+	fileInfo, err := os.Stat(ts.dbFile)
+	if err != nil {
+		return nil, err
+	}
+	space := du.NewDiskUsage(ts.dbFile)
+	i := &Info{
+		DiskSize:       uint64(fileInfo.Size()),
+		AvailableSpace: space.Available(),
+	}
+	if free := uint64(ts.db.Stats().FreeAlloc); free < i.DiskSize {
+		i.DiskSize -= free
+	}
+	err = ts.db.View(func(tx *bolt.Tx) error {
+		i.Triples = uint64(tx.Bucket(spoBucket).Stats().KeyN)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// EachTripleBatch is used to stream triples from the database in batches of
+// the specified size. Each batch is read in its own transaction.
+func (ts *BoltStore) EachTripleBatch(size int) (<-chan []*protocol.Triple, <-chan error) {
+	c := make(chan []*protocol.Triple, 10)
+	cerr := make(chan error, 1)
+
+	go func() {
+		var last []byte
+		for {
+			var triples []*protocol.Triple
+			err := ts.db.View(func(tx *bolt.Tx) error {
+				cur := tx.Bucket(spoBucket).Cursor()
+				k, v := cur.First()
+				if last != nil {
+					if k, v = cur.Seek(last); bytes.Equal(k, last) {
+						k, v = cur.Next()
+					}
+				}
+				for ; k != nil && len(triples) < size; k, v = cur.Next() {
+					triple := &protocol.Triple{}
+					if err := triple.Unmarshal(v); err != nil {
+						return err
+					}
+					triples = append(triples, triple)
+					last = append(last[:0], k...)
+				}
+				return nil
+			})
+			if err != nil {
+				cerr <- err
+				break
+			}
+			if len(triples) == 0 {
+				break
+			}
+			c <- triples
+		}
+		close(c)
+		close(cerr)
+	}()
+	return c, cerr
+}
+
+// Bloom returns a ScalableBloomFilter containing all the triples the current node has in the optional keyspace.
+func (ts *BoltStore) Bloom(keyspace *protocol.Keyspace) (*boom.ScalableBloomFilter, error) {
+	return bloom(ts, keyspace)
+}
+
+// TriplesMatchingBloom streams triples in batches of 1000 that match the bloom filter.
+func (ts *BoltStore) TriplesMatchingBloom(filter *boom.ScalableBloomFilter) (<-chan []*protocol.Triple, <-chan error) {
+	return triplesMatchingBloom(ts, filter)
+}
@@ -0,0 +1,185 @@
+package triplestore
+
+import (
+	"sort"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// QueryAsOf runs an ArrayOp against the triples of the store as they were at
+// the UNIX time asOf, using the history of the store. A limit of -1 returns
+// all results.
+func QueryAsOf(ts TripleStore, q *protocol.ArrayOp, asOf int64, limit int) ([]*protocol.Triple, error) {
+	// Only the triples matching q are kept while the changes to each triple
+	// are replayed in order.
+	state := make(map[string]*protocol.Triple)
+	replay := func(changes []*protocol.Change) {
+		for _, change := range changes {
+			if changeCreated(change) > asOf {
+				continue
+			}
+			if triple := change.Triple; triple != nil && MatchArrayOp(q, triple) {
+				state[tripleKey(triple)] = triple
+			} else if triple != nil {
+				delete(state, tripleKey(triple))
+			} else {
+				delete(state, tripleKey(tombstoneTriple(change.Tombstone)))
+			}
+		}
+	}
+
+	if patterns := historyPatterns(q); patterns != nil {
+		for _, pattern := range patterns {
+			changes, err := ts.History(pattern)
+			if err != nil {
+				return nil, err
+			}
+			replay(changes)
+		}
+	} else {
+		results, errs := ts.EachChangeBatch(DefaultTripleBatchSize)
+		for changes := range results {
+			replay(changes)
+		}
+		for err := range errs {
+			return nil, err
+		}
+	}
+
+	keys := make([]string, 0, len(state))
+	for key := range state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var results []*protocol.Triple
+	for _, key := range keys {
+		if limit > 0 && len(results) >= limit {
+			break
+		}
+		results = append(results, state[key])
+	}
+	return results, nil
+}
+
+// historyPatterns returns the triple patterns whose histories have every
+// triple matching q, or nil if the whole history has to be replayed. The
+// histories of a pattern have all the changes to the triples matching it so
+// they can be replayed one after another.
+func historyPatterns(q *protocol.ArrayOp) []*protocol.Triple {
+	switch q.Mode {
+	case protocol.AND:
+		// Any of the conjuncts narrows the history.
+		for _, t := range q.Triples {
+			if pattern := historyPattern(t); pattern != nil {
+				return []*protocol.Triple{pattern}
+			}
+		}
+		for _, arrayOp := range q.Arguments {
+			if patterns := historyPatterns(arrayOp); patterns != nil {
+				return patterns
+			}
+		}
+	case protocol.OR:
+		var patterns []*protocol.Triple
+		for _, t := range q.Triples {
+			pattern := historyPattern(t)
+			if pattern == nil {
+				return nil
+			}
+			patterns = append(patterns, pattern)
+		}
+		for _, arrayOp := range q.Arguments {
+			argPatterns := historyPatterns(arrayOp)
+			if argPatterns == nil {
+				return nil
+			}
+			patterns = append(patterns, argPatterns...)
+		}
+		return patterns
+	}
+	return nil
+}
+
+// historyPattern returns the fields of the triple the history can be queried
+// by, or nil if none are set.
+func historyPattern(t *protocol.Triple) *protocol.Triple {
+	if len(t.Subj) == 0 && len(t.Pred) == 0 && len(t.Obj) == 0 && len(t.Author) == 0 {
+		return nil
+	}
+	return &protocol.Triple{Subj: t.Subj, Pred: t.Pred, Obj: t.Obj, Author: t.Author}
+}
+
+// MatchChange returns whether the changed triple has the subject, predicate,
+// object and author set on query.
+func MatchChange(query *protocol.Triple, change *protocol.Change) bool {
+	triple := change.Triple
+	if triple == nil {
+		triple = tombstoneTriple(change.Tombstone)
+	}
+	return (len(query.Subj) == 0 || query.Subj == triple.Subj) &&
+		(len(query.Pred) == 0 || query.Pred == triple.Pred) &&
+		(len(query.Obj) == 0 || query.Obj == triple.Obj) &&
+		(len(query.Author) == 0 || query.Author == triple.Author)
+}
+
+// changeCreated returns the time of the assertion or retraction.
+func changeCreated(change *protocol.Change) int64 {
+	if change.Triple != nil {
+		return change.Triple.Created
+	}
+	return change.Tombstone.Created
+}
+
+// sortChangesByTriple sorts changes by the triple they change and then oldest
+// first.
+func sortChangesByTriple(changes []*protocol.Change) {
+	sortChanges(changes)
+	sort.Stable(changeTripleSlice(changes))
+}
+
+type changeTripleSlice []*protocol.Change
+
+func (s changeTripleSlice) Len() int      { return len(s) }
+func (s changeTripleSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s changeTripleSlice) Less(i, j int) bool {
+	return changeKey(s[i]) < changeKey(s[j])
+}
+
+// changeKey is the key of the triple a change asserts or retracts.
+func changeKey(change *protocol.Change) string {
+	if change.Triple != nil {
+		return tripleKey(change.Triple)
+	}
+	return tripleKey(tombstoneTriple(change.Tombstone))
+}
+
+// sortChanges sorts changes oldest first. Assertions come before retractions
+// made at the same time.
+func sortChanges(changes []*protocol.Change) {
+	sort.Stable(changeSlice(changes))
+}
+
+type changeSlice []*protocol.Change
+
+func (p changeSlice) Len() int { return len(p) }
+func (p changeSlice) Less(i, j int) bool {
+	a, b := changeCreated(p[i]), changeCreated(p[j])
+	if a != b {
+		return a < b
+	}
+	return p[i].Triple != nil && p[j].Triple == nil
+}
+func (p changeSlice) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+// Replaces returns whether the triple replaces the stored triple with the same
+// subject, predicate, object and author.
+func Replaces(triple, stored *protocol.Triple) bool {
+	return triple.Author == stored.Author && triple.Created > stored.Created
+}
+
+// Retracts returns whether the tombstone retracts the triple with the same
+// subject, predicate, object and author. A triple created after the tombstone
+// asserts it again.
+func Retracts(tombstone *protocol.Tombstone, triple *protocol.Triple) bool {
+	return tombstone.Author == triple.Author && tombstone.Created >= triple.Created
+}
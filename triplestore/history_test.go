@@ -0,0 +1,190 @@
+package triplestore
+
+import (
+	"testing"
+
+	"github.com/d4l3k/messagediff"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	forEachBackend(t, testHistory)
+}
+
+func TestHistoryAuthors(t *testing.T) {
+	t.Parallel()
+
+	forEachBackend(t, testHistoryAuthors)
+}
+
+func testHistoryAuthors(t *testing.T, db TripleStore) {
+	a := &protocol.Triple{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Obama", Author: "a", Sig: "1", Created: 10}
+	b := &protocol.Triple{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Obama", Author: "b", Sig: "2", Created: 20}
+	tombstone := &protocol.Tombstone{Subj: a.Subj, Pred: a.Pred, Obj: a.Obj, Author: "a", Signer: "a", Sig: "3", Created: 30}
+
+	// Each author's assertion of the same fact is kept.
+	if written, _, err := db.Write([]*protocol.Triple{a, b}); err != nil || len(written) != 2 {
+		t.Errorf("Write(a, b) = %d, %v; not 2", len(written), err)
+	}
+	triples, err := db.Query(&protocol.Triple{Subj: a.Subj}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff([]*protocol.Triple{a, b}, triples); !ok {
+		t.Errorf("Query() = %#v; diff %s", triples, diff)
+	}
+
+	// Retracting one author's assertion leaves the other.
+	if removed, err := db.Erase([]*protocol.Tombstone{tombstone}); err != nil || len(removed) != 1 {
+		t.Errorf("Erase(%+v) = %d, %v; not 1", tombstone, len(removed), err)
+	}
+	triples, err = db.Query(&protocol.Triple{Subj: a.Subj}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff([]*protocol.Triple{b}, triples); !ok {
+		t.Errorf("Query() after Retract = %#v; diff %s", triples, diff)
+	}
+
+	changes, err := db.History(&protocol.Triple{Subj: a.Subj})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*protocol.Change{{Triple: a}, {Triple: b}, {Tombstone: tombstone}}
+	if diff, ok := messagediff.PrettyDiff(want, changes); !ok {
+		t.Errorf("History() = %#v; diff %s", changes, diff)
+	}
+
+	asOfData := []struct {
+		asOf int64
+		want []*protocol.Triple
+	}{
+		{10, []*protocol.Triple{a}},
+		{20, []*protocol.Triple{a, b}},
+		{30, []*protocol.Triple{b}},
+	}
+	q := &protocol.ArrayOp{Triples: []*protocol.Triple{{Subj: a.Subj}}}
+	for i, td := range asOfData {
+		triples, err := QueryAsOf(db, q, td.asOf, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff, ok := messagediff.PrettyDiff(td.want, triples); !ok {
+			t.Errorf("%d. QueryAsOf(%d) = %#v; diff %s", i, td.asOf, triples, diff)
+		}
+	}
+}
+
+func testHistory(t *testing.T, db TripleStore) {
+	v1 := &protocol.Triple{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Obama", Author: "a", Sig: "1", Created: 10}
+	v2 := &protocol.Triple{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Obama", Author: "a", Sig: "2", Created: 20}
+	unrelated := &protocol.Triple{Subj: "/m/0hume", Pred: "/type/object/name", Obj: "Hume", Author: "a", Sig: "4", Created: 10}
+	tombstone := &protocol.Tombstone{Subj: v1.Subj, Pred: v1.Pred, Obj: v1.Obj, Author: "a", Signer: "a", Sig: "5", Created: 40}
+
+	testData := []struct {
+		triples []*protocol.Triple
+		want    int
+	}{
+		{[]*protocol.Triple{v1, unrelated}, 2},
+		// A newer assertion by the same author replaces the triple.
+		{[]*protocol.Triple{v2}, 1},
+		{[]*protocol.Triple{v1}, 0},
+	}
+	for i, td := range testData {
+		if written, _, err := db.Write(td.triples); err != nil || len(written) != td.want {
+			t.Errorf("%d. Write(%+v) = %d, %v; not %d", i, td.triples, len(written), err, td.want)
+		}
+	}
+	triples, err := db.Query(&protocol.Triple{Subj: v1.Subj}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff([]*protocol.Triple{v2}, triples); !ok {
+		t.Errorf("Query() = %#v; diff %s", triples, diff)
+	}
+
+	db.Erase([]*protocol.Tombstone{tombstone})
+
+	changes, err := db.History(&protocol.Triple{Subj: v1.Subj})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*protocol.Change{{Triple: v1}, {Triple: v2}, {Tombstone: tombstone}}
+	if diff, ok := messagediff.PrettyDiff(want, changes); !ok {
+		t.Errorf("History() = %#v; diff %s", changes, diff)
+	}
+
+	asOfData := []struct {
+		asOf int64
+		want []*protocol.Triple
+	}{
+		{5, nil},
+		{10, []*protocol.Triple{v1}},
+		{25, []*protocol.Triple{v2}},
+		{40, nil},
+	}
+	q := &protocol.ArrayOp{Triples: []*protocol.Triple{{Subj: v1.Subj}}}
+	for i, td := range asOfData {
+		triples, err := QueryAsOf(db, q, td.asOf, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff, ok := messagediff.PrettyDiff(td.want, triples); !ok {
+			t.Errorf("%d. QueryAsOf(%d) = %#v; diff %s", i, td.asOf, triples, diff)
+		}
+	}
+	triples, err = QueryAsOf(db, &protocol.ArrayOp{Triples: []*protocol.Triple{{Pred: v1.Pred}}}, 25, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff([]*protocol.Triple{v2, unrelated}, triples); !ok {
+		t.Errorf("QueryAsOf(pred, 25) = %#v; diff %s", triples, diff)
+	}
+
+	// Queries over several patterns or with NOT replay the streamed history.
+	queryData := []struct {
+		q    *protocol.ArrayOp
+		want []*protocol.Triple
+	}{
+		{
+			&protocol.ArrayOp{Triples: []*protocol.Triple{{Subj: v1.Subj}, {Subj: unrelated.Subj}}},
+			[]*protocol.Triple{v2, unrelated},
+		},
+		{
+			&protocol.ArrayOp{Mode: protocol.NOT, Triples: []*protocol.Triple{{Subj: unrelated.Subj}}},
+			[]*protocol.Triple{v2},
+		},
+		{
+			&protocol.ArrayOp{Mode: protocol.AND, Arguments: []*protocol.ArrayOp{
+				{Triples: []*protocol.Triple{{Subj: v1.Subj}}},
+				{Triples: []*protocol.Triple{{Pred: v1.Pred}}},
+			}},
+			[]*protocol.Triple{v2},
+		},
+	}
+	for i, td := range queryData {
+		triples, err := QueryAsOf(db, td.q, 25, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff, ok := messagediff.PrettyDiff(td.want, triples); !ok {
+			t.Errorf("%d. QueryAsOf(%+v, 25) = %#v; diff %s", i, td.q, triples, diff)
+		}
+	}
+
+	var all []*protocol.Change
+	batches, errs := db.EachChangeBatch(1)
+	for batch := range batches {
+		all = append(all, batch...)
+	}
+	for err := range errs {
+		t.Fatal(err)
+	}
+	want = []*protocol.Change{{Triple: v1}, {Triple: v2}, {Tombstone: tombstone}, {Triple: unrelated}}
+	if diff, ok := messagediff.PrettyDiff(want, all); !ok {
+		t.Errorf("EachChangeBatch(1) = %#v; diff %s", all, diff)
+	}
+}
@@ -0,0 +1,304 @@
+package triplestore
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/tylertreat/BoomFilters"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// MemoryStore is a TripleStore that keeps the triples in memory. It's meant for
+// tests and short lived nodes.
+type MemoryStore struct {
+	mu         sync.RWMutex
+	triples    []*protocol.Triple
+	keys       map[string]*protocol.Triple
+	tombstones map[string]*protocol.Tombstone
+	history    []*protocol.Change
+	changes    map[string]bool
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		keys:       make(map[string]*protocol.Triple),
+		tombstones: make(map[string]*protocol.Tombstone),
+		changes:    make(map[string]bool),
+	}
+}
+
+// Query does a WHERE search with the set fields on query. A limit of -1
+// returns all results.
+func (ts *MemoryStore) Query(query *protocol.Triple, limit int) ([]*protocol.Triple, error) {
+	return ts.QueryArrayOp(&protocol.ArrayOp{Triples: []*protocol.Triple{query}}, limit)
+}
+
+// QueryArrayOp runs an ArrayOp against the store.
+func (ts *MemoryStore) QueryArrayOp(q *protocol.ArrayOp, limit int) ([]*protocol.Triple, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	var results []*protocol.Triple
+	for _, triple := range ts.triples {
+		if limit > 0 && len(results) >= limit {
+			break
+		}
+		if MatchArrayOp(q, triple) {
+			t := *triple
+			results = append(results, &t)
+		}
+	}
+	return results, nil
+}
+
+// Write saves a bunch of triples and returns the triples written and the
+// stored triples they replaced.
+func (ts *MemoryStore) Write(triples []*protocol.Triple) (written, replaced []*protocol.Triple, err error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	for _, triple := range triples {
+		key := tripleKey(triple)
+		if tombstone := ts.tombstones[key]; tombstone != nil && Retracts(tombstone, triple) {
+			continue
+		}
+		t := *triple
+		if stored := ts.keys[key]; stored != nil {
+			if !Replaces(triple, stored) {
+				continue
+			}
+			old := *stored
+			replaced = append(replaced, &old)
+			*stored = t
+		} else {
+			ts.keys[key] = &t
+			ts.triples = append(ts.triples, &t)
+		}
+		ts.addChange(&protocol.Change{Triple: &t})
+		written = append(written, triple)
+	}
+	return written, replaced, nil
+}
+
+// addChange adds a change to the history unless it's already there. The lock
+// must be held.
+func (ts *MemoryStore) addChange(change *protocol.Change) {
+	data, err := change.Marshal()
+	if err != nil || ts.changes[string(data)] {
+		return
+	}
+	ts.changes[string(data)] = true
+	c := *change
+	if c.Triple != nil {
+		t := *c.Triple
+		c.Triple = &t
+	}
+	ts.history = append(ts.history, &c)
+}
+
+// Remove removes the triples with the same subject, predicate, object and
+// author and returns the stored triples removed.
+func (ts *MemoryStore) Remove(triples []*protocol.Triple) ([]*protocol.Triple, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	return ts.delete(triples), nil
+}
+
+// delete removes the triples with the same subject, predicate, object and
+// author and returns the stored triples removed. The lock must be held.
+func (ts *MemoryStore) delete(triples []*protocol.Triple) []*protocol.Triple {
+	remove := make(map[string]bool)
+	for _, triple := range triples {
+		if key := tripleKey(triple); ts.keys[key] != nil {
+			remove[key] = true
+		}
+	}
+	if len(remove) == 0 {
+		return nil
+	}
+	var removed []*protocol.Triple
+	kept := ts.triples[:0]
+	for _, triple := range ts.triples {
+		key := tripleKey(triple)
+		if remove[key] {
+			delete(ts.keys, key)
+			removed = append(removed, triple)
+			continue
+		}
+		kept = append(kept, triple)
+	}
+	ts.triples = kept
+	return removed
+}
+
+// Erase saves the tombstones, deletes the triples they retract and returns
+// the stored triples deleted.
+func (ts *MemoryStore) Erase(tombstones []*protocol.Tombstone) ([]*protocol.Triple, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var triples []*protocol.Triple
+	for _, tombstone := range tombstones {
+		key := tripleKey(tombstoneTriple(tombstone))
+		t := *tombstone
+		if stored := ts.tombstones[key]; stored == nil || tombstone.Created > stored.Created {
+			ts.tombstones[key] = &t
+		}
+		ts.addChange(&protocol.Change{Tombstone: &t})
+		if stored := ts.keys[key]; stored != nil && Retracts(tombstone, stored) {
+			triples = append(triples, stored)
+		}
+	}
+	return ts.delete(triples), nil
+}
+
+// Forget removes the history and tombstones of the triples and returns the
+// number of changes and tombstones removed.
+func (ts *MemoryStore) Forget(triples []*protocol.Triple) int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	forget := make(map[string]bool)
+	count := 0
+	for _, triple := range triples {
+		key := tripleKey(triple)
+		forget[key] = true
+		if ts.tombstones[key] != nil {
+			delete(ts.tombstones, key)
+			count++
+		}
+	}
+	kept := ts.history[:0]
+	for _, change := range ts.history {
+		triple := change.Triple
+		if triple == nil {
+			triple = tombstoneTriple(change.Tombstone)
+		}
+		if forget[tripleKey(triple)] {
+			if data, err := change.Marshal(); err == nil {
+				delete(ts.changes, string(data))
+			}
+			count++
+			continue
+		}
+		kept = append(kept, change)
+	}
+	ts.history = kept
+	return count
+}
+
+// Tombstones returns the tombstones with the fields set on query.
+func (ts *MemoryStore) Tombstones(query *protocol.Tombstone) ([]*protocol.Tombstone, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	var keys []string
+	for key, tombstone := range ts.tombstones {
+		if MatchTombstone(query, tombstone) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var results []*protocol.Tombstone
+	for _, key := range keys {
+		t := *ts.tombstones[key]
+		results = append(results, &t)
+	}
+	return results, nil
+}
+
+// History returns the changes to the triples with the fields set on query,
+// oldest first.
+func (ts *MemoryStore) History(query *protocol.Triple) ([]*protocol.Change, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	var results []*protocol.Change
+	for _, change := range ts.history {
+		if MatchChange(query, change) {
+			results = append(results, change)
+		}
+	}
+	sortChanges(results)
+	return results, nil
+}
+
+// EachChangeBatch streams the history in batches of the specified size.
+func (ts *MemoryStore) EachChangeBatch(size int) (<-chan []*protocol.Change, <-chan error) {
+	c := make(chan []*protocol.Change, 10)
+	cerr := make(chan error, 1)
+
+	ts.mu.RLock()
+	changes := make([]*protocol.Change, len(ts.history))
+	copy(changes, ts.history)
+	ts.mu.RUnlock()
+	sortChangesByTriple(changes)
+
+	go func() {
+		for len(changes) > 0 {
+			n := len(changes)
+			if n > size {
+				n = size
+			}
+			c <- changes[:n]
+			changes = changes[n:]
+		}
+		close(c)
+		close(cerr)
+	}()
+	return c, cerr
+}
+
+// Compact does nothing since a MemoryStore doesn't use any disk.
+func (ts *MemoryStore) Compact() error {
+	return nil
+}
+
+// Size returns the number of triples. A MemoryStore doesn't use any disk.
+func (ts *MemoryStore) Size() (*Info, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	return &Info{Triples: uint64(len(ts.triples))}, nil
+}
+
+// EachTripleBatch is used to stream triples in batches of the specified size.
+func (ts *MemoryStore) EachTripleBatch(size int) (<-chan []*protocol.Triple, <-chan error) {
+	c := make(chan []*protocol.Triple, 10)
+	cerr := make(chan error, 1)
+
+	go func() {
+		for i := 0; ; i += size {
+			ts.mu.RLock()
+			var triples []*protocol.Triple
+			if i < len(ts.triples) {
+				end := i + size
+				if end > len(ts.triples) {
+					end = len(ts.triples)
+				}
+				triples = protocol.CloneTriples(ts.triples[i:end])
+			}
+			ts.mu.RUnlock()
+			if len(triples) == 0 {
+				break
+			}
+			c <- triples
+		}
+		close(c)
+		close(cerr)
+	}()
+	return c, cerr
+}
+
+// Bloom returns a ScalableBloomFilter containing all the triples the current node has in the optional keyspace.
+func (ts *MemoryStore) Bloom(keyspace *protocol.Keyspace) (*boom.ScalableBloomFilter, error) {
+	return bloom(ts, keyspace)
+}
+
+// TriplesMatchingBloom streams triples in batches of 1000 that match the bloom filter.
+func (ts *MemoryStore) TriplesMatchingBloom(filter *boom.ScalableBloomFilter) (<-chan []*protocol.Triple, <-chan error) {
+	return triplesMatchingBloom(ts, filter)
+}
@@ -0,0 +1,92 @@
+package triplestore
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// keySpill is a temporary file of triple keys. Keys found while streaming the
+// store are spilled to it so they can be deleted in batches once the stream
+// is done, without holding them all in memory.
+type keySpill struct {
+	f     *os.File
+	w     *bufio.Writer
+	count int
+}
+
+func newKeySpill() (*keySpill, error) {
+	f, err := ioutil.TempFile("", "degdb-keys")
+	if err != nil {
+		return nil, err
+	}
+	return &keySpill{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// add spills the subject, predicate, object and author of the triple.
+func (s *keySpill) add(triple *protocol.Triple) error {
+	key := &protocol.Triple{Subj: triple.Subj, Pred: triple.Pred, Obj: triple.Obj, Author: triple.Author}
+	data, err := key.Marshal()
+	if err != nil {
+		return err
+	}
+	var size [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(size[:], uint64(len(data)))
+	if _, err := s.w.Write(size[:n]); err != nil {
+		return err
+	}
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	s.count++
+	return nil
+}
+
+// each calls fn with the spilled keys in batches of the specified size.
+func (s *keySpill) each(size int, fn func([]*protocol.Triple) error) error {
+	if s.count == 0 {
+		return nil
+	}
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	if _, err := s.f.Seek(0, 0); err != nil {
+		return err
+	}
+	r := bufio.NewReader(s.f)
+	batch := make([]*protocol.Triple, 0, size)
+	for i := 0; i < s.count; i++ {
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return err
+		}
+		data := make([]byte, n)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return err
+		}
+		key := &protocol.Triple{}
+		if err := key.Unmarshal(data); err != nil {
+			return err
+		}
+		if batch = append(batch, key); len(batch) == size {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]*protocol.Triple, 0, size)
+		}
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// close removes the file.
+func (s *keySpill) close() {
+	s.f.Close()
+	os.Remove(s.f.Name())
+}
@@ -0,0 +1,458 @@
+package triplestore
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/d4l3k/go-disk-usage/du"
+	"github.com/jinzhu/gorm"
+	"github.com/tylertreat/BoomFilters"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/degdb/degdb/protocol"
+)
+
+// SQLiteStore is a TripleStore backed by a sqlite3 database.
+type SQLiteStore struct {
+	db     gorm.DB
+	dbFile string
+}
+
+// NewSQLiteStore returns a SQLiteStore with the specified file.
+func NewSQLiteStore(file string, logger *log.Logger) (*SQLiteStore, error) {
+	ts := &SQLiteStore{
+		dbFile: file,
+	}
+	var err error
+	if ts.db, err = gorm.Open("sqlite3", file); err != nil {
+		return nil, err
+	}
+	ts.db.SetLogger(logger)
+	ts.db.CreateTable(&protocol.Triple{})
+	ts.db.Model(&protocol.Triple{}).AddIndex("idx_subj", "subj")
+	ts.db.Model(&protocol.Triple{}).AddIndex("idx_pred", "pred")
+	// Triples used to be unique by subject, predicate and object.
+	ts.db.Model(&protocol.Triple{}).RemoveIndex("idx_subj_pred_obj")
+	ts.db.Model(&protocol.Triple{}).AddUniqueIndex("idx_subj_pred_obj_author", "subj", "pred", "obj", "author")
+	ts.db.AutoMigrate(&protocol.Triple{})
+	ts.db.CreateTable(&protocol.Tombstone{})
+	ts.db.Model(&protocol.Tombstone{}).AddUniqueIndex("idx_tombstone", "subj", "pred", "obj", "author")
+	ts.db.AutoMigrate(&protocol.Tombstone{})
+	ts.db.CreateTable(&sqliteChange{})
+	ts.db.Model(&sqliteChange{}).AddIndex("idx_changes_subj", "subj")
+	ts.db.Model(&sqliteChange{}).AddUniqueIndex("idx_change", "subj", "pred", "obj", "author", "created", "retracted")
+	ts.db.AutoMigrate(&sqliteChange{})
+	return ts, nil
+}
+
+// sqliteChange is a row of the history table. Retractions have the signer
+// and signature of their tombstone.
+type sqliteChange struct {
+	Subj, Pred, Obj, Lang, Author, Signer, Sig string
+	Created                                    int64
+	Retracted                                  bool
+}
+
+func (sqliteChange) TableName() string {
+	return "changes"
+}
+
+func newSQLiteChange(change *protocol.Change) *sqliteChange {
+	if t := change.Triple; t != nil {
+		return &sqliteChange{Subj: t.Subj, Pred: t.Pred, Obj: t.Obj, Lang: t.Lang, Author: t.Author, Sig: t.Sig, Created: t.Created}
+	}
+	t := change.Tombstone
+	return &sqliteChange{Subj: t.Subj, Pred: t.Pred, Obj: t.Obj, Author: t.Author, Signer: t.Signer, Sig: t.Sig, Created: t.Created, Retracted: true}
+}
+
+// sqliteAddChange adds a change to the history unless it's already there.
+func sqliteAddChange(tx *gorm.DB, change *protocol.Change) error {
+	c := newSQLiteChange(change)
+	var count int
+	err := tx.Model(&sqliteChange{}).Where("subj = ? AND pred = ? AND obj = ? AND author = ? AND created = ? AND retracted = ?", c.Subj, c.Pred, c.Obj, c.Author, c.Created, c.Retracted).Count(&count).Error
+	if err != nil || count > 0 {
+		return err
+	}
+	return tx.Create(c).Error
+}
+
+func (c *sqliteChange) change() *protocol.Change {
+	if c.Retracted {
+		return &protocol.Change{Tombstone: &protocol.Tombstone{Subj: c.Subj, Pred: c.Pred, Obj: c.Obj, Author: c.Author, Signer: c.Signer, Sig: c.Sig, Created: c.Created}}
+	}
+	return &protocol.Change{Triple: &protocol.Triple{Subj: c.Subj, Pred: c.Pred, Obj: c.Obj, Lang: c.Lang, Author: c.Author, Sig: c.Sig, Created: c.Created}}
+}
+
+// Query does a WHERE search with the set fields on query. A limit of -1
+// returns all results.
+func (ts *SQLiteStore) Query(query *protocol.Triple, limit int) ([]*protocol.Triple, error) {
+	dbq := ts.db.Where(*query)
+	if limit > 0 {
+		dbq = dbq.Limit(limit)
+	}
+	var results []*protocol.Triple
+	if err := dbq.Find(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// QueryArrayOp runs an ArrayOp against the local triple store.
+func (ts *SQLiteStore) QueryArrayOp(q *protocol.ArrayOp, limit int) ([]*protocol.Triple, error) {
+	query := ArrayOpToSQL(q)
+	args := make([]interface{}, len(query)-1)
+	for i, arg := range query[1:] {
+		args[i] = arg
+	}
+	dbq := ts.db.Where(query[0], args...)
+	if limit > 0 {
+		dbq = dbq.Limit(limit)
+	}
+	var results []*protocol.Triple
+	if err := dbq.Find(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func ArrayOpToSQL(q *protocol.ArrayOp) []string {
+	var rules []string
+	args := []string{""}
+	switch q.Mode {
+	case protocol.AND, protocol.OR:
+		for _, triple := range q.Triples {
+			sql := TripleToSQL(triple)
+			args = append(args, sql[1:]...)
+			rules = append(rules, sql[0])
+		}
+		for _, arrayOp := range q.Arguments {
+			sql := ArrayOpToSQL(arrayOp)
+			args = append(args, sql[1:]...)
+			rules = append(rules, sql[0])
+		}
+		mode := protocol.ArrayOp_Mode_name[int32(q.Mode)]
+		args[0] = "(" + strings.Join(rules, ") "+mode+" (") + ")"
+	case protocol.NOT:
+		if len(q.Triples) > 0 {
+			args = TripleToSQL(q.Triples[0])
+		} else if len(q.Arguments) > 0 {
+			args = ArrayOpToSQL(q.Arguments[0])
+		}
+		args[0] = "NOT (" + args[0] + ")"
+	}
+	return args
+}
+
+func TripleToSQL(triple *protocol.Triple) []string {
+	var rules []string
+	args := []string{""}
+	if len(triple.Subj) > 0 {
+		rules = append(rules, "subj = ?")
+		args = append(args, triple.Subj)
+	}
+	if len(triple.Pred) > 0 {
+		rules = append(rules, "pred = ?")
+		args = append(args, triple.Pred)
+	}
+	if len(triple.Obj) > 0 {
+		rules = append(rules, "obj = ?")
+		args = append(args, triple.Obj)
+	}
+	if len(triple.Lang) > 0 {
+		rules = append(rules, "lang = ?")
+		args = append(args, triple.Lang)
+	}
+	if len(triple.Author) > 0 {
+		rules = append(rules, "author = ?")
+		args = append(args, triple.Author)
+	}
+	// An empty triple matches everything.
+	if len(rules) == 0 {
+		rules = append(rules, "1 = 1")
+	}
+	args[0] = strings.Join(rules, " AND ")
+	return args
+}
+
+// Write saves a bunch of triples and returns the triples written and the
+// stored triples they replaced.
+func (ts *SQLiteStore) Write(triples []*protocol.Triple) (written, replaced []*protocol.Triple, err error) {
+	tx := ts.db.Begin()
+	for _, triple := range triples {
+		ok, stored, err := sqliteWrite(tx, triple)
+		if err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+		if stored != nil {
+			replaced = append(replaced, stored)
+		}
+		if ok {
+			written = append(written, triple)
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, nil, err
+	}
+	return written, replaced, nil
+}
+
+// sqliteWrite saves the triple unless it's retracted or older than the stored
+// one. It returns whether the triple was written and the stored triple it
+// replaced.
+func sqliteWrite(tx *gorm.DB, triple *protocol.Triple) (bool, *protocol.Triple, error) {
+	where := "subj = ? AND pred = ? AND obj = ? AND author = ?"
+	var retracted int
+	if err := tx.Model(&protocol.Tombstone{}).Where(where+" AND created >= ?", triple.Subj, triple.Pred, triple.Obj, triple.Author, triple.Created).Count(&retracted).Error; err != nil {
+		return false, nil, err
+	}
+	if retracted > 0 {
+		return false, nil, nil
+	}
+	var stored []*protocol.Triple
+	if err := tx.Where(where, triple.Subj, triple.Pred, triple.Obj, triple.Author).Limit(1).Find(&stored).Error; err != nil {
+		return false, nil, err
+	}
+	var replaced *protocol.Triple
+	if len(stored) > 0 {
+		if !Replaces(triple, stored[0]) {
+			return false, nil, nil
+		}
+		err := tx.Model(&protocol.Triple{}).Where(where, triple.Subj, triple.Pred, triple.Obj, triple.Author).Updates(map[string]interface{}{
+			"lang":    triple.Lang,
+			"sig":     triple.Sig,
+			"created": triple.Created,
+		}).Error
+		if err != nil {
+			return false, nil, err
+		}
+		replaced = stored[0]
+	} else if err := tx.Create(triple).Error; err != nil {
+		return false, nil, err
+	}
+	if err := sqliteAddChange(tx, &protocol.Change{Triple: triple}); err != nil {
+		return false, nil, err
+	}
+	return true, replaced, nil
+}
+
+// Remove removes the triples with the same subject, predicate, object and
+// author and returns the stored triples removed.
+func (ts *SQLiteStore) Remove(triples []*protocol.Triple) ([]*protocol.Triple, error) {
+	var removed []*protocol.Triple
+	tx := ts.db.Begin()
+	for _, triple := range triples {
+		stored, err := sqliteDelete(tx, triple, "1 = 1")
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if stored != nil {
+			removed = append(removed, stored)
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// sqliteDelete removes the stored triple with the same subject, predicate,
+// object and author if it matches the extra condition and returns it.
+func sqliteDelete(tx *gorm.DB, triple *protocol.Triple, cond string, args ...interface{}) (*protocol.Triple, error) {
+	where := "subj = ? AND pred = ? AND obj = ? AND author = ? AND " + cond
+	args = append([]interface{}{triple.Subj, triple.Pred, triple.Obj, triple.Author}, args...)
+	var stored []*protocol.Triple
+	if err := tx.Where(where, args...).Limit(1).Find(&stored).Error; err != nil || len(stored) == 0 {
+		return nil, err
+	}
+	res := tx.Where(where, args...).Delete(&protocol.Triple{})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return nil, res.Error
+	}
+	return stored[0], nil
+}
+
+// Erase saves the tombstones, deletes the triples they retract and returns
+// the stored triples deleted.
+func (ts *SQLiteStore) Erase(tombstones []*protocol.Tombstone) ([]*protocol.Triple, error) {
+	var removed []*protocol.Triple
+	tx := ts.db.Begin()
+	for _, tombstone := range tombstones {
+		triple, err := sqliteErase(tx, tombstone)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if triple != nil {
+			removed = append(removed, triple)
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// sqliteErase saves the tombstone and deletes the stored triple it retracts
+// and returns it.
+func sqliteErase(tx *gorm.DB, tombstone *protocol.Tombstone) (*protocol.Triple, error) {
+	where := "subj = ? AND pred = ? AND obj = ? AND author = ?"
+	// Only the newest tombstone for a triple is kept.
+	var stored []*protocol.Tombstone
+	if err := tx.Where(where, tombstone.Subj, tombstone.Pred, tombstone.Obj, tombstone.Author).Limit(1).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	if len(stored) == 0 {
+		if err := tx.Create(tombstone).Error; err != nil {
+			return nil, err
+		}
+	} else if tombstone.Created > stored[0].Created {
+		err := tx.Model(&protocol.Tombstone{}).Where(where, tombstone.Subj, tombstone.Pred, tombstone.Obj, tombstone.Author).Updates(map[string]interface{}{
+			"signer":  tombstone.Signer,
+			"sig":     tombstone.Sig,
+			"created": tombstone.Created,
+		}).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := sqliteAddChange(tx, &protocol.Change{Tombstone: tombstone}); err != nil {
+		return nil, err
+	}
+	return sqliteDelete(tx, tombstoneTriple(tombstone), "created <= ?", tombstone.Created)
+}
+
+// Forget removes the history and tombstones of the triples and returns the
+// number of changes and tombstones removed.
+func (ts *SQLiteStore) Forget(triples []*protocol.Triple) int {
+	count := 0
+	tx := ts.db.Begin()
+	for _, triple := range triples {
+		where := "subj = ? AND pred = ? AND obj = ? AND author = ?"
+		for _, model := range []interface{}{&protocol.Tombstone{}, &sqliteChange{}} {
+			res := tx.Where(where, triple.Subj, triple.Pred, triple.Obj, triple.Author).Delete(model)
+			if res.Error != nil {
+				continue
+			}
+			count += int(res.RowsAffected)
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return 0
+	}
+	return count
+}
+
+// Tombstones returns the tombstones with the fields set on query.
+func (ts *SQLiteStore) Tombstones(query *protocol.Tombstone) ([]*protocol.Tombstone, error) {
+	var results []*protocol.Tombstone
+	where := &protocol.Tombstone{Subj: query.Subj, Pred: query.Pred, Obj: query.Obj, Author: query.Author}
+	if err := ts.db.Where(where).Order("subj, pred, obj, author").Find(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// History returns the changes to the triples with the fields set on query,
+// oldest first.
+func (ts *SQLiteStore) History(query *protocol.Triple) ([]*protocol.Change, error) {
+	var rows []*sqliteChange
+	where := &sqliteChange{Subj: query.Subj, Pred: query.Pred, Obj: query.Obj, Author: query.Author}
+	if err := ts.db.Where(where).Order("created, retracted").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	changes := make([]*protocol.Change, len(rows))
+	for i, row := range rows {
+		changes[i] = row.change()
+	}
+	return changes, nil
+}
+
+// EachChangeBatch streams the history in batches of the specified size.
+func (ts *SQLiteStore) EachChangeBatch(size int) (<-chan []*protocol.Change, <-chan error) {
+	c := make(chan []*protocol.Change, 10)
+	cerr := make(chan error, 1)
+
+	go func() {
+		dbq := ts.db.Order("subj, pred, obj, author, created, retracted").Limit(size)
+
+		for i := 0; ; i++ {
+			var rows []*sqliteChange
+			if err := dbq.Offset(i * size).Find(&rows).Error; err != nil {
+				cerr <- err
+				break
+			}
+			if len(rows) == 0 {
+				break
+			}
+			changes := make([]*protocol.Change, len(rows))
+			for j, row := range rows {
+				changes[j] = row.change()
+			}
+			c <- changes
+		}
+		close(c)
+		close(cerr)
+	}()
+	return c, cerr
+}
+
+// Compact runs VACUUM to shrink the database file.
+func (ts *SQLiteStore) Compact() error {
+	return ts.db.Exec("VACUUM").Error
+}
+
+// Size returns an info object about the number of triples and file size of the
+// database.
+func (ts *SQLiteStore) Size() (*Info, error) {
+	fileInfo, err := os.Stat(ts.dbFile)
+	if err != nil {
+		return nil, err
+	}
+	space := du.NewDiskUsage(ts.dbFile)
+	i := &Info{
+		DiskSize:       uint64(fileInfo.Size()),
+		AvailableSpace: space.Available(),
+	}
+	ts.db.Model(&protocol.Triple{}).Count(&i.Triples)
+
+	return i, nil
+}
+
+// EachTripleBatch is used to stream triples from the database in batches of the specified size.
+func (ts *SQLiteStore) EachTripleBatch(size int) (<-chan []*protocol.Triple, <-chan error) {
+	c := make(chan []*protocol.Triple, 10)
+	cerr := make(chan error, 1)
+
+	go func() {
+		dbq := ts.db.Where(&protocol.Triple{}).Limit(size)
+
+		var triples []*protocol.Triple
+		for i := 0; i == 0 || len(triples) > 0; i++ {
+			triples = triples[0:0]
+			if err := dbq.Offset(i * size).Find(&triples).Error; err != nil {
+				cerr <- err
+				break
+			}
+			if len(triples) > 0 {
+				c <- triples
+			}
+		}
+		close(c)
+		close(cerr)
+	}()
+	return c, cerr
+}
+
+// Bloom returns a ScalableBloomFilter containing all the triples the current node has in the optional keyspace.
+func (ts *SQLiteStore) Bloom(keyspace *protocol.Keyspace) (*boom.ScalableBloomFilter, error) {
+	return bloom(ts, keyspace)
+}
+
+// TriplesMatchingBloom streams triples in batches of 1000 that match the bloom filter.
+func (ts *SQLiteStore) TriplesMatchingBloom(filter *boom.ScalableBloomFilter) (<-chan []*protocol.Triple, <-chan error) {
+	return triplesMatchingBloom(ts, filter)
+}
@@ -1,16 +1,13 @@
-// Package triplestore provides utilities for saving and querying triples with
-// a sqlite3 backend.
+// Package triplestore provides utilities for saving and querying triples. The
+// storage backend is pluggable: sqlite3, an embedded bolt key-value store or
+// memory.
 package triplestore
 
 import (
+	"fmt"
 	"log"
-	"os"
-	"strings"
 
-	"github.com/d4l3k/go-disk-usage/du"
-	"github.com/jinzhu/gorm"
-
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/tylertreat/BoomFilters"
 
 	"github.com/degdb/degdb/protocol"
 )
@@ -21,175 +18,215 @@ const (
 	BloomFalsePositiveRate = 1.0e-9
 )
 
-type TripleStore struct {
-	db     gorm.DB
-	dbFile string
+// TripleStore is a storage backend for triples. Triples are unique by subject,
+// predicate, object and author so every author's assertion of a fact is kept.
+type TripleStore interface {
+	// Query does a WHERE search with the set fields on query. A limit of -1
+	// returns all results.
+	Query(query *protocol.Triple, limit int) ([]*protocol.Triple, error)
+	// QueryArrayOp runs an ArrayOp against the store.
+	QueryArrayOp(q *protocol.ArrayOp, limit int) ([]*protocol.Triple, error)
+	// Write saves a bunch of triples and returns the triples written and the
+	// stored triples they replaced. A triple replaces the one with the same
+	// subject, predicate, object and author if it's newer. The assertions are
+	// added to the history.
+	Write(triples []*protocol.Triple) (written, replaced []*protocol.Triple, err error)
+	// Remove removes the triples with the same subject, predicate, object and
+	// author and returns the stored triples removed.
+	Remove(triples []*protocol.Triple) ([]*protocol.Triple, error)
+	// Erase saves the tombstones, deletes the triples they retract and returns
+	// the stored triples deleted. Retracted triples aren't written again
+	// unless they're created after the tombstone, see Retracts.
+	Erase(tombstones []*protocol.Tombstone) ([]*protocol.Triple, error)
+	// Forget removes the history and tombstones of the triples with the same
+	// subject, predicate, object and author and returns the number of changes
+	// and tombstones removed.
+	Forget(triples []*protocol.Triple) int
+	// Tombstones returns the tombstones with the subject, predicate, object
+	// and author set on query.
+	Tombstones(query *protocol.Tombstone) ([]*protocol.Tombstone, error)
+	// History returns the assertions and retractions of the triples with the
+	// subject, predicate, object and author set on query, oldest first.
+	History(query *protocol.Triple) ([]*protocol.Change, error)
+	// EachChangeBatch streams the whole history in batches of the specified
+	// size. The changes to each triple are streamed together, oldest first.
+	EachChangeBatch(size int) (<-chan []*protocol.Change, <-chan error)
+	// Compact releases the disk space of removed triples.
+	Compact() error
+	// Size returns an info object about the number of triples and disk usage.
+	Size() (*Info, error)
+	// EachTripleBatch is used to stream triples in batches of the specified
+	// size.
+	EachTripleBatch(size int) (<-chan []*protocol.Triple, <-chan error)
+	// Bloom returns a ScalableBloomFilter containing all the triples in the
+	// optional keyspace.
+	Bloom(keyspace *protocol.Keyspace) (*boom.ScalableBloomFilter, error)
+	// TriplesMatchingBloom streams triples that match the bloom filter.
+	TriplesMatchingBloom(filter *boom.ScalableBloomFilter) (<-chan []*protocol.Triple, <-chan error)
 }
 
-// NewTripleStore returns a TripleStore with the specified file.
-func NewTripleStore(file string, logger *log.Logger) (*TripleStore, error) {
-	ts := &TripleStore{
-		dbFile: file,
-	}
-	var err error
-	if ts.db, err = gorm.Open("sqlite3", file); err != nil {
-		return nil, err
-	}
-	ts.db.SetLogger(logger)
-	ts.db.CreateTable(&protocol.Triple{})
-	ts.db.Model(&protocol.Triple{}).AddIndex("idx_subj", "subj")
-	ts.db.Model(&protocol.Triple{}).AddIndex("idx_pred", "pred")
-	ts.db.Model(&protocol.Triple{}).AddUniqueIndex("idx_subj_pred_obj", "subj", "pred", "obj")
-	ts.db.AutoMigrate(&protocol.Triple{})
-	return ts, nil
+// Info represents the state of the database.
+type Info struct {
+	Triples, DiskSize, AvailableSpace uint64
+}
+
+// Backends are the names of the storage backends Open accepts.
+var Backends = []string{"sqlite", "bolt", "memory"}
+
+// Open returns a TripleStore using the named backend and file. The memory
+// backend doesn't use the file.
+func Open(backend, file string, logger *log.Logger) (TripleStore, error) {
+	switch backend {
+	case "sqlite":
+		return NewSQLiteStore(file, logger)
+	case "bolt":
+		return NewBoltStore(file, logger)
+	case "memory":
+		return NewMemoryStore(), nil
+	}
+	return nil, fmt.Errorf("triplestore: unknown backend %q", backend)
+}
+
+// NewTripleStore returns a sqlite3 TripleStore with the specified file.
+func NewTripleStore(file string, logger *log.Logger) (TripleStore, error) {
+	return NewSQLiteStore(file, logger)
 }
 
-// Query does a WHERE search with the set fields on query. A limit of -1
-// returns all results.
-func (ts *TripleStore) Query(query *protocol.Triple, limit int) ([]*protocol.Triple, error) {
-	dbq := ts.db.Where(*query)
-	if limit > 0 {
-		dbq = dbq.Limit(limit)
+// Evict deletes the triples whose hash isn't in the keyspace along with their
+// history and tombstones and compacts the store. It returns the number of
+// triples deleted. The store is streamed once for the triples and once for the
+// history, spilling the keys to evict to a temporary file, and they're deleted
+// in batches of DefaultTripleBatchSize after each stream.
+func Evict(ts TripleStore, keyspace *protocol.Keyspace, hash func(*protocol.Triple) uint64) (int, error) {
+	evict, err := newKeySpill()
+	if err != nil {
+		return 0, err
+	}
+	defer evict.close()
+	var spillErr error
+	results, errs := ts.EachTripleBatch(DefaultTripleBatchSize)
+	for triples := range results {
+		for _, triple := range triples {
+			if spillErr == nil && !keyspace.Includes(hash(triple)) {
+				spillErr = evict.add(triple)
+			}
+		}
 	}
-	var results []*protocol.Triple
-	if err := dbq.Find(&results).Error; err != nil {
-		return nil, err
+	for err := range errs {
+		return 0, err
+	}
+	if spillErr != nil {
+		return 0, spillErr
+	}
+	count := 0
+	err = evict.each(DefaultTripleBatchSize, func(batch []*protocol.Triple) error {
+		removed, err := ts.Remove(batch)
+		count += len(removed)
+		return err
+	})
+	if err != nil {
+		return count, err
 	}
-	return results, nil
-}
 
-// QueryArrayOp runs an ArrayOp against the local triple store.
-func (ts *TripleStore) QueryArrayOp(q *protocol.ArrayOp, limit int) ([]*protocol.Triple, error) {
-	query := ArrayOpToSQL(q)
-	args := make([]interface{}, len(query)-1)
-	for i, arg := range query[1:] {
-		args[i] = arg
+	// Retracted and replaced triples are only left in the history. The
+	// changes to each triple are streamed together so comparing with the
+	// previous one is enough to spill each triple once.
+	forget, err := newKeySpill()
+	if err != nil {
+		return count, err
+	}
+	defer forget.close()
+	last := ""
+	changes, errs := ts.EachChangeBatch(DefaultTripleBatchSize)
+	for batch := range changes {
+		for _, change := range batch {
+			triple := change.Triple
+			if triple == nil {
+				triple = tombstoneTriple(change.Tombstone)
+			}
+			key := tripleKey(triple)
+			if key == last {
+				continue
+			}
+			last = key
+			if spillErr == nil && !keyspace.Includes(hash(triple)) {
+				spillErr = forget.add(triple)
+			}
+		}
 	}
-	dbq := ts.db.Where(query[0], args...)
-	if limit > 0 {
-		dbq = dbq.Limit(limit)
+	for err := range errs {
+		return count, err
 	}
-	var results []*protocol.Triple
-	if err := dbq.Find(&results).Error; err != nil {
-		return nil, err
+	if spillErr != nil {
+		return count, spillErr
+	}
+	forgotten := 0
+	err = forget.each(DefaultTripleBatchSize, func(batch []*protocol.Triple) error {
+		forgotten += ts.Forget(batch)
+		return nil
+	})
+	if err != nil {
+		return count, err
 	}
-	return results, nil
+
+	if count == 0 && forgotten == 0 {
+		return 0, nil
+	}
+	return count, ts.Compact()
+}
+
+// MatchTriple returns whether the triple has the fields set on query, like the
+// SQL from TripleToSQL.
+func MatchTriple(query, triple *protocol.Triple) bool {
+	return (len(query.Subj) == 0 || query.Subj == triple.Subj) &&
+		(len(query.Pred) == 0 || query.Pred == triple.Pred) &&
+		(len(query.Obj) == 0 || query.Obj == triple.Obj) &&
+		(len(query.Lang) == 0 || query.Lang == triple.Lang) &&
+		(len(query.Author) == 0 || query.Author == triple.Author)
 }
 
-func ArrayOpToSQL(q *protocol.ArrayOp) []string {
-	var rules []string
-	args := []string{""}
+// MatchArrayOp returns whether the triple matches the ArrayOp, like the SQL
+// from ArrayOpToSQL.
+func MatchArrayOp(q *protocol.ArrayOp, triple *protocol.Triple) bool {
 	switch q.Mode {
 	case protocol.AND, protocol.OR:
-		for _, triple := range q.Triples {
-			sql := TripleToSQL(triple)
-			args = append(args, sql[1:]...)
-			rules = append(rules, sql[0])
+		and := q.Mode == protocol.AND
+		for _, t := range q.Triples {
+			if MatchTriple(t, triple) != and {
+				return !and
+			}
 		}
 		for _, arrayOp := range q.Arguments {
-			sql := ArrayOpToSQL(arrayOp)
-			args = append(args, sql[1:]...)
-			rules = append(rules, sql[0])
+			if MatchArrayOp(arrayOp, triple) != and {
+				return !and
+			}
 		}
-		mode := protocol.ArrayOp_Mode_name[int32(q.Mode)]
-		args[0] = "(" + strings.Join(rules, ") "+mode+" (") + ")"
+		return and
 	case protocol.NOT:
 		if len(q.Triples) > 0 {
-			args = TripleToSQL(q.Triples[0])
+			return !MatchTriple(q.Triples[0], triple)
 		} else if len(q.Arguments) > 0 {
-			args = ArrayOpToSQL(q.Arguments[0])
-		}
-		args[0] = "NOT (" + args[0] + ")"
-	}
-	return args
-}
-
-func TripleToSQL(triple *protocol.Triple) []string {
-	var rules []string
-	args := []string{""}
-	if len(triple.Subj) > 0 {
-		rules = append(rules, "subj = ?")
-		args = append(args, triple.Subj)
-	}
-	if len(triple.Pred) > 0 {
-		rules = append(rules, "pred = ?")
-		args = append(args, triple.Pred)
-	}
-	if len(triple.Obj) > 0 {
-		rules = append(rules, "obj = ?")
-		args = append(args, triple.Obj)
-	}
-	if len(triple.Lang) > 0 {
-		rules = append(rules, "lang = ?")
-		args = append(args, triple.Lang)
-	}
-	if len(triple.Author) > 0 {
-		rules = append(rules, "author = ?")
-		args = append(args, triple.Author)
-	}
-	args[0] = strings.Join(rules, " AND ")
-	return args
-}
-
-// Insert saves a bunch of triples and returns the number asserted.
-func (ts *TripleStore) Insert(triples []*protocol.Triple) int {
-	count := 0
-	tx := ts.db.Begin()
-	for _, triple := range triples {
-		if err := tx.Create(triple).Error; err != nil {
-			continue
+			return !MatchArrayOp(q.Arguments[0], triple)
 		}
-		count++
-	}
-	if err := tx.Commit().Error; err != nil {
-		return 0
+		return true
 	}
-	return count
+	return false
 }
 
-// Info represents the state of the database.
-type Info struct {
-	Triples, DiskSize, AvailableSpace uint64
+// MatchTombstone returns whether the tombstone has the fields set on query.
+func MatchTombstone(query, t *protocol.Tombstone) bool {
+	return (len(query.Subj) == 0 || query.Subj == t.Subj) &&
+		(len(query.Pred) == 0 || query.Pred == t.Pred) &&
+		(len(query.Obj) == 0 || query.Obj == t.Obj) &&
+		(len(query.Author) == 0 || query.Author == t.Author)
 }
 
-// Size returns an info object about the number of triples and file size of the
-// database.
-func (ts *TripleStore) Size() (*Info, error) {
-	fileInfo, err := os.Stat(ts.dbFile)
-	if err != nil {
-		return nil, err
-	}
-	space := du.NewDiskUsage(ts.dbFile)
-	i := &Info{
-		DiskSize:       uint64(fileInfo.Size()),
-		AvailableSpace: space.Available(),
-	}
-	ts.db.Model(&protocol.Triple{}).Count(&i.Triples)
-
-	return i, nil
+// tombstoneTriple returns the triple retracted by the tombstone.
+func tombstoneTriple(t *protocol.Tombstone) *protocol.Triple {
+	return &protocol.Triple{Subj: t.Subj, Pred: t.Pred, Obj: t.Obj, Author: t.Author}
 }
 
-// EachTripleBatch is used to stream triples from the database in batches of the specified size.
-func (ts *TripleStore) EachTripleBatch(size int) (<-chan []*protocol.Triple, <-chan error) {
-	c := make(chan []*protocol.Triple, 10)
-	cerr := make(chan error, 1)
-
-	go func() {
-		dbq := ts.db.Where(&protocol.Triple{}).Limit(size)
-
-		var triples []*protocol.Triple
-		for i := 0; i == 0 || len(triples) > 0; i++ {
-			triples = triples[0:0]
-			if err := dbq.Offset(i * size).Find(&triples).Error; err != nil {
-				cerr <- err
-				break
-			}
-			if len(triples) > 0 {
-				c <- triples
-			}
-		}
-		close(c)
-		close(cerr)
-	}()
-	return c, cerr
+// tripleKey is the unique key of a triple and of the tombstone retracting it.
+func tripleKey(t *protocol.Triple) string {
+	return t.Subj + "\x00" + t.Pred + "\x00" + t.Obj + "\x00" + t.Author
 }
@@ -1,6 +1,7 @@
 package triplestore
 
 import (
+	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/d4l3k/messagediff"
+	"github.com/spaolacci/murmur3"
 
 	"github.com/degdb/degdb/protocol"
 )
@@ -38,19 +40,14 @@ var testTriples = []*protocol.Triple{
 func TestTripleDuplicates(t *testing.T) {
 	t.Parallel()
 
-	file, err := ioutil.TempFile(os.TempDir(), "triplestore.db")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.Remove(file.Name())
-	db, err := NewTripleStore(file.Name(), log.New(os.Stdout, "", log.Flags()))
-	if err != nil {
-		t.Fatal(err)
-	}
+	forEachBackend(t, testTripleDuplicates)
+}
 
-	db.Insert(testTriples)
+func testTripleDuplicates(t *testing.T, db TripleStore) {
+
+	db.Write(testTriples)
 	// Insert twice to ensure no duplicates.
-	db.Insert(testTriples)
+	db.Write(testTriples)
 
 	info, err := db.Size()
 	if err != nil {
@@ -64,17 +61,12 @@ func TestTripleDuplicates(t *testing.T) {
 func TestTripleStore(t *testing.T) {
 	t.Parallel()
 
-	file, err := ioutil.TempFile(os.TempDir(), "triplestore.db")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.Remove(file.Name())
-	db, err := NewTripleStore(file.Name(), log.New(os.Stdout, "", log.Flags()))
-	if err != nil {
-		t.Fatal(err)
-	}
+	forEachBackend(t, testTripleStore)
+}
+
+func testTripleStore(t *testing.T, db TripleStore) {
 
-	db.Insert(testTriples)
+	db.Write(testTriples)
 
 	testData := []struct {
 		query *protocol.Triple
@@ -251,7 +243,7 @@ func BenchmarkTripleInsert(b *testing.B) {
 			Pred: "some subject! woooooo",
 			Obj:  "toasters are delicious",
 		}
-		db.Insert([]*protocol.Triple{triple})
+		db.Write([]*protocol.Triple{triple})
 	}
 }
 func BenchmarkTripleInsertBatch1000(b *testing.B) {
@@ -274,24 +266,19 @@ func BenchmarkTripleInsertBatch1000(b *testing.B) {
 				Obj:  "toasters are delicious",
 			}
 		}
-		db.Insert(triples)
+		db.Write(triples)
 	}
 }
 
 func TestTripleStoreQueryArrayOp(t *testing.T) {
 	t.Parallel()
 
-	file, err := ioutil.TempFile(os.TempDir(), "triplestore.db")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.Remove(file.Name())
-	db, err := NewTripleStore(file.Name(), log.New(os.Stdout, "", log.Flags()))
-	if err != nil {
-		t.Fatal(err)
-	}
+	forEachBackend(t, testTripleStoreQueryArrayOp)
+}
+
+func testTripleStoreQueryArrayOp(t *testing.T, db TripleStore) {
 
-	db.Insert(testTriples)
+	db.Write(testTriples)
 
 	testData := []struct {
 		query *protocol.ArrayOp
@@ -377,3 +364,262 @@ func TestTripleStoreQueryArrayOp(t *testing.T) {
 		}
 	}
 }
+
+// forEachBackend runs the test against an empty store of each backend.
+func forEachBackend(t *testing.T, test func(*testing.T, TripleStore)) {
+	for _, backend := range Backends {
+		file, err := ioutil.TempFile(os.TempDir(), "triplestore.db")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(file.Name())
+		db, err := Open(backend, file.Name(), log.New(os.Stdout, "", log.Flags()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Run(backend, func(t *testing.T) {
+			test(t, db)
+		})
+	}
+}
+
+func TestEvict(t *testing.T) {
+	t.Parallel()
+
+	forEachBackend(t, testEvict)
+}
+
+func testEvict(t *testing.T, db TripleStore) {
+	db.Write(testTriples)
+	// A retracted triple outside the keyspace is only left in the history.
+	tombstone := &protocol.Tombstone{Subj: "/m/0hume", Pred: "/common/topic/alias", Obj: "Hume City"}
+	db.Erase([]*protocol.Tombstone{tombstone})
+
+	subjHash := func(triple *protocol.Triple) uint64 {
+		return murmur3.Sum64([]byte(triple.Subj))
+	}
+	hash := murmur3.Sum64([]byte("/m/02mjmr"))
+	keyspace := &protocol.Keyspace{Start: hash, End: hash + 1}
+	count, err := Evict(db, keyspace, subjHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Evict(%+v) = %d; not 2", keyspace, count)
+	}
+	triples, err := db.Query(&protocol.Triple{}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff(testTriples[:2], triples); !ok {
+		t.Errorf("Query() after Evict = %#v; diff %s", triples, diff)
+	}
+	info, err := db.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Triples != 2 {
+		t.Errorf("Size() = %#v; not 2 triples", info)
+	}
+
+	changes, err := db.History(&protocol.Triple{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*protocol.Change{{Triple: testTriples[0]}, {Triple: testTriples[1]}}
+	if diff, ok := messagediff.PrettyDiff(want, changes); !ok {
+		t.Errorf("History() after Evict = %#v; diff %s", changes, diff)
+	}
+	tombstones, err := db.Tombstones(&protocol.Tombstone{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tombstones) != 0 {
+		t.Errorf("Tombstones() after Evict = %#v; expected none", tombstones)
+	}
+}
+
+func TestEvictBatches(t *testing.T) {
+	t.Parallel()
+
+	forEachBackend(t, testEvictBatches)
+}
+
+func testEvictBatches(t *testing.T, db TripleStore) {
+	// More triples than fit in one batch.
+	n := DefaultTripleBatchSize*2 + 1
+	var triples []*protocol.Triple
+	for i := 0; i < n; i++ {
+		triples = append(triples, &protocol.Triple{Subj: fmt.Sprintf("/m/%d", i), Pred: "/type/object/name", Obj: "Name"})
+	}
+	db.Write(triples)
+
+	subjHash := func(triple *protocol.Triple) uint64 {
+		return murmur3.Sum64([]byte(triple.Subj))
+	}
+	hash := murmur3.Sum64([]byte("/m/none"))
+	keyspace := &protocol.Keyspace{Start: hash, End: hash + 1}
+	count, err := Evict(db, keyspace, subjHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != n {
+		t.Errorf("Evict(%+v) = %d; not %d", keyspace, count, n)
+	}
+	info, err := db.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Triples != 0 {
+		t.Errorf("Size() = %#v; not 0 triples", info)
+	}
+	last := triples[n-1]
+	changes, err := db.History(&protocol.Triple{Subj: last.Subj})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("History(%q) = %+v; expected it forgotten", last.Subj, changes)
+	}
+}
+
+func TestRetract(t *testing.T) {
+	t.Parallel()
+
+	forEachBackend(t, testRetract)
+}
+
+func testRetract(t *testing.T, db TripleStore) {
+	triples := protocol.CloneTriples(testTriples)
+	for _, triple := range triples {
+		triple.Author = "author"
+	}
+	db.Write(triples)
+
+	tombstones := []*protocol.Tombstone{
+		{Subj: triples[0].Subj, Pred: triples[0].Pred, Obj: triples[0].Obj, Author: "author", Signer: "author"},
+		// Only the author's triples are retracted.
+		{Subj: triples[1].Subj, Pred: triples[1].Pred, Obj: triples[1].Obj, Author: "other", Signer: "other"},
+	}
+	if removed, err := db.Erase(tombstones); err != nil || len(removed) != 1 {
+		t.Errorf("Erase(%+v) = %d, %v; not 1", tombstones, len(removed), err)
+	}
+	// Retracted triples can't be inserted again.
+	if written, _, err := db.Write(triples[:1]); err != nil || len(written) != 0 {
+		t.Errorf("Write(%+v) = %d, %v after Retract; not 0", triples[:1], len(written), err)
+	}
+	out, err := db.Query(&protocol.Triple{}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff(triples[1:], out); !ok {
+		t.Errorf("Query() after Retract = %#v; diff %s", out, diff)
+	}
+
+	out2, err := db.Tombstones(&protocol.Tombstone{Subj: triples[0].Subj})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff(tombstones, out2); !ok {
+		t.Errorf("Tombstones() = %#v; diff %s", out2, diff)
+	}
+	out2, err = db.Tombstones(&protocol.Tombstone{Author: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff(tombstones[1:], out2); !ok {
+		t.Errorf("Tombstones(author) = %#v; diff %s", out2, diff)
+	}
+}
+
+func TestRetractReassert(t *testing.T) {
+	t.Parallel()
+
+	forEachBackend(t, testRetractReassert)
+}
+
+func testRetractReassert(t *testing.T, db TripleStore) {
+	triple := &protocol.Triple{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Barack Obama", Author: "author", Created: 1}
+	db.Write([]*protocol.Triple{triple})
+	tombstone := &protocol.Tombstone{Subj: triple.Subj, Pred: triple.Pred, Obj: triple.Obj, Author: "author", Signer: "author", Created: 2}
+	if removed, err := db.Erase([]*protocol.Tombstone{tombstone}); err != nil || len(removed) != 1 {
+		t.Errorf("Erase() = %d, %v; not 1", len(removed), err)
+	}
+
+	// Assertions older than the tombstone stay retracted.
+	if written, _, err := db.Write([]*protocol.Triple{triple}); err != nil || len(written) != 0 {
+		t.Errorf("Write(%+v) = %d, %v after Retract; not 0", triple, len(written), err)
+	}
+	// A newer assertion asserts the triple again.
+	reassert := *triple
+	reassert.Created = 3
+	if written, _, err := db.Write([]*protocol.Triple{&reassert}); err != nil || len(written) != 1 {
+		t.Errorf("Write(%+v) = %d, %v after Retract; not 1", reassert, len(written), err)
+	}
+	// Replaying the old tombstone doesn't retract it.
+	if removed, err := db.Erase([]*protocol.Tombstone{tombstone}); err != nil || len(removed) != 0 {
+		t.Errorf("Erase(old tombstone) = %d, %v; not 0", len(removed), err)
+	}
+	out, err := db.Query(&protocol.Triple{Subj: triple.Subj}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff([]*protocol.Triple{&reassert}, out); !ok {
+		t.Errorf("Query() after reassert = %#v; diff %s", out, diff)
+	}
+
+	// Retracting it again keeps the newest tombstone.
+	retract := *tombstone
+	retract.Created = 4
+	if removed, err := db.Erase([]*protocol.Tombstone{&retract}); err != nil || len(removed) != 1 {
+		t.Errorf("Erase(new tombstone) = %d, %v; not 1", len(removed), err)
+	}
+	tombstones, err := db.Tombstones(&protocol.Tombstone{Subj: triple.Subj})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff([]*protocol.Tombstone{&retract}, tombstones); !ok {
+		t.Errorf("Tombstones() = %#v; diff %s", tombstones, diff)
+	}
+	changes, err := db.History(&protocol.Triple{Subj: triple.Subj})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 4 {
+		t.Errorf("History() = %#v; expected 4 changes", changes)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	forEachBackend(t, testWrite)
+}
+
+func testWrite(t *testing.T, db TripleStore) {
+	old := &protocol.Triple{Subj: "/m/02mjmr", Pred: "/type/object/name", Obj: "Barack Obama", Author: "author", Created: 1}
+	written, replaced, err := db.Write([]*protocol.Triple{old})
+	if err != nil || len(written) != 1 || len(replaced) != 0 {
+		t.Errorf("Write(%+v) = %+v, %+v, %v; expected it written", old, written, replaced, err)
+	}
+	newer := *old
+	newer.Created = 2
+	written, replaced, err = db.Write([]*protocol.Triple{old, &newer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff([]*protocol.Triple{&newer}, written); !ok {
+		t.Errorf("Write() written = %+v; diff %s", written, diff)
+	}
+	if diff, ok := messagediff.PrettyDiff([]*protocol.Triple{old}, replaced); !ok {
+		t.Errorf("Write() replaced = %+v; diff %s", replaced, diff)
+	}
+
+	removed, err := db.Remove([]*protocol.Triple{old, {Subj: "missing", Pred: "b", Obj: "c", Author: "author"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff, ok := messagediff.PrettyDiff([]*protocol.Triple{&newer}, removed); !ok {
+		t.Errorf("Remove() = %+v; diff %s", removed, diff)
+	}
+}